@@ -0,0 +1,289 @@
+// Package applier applies streams of declarations of any kind, writing the
+// values assigned by the backend back to the files they were read from as
+// soon as each declaration is applied.
+package applier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// Declaration is a declaration that can be validated against its schema.
+type Declaration interface {
+	Validate() error
+}
+
+// Overlay is merged into each declaration of a stream before it is applied,
+// and records the IDs assigned by the backend in place of the stream.
+type Overlay[T any] interface {
+	Apply(i int, declaration T) (T, error)
+	SetServerIDs(i int, applied T) bool
+	Bytes() []byte
+}
+
+// Runner applies declarations of type T.
+type Runner[T Declaration] struct {
+	// Read reads a stream of declarations.
+	Read func(in io.Reader, opts ...resource.ReadOption) (*resource.Stream[T], error)
+	// Name returns the name of a declaration, to identify it in errors.
+	Name func(declaration T) string
+	// Apply applies a declaration whose references are read relative to
+	// baseDir, and returns it as applied.
+	Apply func(ctx context.Context, declaration T, baseDir string) (T, error)
+	// Overlay returns the overlay of the declarations in filename, and the
+	// file it is written to, or nil if there is none. Declarations have no
+	// overlays if it is nil.
+	Overlay func(filename string) (Overlay[T], string, error)
+	// BackupOnOverwrite creates a backup of each file before it is updated.
+	BackupOnOverwrite bool
+	// ReadOpts are the options declarations are read with.
+	ReadOpts []resource.ReadOption
+}
+
+// Output holds where Run writes the values assigned by the backend.
+type Output[T any] struct {
+	// Stream, if not nil, is written the full stream after each declaration
+	// is applied, so that the values assigned by the backend are retained
+	// even if a later declaration fails.
+	Stream io.Writer
+	// Includes, if not nil, updates the files included by declarations
+	// alongside the stream.
+	Includes *file.Overwriters
+	// Overlay, if not nil, is merged into each declaration, and the IDs
+	// assigned by the backend are recorded in it and written to OverlayOut
+	// instead, leaving the stream as it was read.
+	Overlay    Overlay[T]
+	OverlayOut io.Writer
+}
+
+// ValidateFiles validates the declarations in each of filenames against the
+// schema, with their overlays merged. Files that cannot be read are left for
+// ApplyFiles to report.
+func (r Runner[T]) ValidateFiles(filenames []string) error {
+	var errs []error
+	for _, filename := range filenames {
+		if err := r.validateFile(filename); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r Runner[T]) validateFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var ov Overlay[T]
+	if r.Overlay != nil {
+		if ov, _, err = r.Overlay(filename); err != nil {
+			return nil
+		}
+	}
+
+	return r.Validate(filename, filepath.Dir(filename), f, ov)
+}
+
+// Validate validates each declaration read from in against the schema, with
+// ov merged if it is not nil. Declarations that cannot be read are left for
+// Run to report, so that they are counted alongside those that could not be
+// applied.
+func (r Runner[T]) Validate(source string, baseDir string, in io.Reader, ov Overlay[T]) error {
+	stream, err := r.Read(in, append(slices.Clip(r.ReadOpts), resource.WithBaseDir(baseDir))...)
+	if err != nil {
+		return nil
+	}
+
+	var errs []error
+	for i, declaration := range stream.Declarations() {
+		if ov != nil {
+			if declaration, err = ov.Apply(i, declaration); err != nil {
+				continue
+			}
+		}
+
+		if err := declaration.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: declaration %d (%s): %w", source, i+1, r.Name(declaration), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyFiles applies the declarations in each of filenames, returning the
+// combined outcome and the error of each file that was not fully applied.
+func (r Runner[T]) ApplyFiles(ctx context.Context, filenames []string) (Outcome[T], []error) {
+	total := Outcome[T]{}
+	var errs []error
+	for _, filename := range filenames {
+		o, err := r.ApplyFile(ctx, filename)
+		total.Add(o)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return total, errs
+}
+
+// ApplyFile applies the declarations in filename, updating filename, and the
+// files its declarations include, each time a declaration is applied. If the
+// declarations have an overlay, it is updated instead.
+func (r Runner[T]) ApplyFile(ctx context.Context, filename string) (Outcome[T], error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	// This is a read-only open.
+	f, err := os.Open(filename)
+	if err != nil {
+		return Outcome[T]{}, err
+	}
+	defer f.Close()
+
+	if r.Overlay != nil {
+		ov, overlayFilename, err := r.Overlay(filename)
+		if err != nil {
+			return Outcome[T]{Unreadable: 1}, err
+		}
+
+		if ov != nil {
+			L.Info("applying overlay", "source", filename, "overlay", overlayFilename)
+			_, o, err := r.Run(ctx, filename, filepath.Dir(filename), f, Output[T]{
+				Overlay: ov,
+				OverlayOut: &file.LazyOverwriter{
+					Filename:        overlayFilename,
+					DoBackup:        r.BackupOnOverwrite,
+					CreateIfMissing: true,
+					OptionalLogger:  L.With("name", "fileIO"),
+				},
+			})
+			return o, err
+		}
+	}
+
+	_, o, err := r.Run(ctx, filename, filepath.Dir(filename), f, Output[T]{
+		Stream: &file.LazyOverwriter{
+			Filename:       filename,
+			DoBackup:       r.BackupOnOverwrite,
+			OptionalLogger: L.With("name", "fileIO"),
+		},
+		Includes: &file.Overwriters{
+			DoBackup:       r.BackupOnOverwrite,
+			OptionalLogger: L.With("name", "fileIO"),
+		},
+	})
+	return o, err
+}
+
+// Run applies each declaration read from in, writing the values assigned by
+// the backend to out. Files referenced by declarations are read relative to
+// baseDir. The stream is returned so that callers may also write it
+// elsewhere.
+func (r Runner[T]) Run(ctx context.Context, source string, baseDir string, in io.Reader, out Output[T]) (*resource.Stream[T], Outcome[T], error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Info("reading in desired declarations", "source", source)
+	stream, err := r.Read(in, append(slices.Clip(r.ReadOpts), warnDeprecations(ctx, source), resource.WithBaseDir(baseDir))...)
+	if err != nil {
+		return nil, Outcome[T]{Unreadable: 1}, fmt.Errorf("%s: %w", source, err)
+	}
+
+	o := Outcome[T]{}
+	var errs []error
+	for i, declaration := range stream.Declarations() {
+		if out.Overlay != nil {
+			overlaid, err := out.Overlay.Apply(i, declaration)
+			if err != nil {
+				L.Error("unable to apply overlay", "source", source, "index", i, "name", r.Name(declaration), "error", err)
+				errs = append(errs, fmt.Errorf("%s: declaration %d (%s): %w", source, i+1, r.Name(declaration), err))
+				o.Failed++
+				continue
+			}
+			declaration = overlaid
+		}
+
+		applied, err := r.Apply(ctx, declaration, baseDir)
+		if err != nil {
+			L.Error("unable to apply declaration", "source", source, "index", i, "name", r.Name(declaration), "error", err)
+			errs = append(errs, fmt.Errorf("%s: declaration %d (%s): %w", source, i+1, r.Name(declaration), err))
+			o.Failed++
+			continue
+		}
+		o.Applied = append(o.Applied, applied)
+
+		if out.Overlay != nil {
+			if !out.Overlay.SetServerIDs(i, applied) {
+				continue
+			}
+
+			L.Info("Updating overlay with the IDs assigned.", "source", source, "index", i)
+			if _, err := out.OverlayOut.Write(out.Overlay.Bytes()); err != nil {
+				return stream, o, err
+			}
+			continue
+		}
+
+		if err := stream.Replace(i, applied); err != nil {
+			return stream, o, err
+		}
+
+		if err := out.Includes.WriteAll(stream.IncludeUpdates()); err != nil {
+			return stream, o, err
+		}
+
+		if out.Stream == nil {
+			continue
+		}
+
+		L.Info("Updating provided resource declaration.", "source", source, "index", i)
+		if _, err := out.Stream.Write(stream.Bytes()); err != nil {
+			return stream, o, err
+		}
+	}
+
+	return stream, o, errors.Join(errs...)
+}
+
+// warnDeprecations logs warnings about the declarations read from source.
+func warnDeprecations(ctx context.Context, source string) resource.ReadOption {
+	L := logger.FromContextOrDiscard(ctx)
+	return resource.WithWarningHandler(func(warning string) {
+		L.Warn(warning, "source", source)
+	})
+}
+
+// Outcome holds the declarations that were applied, and counts those that
+// were not.
+type Outcome[T any] struct {
+	Applied    []T
+	Failed     int
+	Unreadable int
+}
+
+// Add adds the declarations and counts of other to o.
+func (o *Outcome[T]) Add(other Outcome[T]) {
+	o.Applied = append(o.Applied, other.Applied...)
+	o.Failed += other.Failed
+	o.Unreadable += other.Unreadable
+}
+
+// Print writes a summary of o to w, naming declarations with noun, and what
+// was done to them with verb, e.g. "applied".
+func (o Outcome[T]) Print(w io.Writer, noun string, verb string) {
+	fmt.Fprintf(w, "%d %s(s) %s, %d failed", len(o.Applied), noun, verb, o.Failed)
+	if o.Unreadable > 0 {
+		fmt.Fprintf(w, ", %d file(s) could not be read", o.Unreadable)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,76 @@
+package applier_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/applier"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var errUnnamed = errors.New("unnamed")
+
+// componentRunner applies component declarations by assigning them an ID
+// derived from their name, and fails those without a name.
+func componentRunner() applier.Runner[*resource.ComponentDeclaration] {
+	return applier.Runner[*resource.ComponentDeclaration]{
+		Read: resource.ReadComponentStream,
+		Name: func(d *resource.ComponentDeclaration) string { return d.Spec.Name },
+		Apply: func(_ context.Context, d *resource.ComponentDeclaration, _ string) (*resource.ComponentDeclaration, error) {
+			if d.Spec.Name == "" {
+				return nil, errUnnamed
+			}
+
+			applied := *d
+			applied.Spec.ID = d.Spec.Name + "-id"
+			return &applied, nil
+		},
+	}
+}
+
+var _ = Describe("Applier", func() {
+	It("should apply every declaration it can, and write back the IDs assigned", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "components.yaml")
+		content := "# first\nkind: Component\nspec:\n  name: first\n---\nkind: Component\nspec:\n  type: Containers\n"
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+
+		o, err := componentRunner().ApplyFile(context.TODO(), filename)
+		Expect(err).To(MatchError(errUnnamed))
+		Expect(err.Error()).To(ContainSubstring("declaration 2"))
+		Expect(o.Applied).To(HaveLen(1))
+		Expect(o.Failed).To(Equal(1))
+
+		b, err := os.ReadFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(HavePrefix("# first\n"))
+		Expect(string(b)).To(ContainSubstring("_id: first-id"))
+		Expect(string(b)).To(HaveSuffix("---\nkind: Component\nspec:\n  type: Containers\n"))
+	})
+
+	It("should count files that cannot be read", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "components.yaml")
+		Expect(os.WriteFile(filename, []byte("kind: [\n"), 0o644)).To(Succeed())
+
+		o, errs := componentRunner().ApplyFiles(context.TODO(), []string{filename})
+		Expect(errs).To(HaveLen(1))
+		Expect(o.Unreadable).To(Equal(1))
+	})
+
+	It("should validate every declaration before reporting", func() {
+		err := componentRunner().Validate("test", "", bytes.NewBufferString("kind: Component\nspec:\n  type: not a type\n"), nil)
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(HavePrefix("test: declaration 1"))
+	})
+
+	It("should summarize outcomes", func() {
+		out := &bytes.Buffer{}
+		applier.Outcome[*resource.ComponentDeclaration]{Applied: make([]*resource.ComponentDeclaration, 3), Failed: 1, Unreadable: 2}.Print(out, "declaration", "applied")
+		Expect(out.String()).To(Equal("3 declaration(s) applied, 1 failed, 2 file(s) could not be read\n"))
+	})
+})
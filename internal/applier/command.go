@@ -0,0 +1,172 @@
+package applier
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// Command is an apply command for declarations of type T. It holds what
+// differs between the apply commands of each kind of declaration.
+type Command[T Declaration] struct {
+	// Runner returns the runner applying declarations with client, merging
+	// the selected overlay of each declaration file into its declarations.
+	Runner func(client graphql.Client, backupOnOverwrite bool, overlaySelection cli.OverlaySelection, readOpts ...resource.ReadOption) Runner[T]
+	// Columns render the applied declarations as a table.
+	Columns *printer.Columns[T]
+	// Kind is the kind of the declarations applied. Files found in
+	// directories that hold no declaration of this kind are skipped.
+	Kind string
+	// Noun names the declarations in summaries.
+	Noun string
+	// Verb describes what Runner does to declarations in summaries. It
+	// defaults to "applied".
+	Verb string
+
+	// ErrApplyIncomplete is returned if any declaration was not applied.
+	ErrApplyIncomplete error
+	// ErrValidationFailed is returned if any declaration is invalid, in
+	// which case nothing is applied. Declarations are only validated by
+	// commands with a --validate flag.
+	ErrValidationFailed error
+	// ErrOverlayRequiresFiles is returned if an overlay is chosen for
+	// declarations read from stdin.
+	ErrOverlayRequiresFiles error
+}
+
+// RunE applies the declarations read from the files and directories named by
+// args, or from stdin if args is "-", with the flags of cmd. If cmd has a
+// --validate flag, every declaration is validated before any is applied,
+// unless --validate=false is set.
+func (c Command[T]) RunE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, c.Columns)
+	if err != nil {
+		return err
+	}
+	printApplied := cmd.Flags().Changed(cli.FlagIDOutput)
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	variables, err := cli.Variables(cmd)
+	if err != nil {
+		return err
+	}
+
+	readOpts := []resource.ReadOption{resource.WithStrict(cfg.Strict), resource.WithVariables(variables)}
+	validateFirst, _ := cmd.Flags().GetBool(cli.FlagIDValidate)
+	overlay := cli.SelectedOverlay(cmd, cfg)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	backupOnOverwrite, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
+	r := c.Runner(client, backupOnOverwrite, overlay, readOpts...)
+
+	if len(args) == 1 && args[0] == "-" {
+		if overlay.Explicit && overlay.Name != "" {
+			return c.ErrOverlayRequiresFiles
+		}
+
+		in, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+
+		if validateFirst {
+			if err := r.Validate("stdin", "", bytes.NewReader(in), nil); err != nil {
+				return errors.Join(c.ErrValidationFailed, err)
+			}
+		}
+
+		stream, o, err := r.Run(cmd.Context(), "stdin", "", bytes.NewReader(in), Output[T]{})
+		switch {
+		case printApplied:
+			if perr := p.Print(cmd.OutOrStdout(), o.Applied...); perr != nil {
+				return perr
+			}
+		case stream != nil:
+			if _, werr := cmd.OutOrStdout().Write(stream.Bytes()); werr != nil {
+				return werr
+			}
+		}
+		o.Print(cmd.ErrOrStderr(), c.Noun, c.verb())
+		return err
+	}
+
+	recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
+
+	files := []string{}
+	var errs []error
+	for _, arg := range args {
+		found, err := resource.DeclarationFiles(arg, recursive, c.Kind)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		files = append(files, found...)
+	}
+
+	if validateFirst {
+		if err := r.ValidateFiles(files); err != nil {
+			return errors.Join(c.ErrValidationFailed, err)
+		}
+	}
+
+	total, failed := r.ApplyFiles(cmd.Context(), files)
+	errs = append(errs, failed...)
+
+	total.Print(cmd.ErrOrStderr(), c.Noun, c.verb())
+
+	if printApplied {
+		if err := p.Print(cmd.OutOrStdout(), total.Applied...); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{c.ErrApplyIncomplete}, errs...)...)
+	}
+
+	return nil
+}
+
+func (c Command[T]) verb() string {
+	if c.Verb == "" {
+		return "applied"
+	}
+
+	return c.Verb
+}
@@ -0,0 +1,464 @@
+// Package bundle reads and writes bundles: gzipped tarballs holding a product
+// listing declaration, the files it reads from, and where and when it was
+// fetched, along with a SHA-256 manifest of their content.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrInvalidBundle    = errors.New("invalid bundle")
+	ErrManifestMismatch = errors.New("bundle content does not match its manifest")
+	ErrOutsideBundle    = errors.New("the file is outside the bundle")
+)
+
+const (
+	// MetadataFilename is the name of the bundle's metadata within it.
+	MetadataFilename = "bundle.yaml"
+	// ManifestFilename is the name of the bundle's manifest within it, which
+	// holds the SHA-256 digest of every other file in the format of
+	// sha256sum, so that an extracted bundle is verified with sha256sum -c.
+	ManifestFilename = "SHA256SUMS"
+	// filesDir is the directory of the bundle holding the declaration and
+	// the files it reads from.
+	filesDir = "files"
+	// valuesDir is the directory of the bundle holding the values of the
+	// variables the declaration references.
+	valuesDir = "values"
+)
+
+// Metadata describes the declaration a bundle holds.
+type Metadata struct {
+	// Declaration is the path of the declaration among the bundle's files.
+	Declaration string `json:"declaration"`
+	// Overlay is the name of the overlay of the declaration, which holds its
+	// IDs in SourceEnv. The overlay is among the bundle's files if it
+	// existed when the bundle was created.
+	Overlay string `json:"overlay,omitempty"`
+	// Values are the names of the bundle's values files, in the order they
+	// were provided, so that later files take precedence.
+	Values []string `json:"values,omitempty"`
+	// Interpolate is set if variables are interpolated into the declaration
+	// even if the bundle holds no values files, from the environment.
+	Interpolate bool `json:"interpolate,omitempty"`
+	// SourceEnv is the catalog API environment the declaration is applied
+	// to.
+	SourceEnv string `json:"source_env"`
+	// ListingID is the ID of the product listing in SourceEnv, if it has
+	// been applied there.
+	ListingID string `json:"listing_id,omitempty"`
+	// FetchedAt is when the product listing was fetched from SourceEnv, and
+	// LastUpdateDate is when it had last been updated at that time.
+	FetchedAt      *time.Time `json:"fetched_at,omitempty"`
+	LastUpdateDate *time.Time `json:"last_update_date,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CreatedBy      string     `json:"created_by"`
+}
+
+// Bundle is a declaration, the files it reads from, and their metadata.
+type Bundle struct {
+	Metadata Metadata
+	// Files hold the content of the declaration and the files it reads from,
+	// by their slash-separated paths relative to the root of the bundle.
+	Files map[string][]byte
+	// Values hold the content of the values files, by the names listed in
+	// the metadata.
+	Values map[string][]byte
+}
+
+// FromFiles returns a bundle of the declaration in filename and the files it
+// reads from, which are named by the paths they are read from. Each file must
+// be within the directory root, and is bundled by its path relative to root.
+// References to files in the declarations bundled are rewritten relative to
+// the files that hold them, so that the bundle reads nothing outside itself.
+func FromFiles(metadata Metadata, root, filename string, files []string) (*Bundle, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{Metadata: metadata, Files: map[string][]byte{}}
+	for i, name := range append([]string{filename}, files...) {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !within(root, abs) {
+			return nil, fmt.Errorf("%w: %s is not within %s", ErrOutsideBundle, name, root)
+		}
+
+		content, err := os.ReadFile(abs)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 || isYAML(abs) {
+			if content, err = relativeReferences(content, root, filepath.Dir(abs)); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if i == 0 {
+			b.Metadata.Declaration = rel
+		}
+		b.Files[rel] = content
+	}
+
+	return b, nil
+}
+
+// AddValues adds the values files named filenames to b, in order.
+func (b *Bundle) AddValues(filenames []string) error {
+	for _, filename := range filenames {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		if b.Values == nil {
+			b.Values = map[string][]byte{}
+		}
+
+		// Values files are numbered, as files of the same name may be
+		// provided from different directories.
+		name := fmt.Sprintf("%d-%s", len(b.Metadata.Values)+1, filepath.Base(filename))
+		b.Metadata.Values = append(b.Metadata.Values, name)
+		b.Values[name] = content
+	}
+
+	return nil
+}
+
+// Variables returns the lookup of the variables held by the values files of
+// b, where later files take precedence, as with --values, falling back to the
+// environment. It returns nil if variables are not interpolated, i.e. if b
+// holds no values files and Interpolate is not set.
+func (b *Bundle) Variables() (resource.VariableLookup, error) {
+	if len(b.Metadata.Values) == 0 && !b.Metadata.Interpolate {
+		return nil, nil
+	}
+
+	values := resource.Values{}
+	for _, name := range b.Metadata.Values {
+		v, err := resource.ReadValues(bytes.NewReader(b.Values[name]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		maps.Copy(values, v)
+	}
+
+	return resource.ValuesOver(values), nil
+}
+
+// OverlayFile returns the path of the overlay of the declaration among the
+// bundle's files, or an empty string if the bundle has no overlay.
+func (m Metadata) OverlayFile() string {
+	if m.Overlay == "" {
+		return ""
+	}
+
+	return filepath.ToSlash(file.OverlayFilename(filepath.FromSlash(m.Declaration), m.Overlay))
+}
+
+// relativeReferences returns the declarations content, read from dir, with
+// each absolute reference to a file written relative to dir. References to
+// files outside root are refused.
+func relativeReferences(content []byte, root, dir string) ([]byte, error) {
+	return resource.RewriteFileReferences(content, func(name string) (string, error) {
+		abs := filepath.FromSlash(name)
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(dir, abs)
+		}
+
+		if !within(root, abs) {
+			return "", fmt.Errorf("%w: %s is not within %s", ErrOutsideBundle, name, root)
+		}
+
+		if !filepath.IsAbs(name) {
+			return name, nil
+		}
+
+		rel, err := filepath.Rel(dir, abs)
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.ToSlash(rel), nil
+	})
+}
+
+// isYAML returns true if filename is named as a YAML file.
+func isYAML(filename string) bool {
+	ext := filepath.Ext(filename)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// within returns true if filename is within dir.
+func within(dir, filename string) bool {
+	rel, err := filepath.Rel(dir, filename)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// Write writes b to w as a gzipped tarball. The tarball only depends on the
+// content of b, so that the same bundle is always written the same way.
+func (b *Bundle) Write(w io.Writer) error {
+	metadata, err := yaml.Marshal(b.Metadata)
+	if err != nil {
+		return err
+	}
+
+	entries := map[string][]byte{MetadataFilename: metadata}
+	for name, content := range b.Files {
+		entries[path.Join(filesDir, name)] = content
+	}
+	for name, content := range b.Values {
+		entries[path.Join(valuesDir, name)] = content
+	}
+
+	names := slices.Sorted(maps.Keys(entries))
+	var manifest bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&manifest, "%s  %s\n", digest(entries[name]), name)
+	}
+	entries[ManifestFilename] = manifest.Bytes()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, name := range append([]string{ManifestFilename}, names...) {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(entries[name])),
+			ModTime:  b.Metadata.CreatedAt,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(entries[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// Read reads a bundle written by Write from r, verifying that its content
+// matches its manifest.
+func Read(r io.Reader) (*Bundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	defer gz.Close()
+
+	entries := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: %s is not a regular file", ErrInvalidBundle, header.Name)
+		}
+
+		if !validName(header.Name) {
+			return nil, fmt.Errorf("%w: %s is not a relative path within the bundle", ErrInvalidBundle, header.Name)
+		}
+
+		if _, ok := entries[header.Name]; ok {
+			return nil, fmt.Errorf("%w: %s is held more than once", ErrInvalidBundle, header.Name)
+		}
+
+		if entries[header.Name], err = io.ReadAll(tr); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+		}
+	}
+
+	manifest, ok := entries[ManifestFilename]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is missing", ErrInvalidBundle, ManifestFilename)
+	}
+	delete(entries, ManifestFilename)
+
+	if err := verify(manifest, entries); err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{Files: map[string][]byte{}}
+	if err := yaml.UnmarshalStrict(entries[MetadataFilename], &b.Metadata); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBundle, MetadataFilename, err)
+	}
+	delete(entries, MetadataFilename)
+
+	for name, content := range entries {
+		if rel, ok := strings.CutPrefix(name, valuesDir+"/"); ok && slices.Contains(b.Metadata.Values, rel) {
+			if b.Values == nil {
+				b.Values = map[string][]byte{}
+			}
+			b.Values[rel] = content
+			continue
+		}
+
+		rel, ok := strings.CutPrefix(name, filesDir+"/")
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidBundle, name)
+		}
+		b.Files[rel] = content
+	}
+
+	if _, ok := b.Files[b.Metadata.Declaration]; !ok {
+		return nil, fmt.Errorf("%w: the declaration %q is missing", ErrInvalidBundle, b.Metadata.Declaration)
+	}
+
+	for _, name := range b.Metadata.Values {
+		if _, ok := b.Values[name]; !ok {
+			return nil, fmt.Errorf("%w: the values file %q is missing", ErrInvalidBundle, name)
+		}
+	}
+
+	return b, nil
+}
+
+// ReadFile reads the bundle in filename, as Read does.
+func ReadFile(filename string) (*Bundle, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// verify returns an error unless manifest lists the digest of every entry,
+// and nothing else.
+func verify(manifest []byte, entries map[string][]byte) error {
+	listed := map[string]bool{}
+	var errs []error
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return fmt.Errorf("%w: %s: malformed line %q", ErrInvalidBundle, ManifestFilename, scanner.Text())
+		}
+		listed[name] = true
+
+		content, ok := entries[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%s is missing", name))
+		case digest(content) != sum:
+			errs = append(errs, fmt.Errorf("%s has been modified", name))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidBundle, ManifestFilename, err)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		if !listed[name] {
+			errs = append(errs, fmt.Errorf("%s is not in the manifest", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrManifestMismatch}, errs...)...)
+	}
+
+	return nil
+}
+
+// Extract writes the files of b to dir, and returns the path of the
+// declaration within it. The declaration may not read from files outside
+// dir, so that a bundle cannot read files of the system it is applied on.
+func (b *Bundle) Extract(dir string) (string, error) {
+	for name, content := range b.Files {
+		if !validName(name) {
+			return "", fmt.Errorf("%w: %s is not a relative path within the bundle", ErrInvalidBundle, name)
+		}
+
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			return "", err
+		}
+
+		if err := os.WriteFile(filename, content, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	filename := filepath.Join(dir, filepath.FromSlash(b.Metadata.Declaration))
+	files, err := resource.ReferencedFiles(filename)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range files {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return "", err
+		}
+
+		if !within(root, abs) {
+			return "", fmt.Errorf("%w: %s is read from outside the bundle", ErrOutsideBundle, name)
+		}
+	}
+
+	return filename, nil
+}
+
+// validName returns true if name is a clean, relative, slash-separated path
+// that does not leave the directory it is relative to.
+func validName(name string) bool {
+	return name != "" && !path.IsAbs(name) && path.Clean(name) == name && name != ".." && !strings.HasPrefix(name, "../")
+}
+
+// digest returns the hex-encoded SHA-256 digest of content.
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,257 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/bundle"
+)
+
+// entries returns the files of the gzipped tarball b, by name.
+func entries(b []byte) map[string][]byte {
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	Expect(err).ToNot(HaveOccurred())
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		Expect(err).ToNot(HaveOccurred())
+		files[header.Name], err = io.ReadAll(tr)
+		Expect(err).ToNot(HaveOccurred())
+	}
+}
+
+// tarball returns a gzipped tarball of files, by name.
+func tarball(files map[string][]byte) []byte {
+	var out bytes.Buffer
+	gz := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(content))})).To(Succeed())
+		_, err := tw.Write(content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+	Expect(tw.Close()).To(Succeed())
+	Expect(gz.Close()).To(Succeed())
+	return out.Bytes()
+}
+
+var _ = Describe("Bundle", func() {
+	var b *bundle.Bundle
+
+	BeforeEach(func() {
+		created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+		b = &bundle.Bundle{
+			Metadata: bundle.Metadata{
+				Declaration: "product/my.product.yaml",
+				SourceEnv:   "stage",
+				ListingID:   "p123",
+				FetchedAt:   &created,
+				CreatedAt:   created,
+				CreatedBy:   "productctl test",
+			},
+			Files: map[string][]byte{
+				"product/my.product.yaml": []byte("kind: ProductListing\nspec:\n  name: mine\n"),
+				"shared/long.md":          []byte("# Long\n"),
+			},
+		}
+	})
+
+	It("should read the bundle it wrote", func() {
+		var out bytes.Buffer
+		Expect(b.Write(&out)).To(Succeed())
+
+		read, err := bundle.Read(&out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read).To(Equal(b))
+	})
+
+	It("should write the same bundle the same way", func() {
+		var first, second bytes.Buffer
+		Expect(b.Write(&first)).To(Succeed())
+		Expect(b.Write(&second)).To(Succeed())
+		Expect(first.Bytes()).To(Equal(second.Bytes()))
+	})
+
+	It("should write a manifest in the format of sha256sum", func() {
+		var out bytes.Buffer
+		Expect(b.Write(&out)).To(Succeed())
+
+		files := entries(out.Bytes())
+		Expect(files).To(HaveKey("files/shared/long.md"))
+		sum := sha256.Sum256([]byte("# Long\n"))
+		Expect(string(files[bundle.ManifestFilename])).To(ContainSubstring(hex.EncodeToString(sum[:]) + "  files/shared/long.md\n"))
+		Expect(string(files[bundle.ManifestFilename])).To(MatchRegexp(`(?m)^[0-9a-f]{64}  bundle\.yaml$`))
+	})
+
+	When("the bundle has been tampered with", func() {
+		var files map[string][]byte
+
+		BeforeEach(func() {
+			var out bytes.Buffer
+			Expect(b.Write(&out)).To(Succeed())
+			files = entries(out.Bytes())
+		})
+
+		It("should refuse modified files", func() {
+			files["files/shared/long.md"] = []byte("# Longer\n")
+			_, err := bundle.Read(bytes.NewReader(tarball(files)))
+			Expect(err).To(MatchError(bundle.ErrManifestMismatch))
+			Expect(err).To(MatchError(ContainSubstring("files/shared/long.md has been modified")))
+		})
+
+		It("should refuse missing files", func() {
+			delete(files, "files/shared/long.md")
+			_, err := bundle.Read(bytes.NewReader(tarball(files)))
+			Expect(err).To(MatchError(ContainSubstring("files/shared/long.md is missing")))
+		})
+
+		It("should refuse files that are not in the manifest", func() {
+			files["files/extra.yaml"] = []byte("extra: true\n")
+			_, err := bundle.Read(bytes.NewReader(tarball(files)))
+			Expect(err).To(MatchError(ContainSubstring("files/extra.yaml is not in the manifest")))
+		})
+
+		It("should refuse bundles without a manifest", func() {
+			delete(files, bundle.ManifestFilename)
+			_, err := bundle.Read(bytes.NewReader(tarball(files)))
+			Expect(err).To(MatchError(bundle.ErrInvalidBundle))
+		})
+
+		It("should refuse paths that leave the bundle", func() {
+			files["../escape"] = []byte("x")
+			_, err := bundle.Read(bytes.NewReader(tarball(files)))
+			Expect(err).To(MatchError(bundle.ErrInvalidBundle))
+		})
+	})
+
+	It("should refuse content that is not a bundle", func() {
+		_, err := bundle.Read(bytes.NewBufferString("kind: ProductListing\n"))
+		Expect(err).To(MatchError(bundle.ErrInvalidBundle))
+	})
+
+	When("bundling files", func() {
+		It("should keep their paths relative to one another", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.MkdirAll(filepath.Join(dir, "product"), 0o755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dir, "shared"), 0o755)).To(Succeed())
+			declaration := filepath.Join(dir, "product", "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "shared", "long.md"), []byte("# Long\n"), 0o644)).To(Succeed())
+
+			bundled, err := bundle.FromFiles(bundle.Metadata{SourceEnv: "stage"}, dir, declaration, []string{filepath.Join(dir, "product", "..", "shared", "long.md")})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundled.Metadata.Declaration).To(Equal("product/my.product.yaml"))
+			Expect(bundled.Files).To(HaveKeyWithValue("shared/long.md", []byte("# Long\n")))
+
+			extracted := GinkgoT().TempDir()
+			filename, err := bundled.Extract(extracted)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(filename).To(Equal(filepath.Join(extracted, "product", "my.product.yaml")))
+			Expect(filepath.Join(extracted, "shared", "long.md")).To(BeAnExistingFile())
+		})
+
+		It("should write absolute references relative to the files that hold them", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.MkdirAll(filepath.Join(dir, "product"), 0o755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dir, "shared"), 0o755)).To(Succeed())
+			long := filepath.Join(dir, "shared", "long.md")
+			declaration := filepath.Join(dir, "product", "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: "+long+"}\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(long, []byte("# Long\n"), 0o644)).To(Succeed())
+
+			bundled, err := bundle.FromFiles(bundle.Metadata{SourceEnv: "stage"}, dir, declaration, []string{long})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bundled.Files["product/my.product.yaml"])).To(ContainSubstring("$file: ../shared/long.md"))
+
+			extracted := GinkgoT().TempDir()
+			_, err = bundled.Extract(extracted)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should refuse files outside the root of the bundle", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.MkdirAll(filepath.Join(dir, "product"), 0o755)).To(Succeed())
+			declaration := filepath.Join(dir, "product", "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: ../long.md}\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "long.md"), []byte("# Long\n"), 0o644)).To(Succeed())
+
+			_, err := bundle.FromFiles(bundle.Metadata{SourceEnv: "stage"}, filepath.Join(dir, "product"), declaration, []string{filepath.Join(dir, "long.md")})
+			Expect(err).To(MatchError(bundle.ErrOutsideBundle))
+
+			_, err = bundle.FromFiles(bundle.Metadata{SourceEnv: "stage"}, filepath.Join(dir, "product"), declaration, nil)
+			Expect(err).To(MatchError(bundle.ErrOutsideBundle))
+		})
+	})
+
+	When("extracting a bundle", func() {
+		It("should refuse declarations that read from files outside of it", func() {
+			b.Files["product/my.product.yaml"] = []byte("kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: /etc/hostname}\n")
+
+			_, err := b.Extract(GinkgoT().TempDir())
+			Expect(err).To(MatchError(bundle.ErrOutsideBundle))
+		})
+	})
+
+	It("should only interpolate variables if it holds values files or is set to", func() {
+		variables, err := b.Variables()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(variables).To(BeNil())
+
+		b.Metadata.Interpolate = true
+		variables, err = b.Variables()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(variables).ToNot(BeNil())
+	})
+
+	When("holding values files", func() {
+		It("should write them, and read them back in order", func() {
+			b.Metadata.Values = []string{"1-values.yaml", "2-values.yaml"}
+			b.Values = map[string][]byte{
+				"1-values.yaml": []byte("A: first\nB: first\n"),
+				"2-values.yaml": []byte("B: second\n"),
+			}
+
+			var out bytes.Buffer
+			Expect(b.Write(&out)).To(Succeed())
+			Expect(entries(out.Bytes())).To(HaveKey("values/1-values.yaml"))
+
+			read, err := bundle.Read(&out)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(read.Values).To(Equal(b.Values))
+
+			variables, err := read.Variables()
+			Expect(err).ToNot(HaveOccurred())
+			value, ok := variables("A")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("first"))
+			value, ok = variables("B")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("second"))
+		})
+
+		It("should refuse bundles missing one", func() {
+			b.Metadata.Values = []string{"1-values.yaml"}
+
+			var out bytes.Buffer
+			Expect(b.Write(&out)).To(Succeed())
+
+			_, err := bundle.Read(&out)
+			Expect(err).To(MatchError(bundle.ErrInvalidBundle))
+		})
+	})
+})
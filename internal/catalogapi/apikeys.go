@@ -0,0 +1,98 @@
+package catalogapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+var ErrAPIKeyNotCreated = errors.New("the backend did not return a newly created API key")
+
+// ListAPIKeys returns the API keys associated with orgID. The key values
+// themselves are never returned by the backend, only their metadata.
+func ListAPIKeys(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+) ([]*genpyxis.APIKeySupportedFields, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if orgID == 0 {
+		return nil, ErrMissingOrgID
+	}
+
+	L.Debug("querying api keys", "orgID", orgID)
+	resp, err := genpyxis.APIKeys(ctx, client, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if gqlErr := resp.Get_key.GetError(); gqlErr != nil {
+		return nil, ParseGraphQLResponseError(gqlErr)
+	}
+
+	return resp.Get_key.GetData(), nil
+}
+
+// CreateAPIKey creates a new API key for orgID with the provided description,
+// and returns the key alongside its metadata. The key value is only ever
+// returned at creation time, so callers must store it.
+func CreateAPIKey(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+	description string,
+) (string, *genpyxis.APIKeySupportedFields, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if orgID == 0 {
+		return "", nil, ErrMissingOrgID
+	}
+
+	L.Debug("creating api key", "orgID", orgID, "description", description)
+	resp, err := genpyxis.NewAPIKey(ctx, client, orgID, description)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if gqlErr := resp.Create_api_key.GetError(); gqlErr != nil {
+		return "", nil, ParseGraphQLResponseError(gqlErr)
+	}
+
+	data := resp.Create_api_key.GetData()
+	if data == nil || data.GetApi_key() == "" || data.GetKey_data() == nil {
+		return "", nil, ErrAPIKeyNotCreated
+	}
+
+	return data.GetApi_key(), data.GetKey_data(), nil
+}
+
+// DeleteAPIKey deletes the API key identified by keyID from orgID.
+func DeleteAPIKey(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+	keyID int,
+) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if orgID == 0 {
+		return ErrMissingOrgID
+	}
+
+	L.Debug("deleting api key", "orgID", orgID, "keyID", keyID)
+	resp, err := genpyxis.DeleteAPIKey(ctx, client, orgID, keyID)
+	if err != nil {
+		return err
+	}
+
+	if gqlErr := resp.Delete_api_key.GetError(); gqlErr != nil {
+		return ParseGraphQLResponseError(gqlErr)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package catalogapi
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 
@@ -16,6 +17,7 @@ import (
 var (
 	ErrMissingName         = errors.New("listing did not have a name and it is required")
 	ErrDetachingComponents = errors.New("unable to detach components from product listing")
+	ErrMissingOrgID        = errors.New("an organization ID is required, set it with --org-id or org-id in your configuration")
 )
 
 // APIEndpoint represents a full URL to a given Catalog API instance.
@@ -50,8 +52,19 @@ func ApplyProduct(
 		L = L.With("operation", "create")
 	}
 
+	// Referenced components are attached, but are managed by their own
+	// declarations.
+	referencedComponentIDs := make([]string, 0, len(declaration.With.ComponentRefs))
+	for i, ref := range declaration.With.ComponentRefs {
+		id := ref.ComponentID()
+		if id == "" {
+			return nil, fmt.Errorf("%w: with.component_refs[%d]", resource.ErrUnresolvedComponentReference, i)
+		}
+		referencedComponentIDs = append(referencedComponentIDs, id)
+	}
+
 	if updateListing {
-		if len(declaration.With.Components) == 0 {
+		if len(declaration.With.Components) == 0 && len(referencedComponentIDs) == 0 {
 			L.Info("declaration enumerated no components. detaching all components from product (if necessary)")
 			resp, err := genpyxis.SetComponentsForProduct(ctx, client, declaration.Spec.ID, []string{})
 			if err != nil {
@@ -70,10 +83,14 @@ func ApplyProduct(
 	newComponents := []genpyxis.CertificationProjectInput{}
 	existingComponents := []genpyxis.CertificationProjectInput{}
 	associatedComponentIDs := []string{}
+	// The declared components, by ID, so that values held only by the
+	// declaration are kept once they are applied.
+	declaredComponents := map[string]*resource.Component{}
+	newDeclaredComponents := []*resource.Component{}
 
 	// Treat components that have IDs on-disk as pre-existing.
 	for _, c := range declaration.With.Components {
-		cInput, err := resource.JSONConvert[genpyxis.CertificationProjectInput](c)
+		cInput, err := resource.ConvertInput[genpyxis.CertificationProjectInput](c)
 		if err != nil {
 			return nil, err
 		}
@@ -81,17 +98,19 @@ func ApplyProduct(
 		if cInput.Id == "" {
 			L.Debug("component lacking id, treating as new", "component", logger.MarshalJSON(cInput))
 			newComponents = append(newComponents, cInput)
+			newDeclaredComponents = append(newDeclaredComponents, c)
 			continue
 		}
 
 		L.Debug("component contained id, assuming pre-existing", "component", logger.MarshalJSON(cInput))
 
 		existingComponents = append(existingComponents, cInput)
+		declaredComponents[cInput.Id] = c
 		associatedComponentIDs = append(associatedComponentIDs, cInput.Id)
 	}
 
 	// We assume components without IDs must be created.
-	for _, newC := range newComponents {
+	for i, newC := range newComponents {
 		L.Debug("creating new component in backend", "component", logger.MarshalJSON(newC))
 
 		// The backend complains if the project_status value isn't set for new
@@ -109,7 +128,9 @@ func ApplyProduct(
 			return nil, ParseGraphQLResponseError(gqlErr)
 		}
 
-		associatedComponentIDs = append(associatedComponentIDs, resp.Create_certification_project.Data.GetId())
+		newID := resp.Create_certification_project.Data.GetId()
+		declaredComponents[newID] = newDeclaredComponents[i]
+		associatedComponentIDs = append(associatedComponentIDs, newID)
 	}
 
 	// existing components need to be applied
@@ -130,12 +151,13 @@ func ApplyProduct(
 		}
 	}
 
+	associatedComponentIDs = append(associatedComponentIDs, referencedComponentIDs...)
 	declaration.Spec.CertProjects = associatedComponentIDs
 	L.Debug("components associated", "components", logger.MarshalJSON(declaration.Spec.CertProjects))
 
 	// Create the ProductListing
 	var input genpyxis.ProductListingInput
-	input, err := resource.JSONConvert[genpyxis.ProductListingInput](declaration.Spec)
+	input, err := resource.ConvertInput[genpyxis.ProductListingInput](declaration.Spec)
 	if err != nil {
 		return nil, err
 	}
@@ -192,10 +214,15 @@ func ApplyProduct(
 	L.Debug("updating manifest with updated component metadata")
 	newComponentResources := make([]*resource.Component, 0, len(updatedComponents))
 	for _, updatedC := range updatedComponents {
+		if slices.Contains(referencedComponentIDs, updatedC.Id) {
+			continue
+		}
+
 		converted, err := resource.JSONConvert[resource.Component](updatedC)
 		if err != nil {
 			return nil, err
 		}
+		converted.KeepDeclared(declaredComponents[updatedC.Id])
 		newComponentResources = append(newComponentResources, &converted)
 	}
 
@@ -26,7 +26,9 @@ func TokenAuthenticatedHTTPClient(
 	token string,
 	logger *slog.Logger,
 ) *http.Client {
-	httpClient := http.DefaultClient
+	// A new client is used (as opposed to http.DefaultClient) so that clients
+	// built with different tokens can be used at the same time.
+	httpClient := &http.Client{}
 
 	httpClient.Transport = buildTransport(
 		http.DefaultTransport,
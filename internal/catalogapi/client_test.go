@@ -42,6 +42,18 @@ var _ = Describe("Client", func() {
 			})
 		})
 
+		When("clients are built with different tokens", func() {
+			It("should not share a token", func() {
+				first := catalogapi.TokenAuthenticatedHTTPClient(testToken, testLogger)
+				_ = catalogapi.TokenAuthenticatedHTTPClient("other-token", testLogger)
+				req, err := http.NewRequest(http.MethodGet, testServer.URL, bytes.NewBuffer([]byte("testRequest")))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = first.Do(req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(req.Header.Get("X-API-KEY")).To(Equal(testToken))
+			})
+		})
+
 		It("should have the appropriate user agent configured", func() {
 			client := catalogapi.TokenAuthenticatedHTTPClient(testToken, testLogger)
 			req, err := http.NewRequest(http.MethodGet, testServer.URL, bytes.NewBuffer([]byte("testRequest")))
@@ -0,0 +1,99 @@
+package catalogapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrMissingComponentName = errors.New("component did not have a name and it is required")
+
+// ApplyComponent will update an existing component if it exists (identified by
+// the presence of an ID) or create the component if it does not already
+// exist. The component is returned as stored by the backend.
+func ApplyComponent(
+	ctx context.Context,
+	client graphql.Client,
+	declaration *resource.ComponentDeclaration,
+) (*resource.ComponentDeclaration, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if declaration.Spec.Name == "" {
+		return nil, ErrMissingComponentName
+	}
+
+	input, err := resource.ConvertInput[genpyxis.CertificationProjectInput](declaration.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *genpyxis.MutateComponentCommonResponse
+	if input.Id == "" {
+		L.Debug("creating new component in backend", "component", logger.MarshalJSON(input))
+
+		// The backend complains if the project_status value isn't set for new
+		// components, so we'll set it if the user hasn't.
+		if input.Project_status == "" {
+			input.Project_status = "active"
+		}
+
+		resp, err := genpyxis.NewComponent(ctx, client, &input)
+		if err != nil {
+			return nil, err
+		}
+		response = resp.GetCreate_certification_project()
+	} else {
+		L.Debug("applying pre-existing component's configuration", "name", input.Name, "id", input.Id)
+		resp, err := genpyxis.ApplyComponent(ctx, client, input.Id, &input)
+		if err != nil {
+			return nil, err
+		}
+		response = resp.GetUpdate_certification_project()
+	}
+
+	if gqlErr := response.GetError(); gqlErr != nil {
+		return nil, ParseGraphQLResponseError(gqlErr)
+	}
+
+	applied := resource.NewComponentDeclaration()
+	applied.Spec, err = resource.JSONConvert[resource.Component](response.GetData())
+	if err != nil {
+		return nil, err
+	}
+	applied.Spec.KeepDeclared(&declaration.Spec)
+
+	return &applied, nil
+}
+
+// PopulateComponent will return a ComponentDeclaration for the provided
+// componentID.
+func PopulateComponent(
+	ctx context.Context,
+	client graphql.Client,
+	componentID string,
+) (*resource.ComponentDeclaration, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("querying component by ID", "componentID", componentID)
+	resp, err := genpyxis.ComponentByID(ctx, client, componentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if gqlErr := resp.Get_certification_project.GetError(); gqlErr != nil {
+		return nil, ParseGraphQLResponseError(gqlErr)
+	}
+
+	component := resource.NewComponentDeclaration()
+	component.Spec, err = resource.JSONConvert[resource.Component](resp.GetGet_certification_project().GetData())
+	if err != nil {
+		return nil, err
+	}
+
+	return &component, nil
+}
@@ -0,0 +1,223 @@
+package catalogapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// cannedClient answers each operation with the response registered for it,
+// recording the variables of each request.
+type cannedClient struct {
+	responses map[string]string
+	requests  map[string][]any
+}
+
+func (c *cannedClient) MakeRequest(_ context.Context, req *graphql.Request, resp *graphql.Response) error {
+	if c.requests == nil {
+		c.requests = map[string][]any{}
+	}
+	c.requests[req.OpName] = append(c.requests[req.OpName], req.Variables)
+
+	body, ok := c.responses[req.OpName]
+	if !ok {
+		return errors.New("unexpected operation " + req.OpName)
+	}
+
+	return json.Unmarshal([]byte(body), resp.Data)
+}
+
+var _ = Describe("Component", func() {
+	var client *cannedClient
+
+	BeforeEach(func() {
+		client = &cannedClient{responses: map[string]string{
+			"NewComponent":   `{"create_certification_project":{"data":{"_id":"new-id","name":"base-image","type":"Containers"}}}`,
+			"ApplyComponent": `{"update_certification_project":{"data":{"_id":"existing-id","name":"base-image","type":"Containers"}}}`,
+			"ComponentByID":  `{"get_certification_project":{"data":{"_id":"existing-id","name":"base-image","type":"Containers"}}}`,
+		}}
+	})
+
+	When("applying a component", func() {
+		It("should create components without an ID", func() {
+			declaration := resource.NewComponentDeclaration()
+			declaration.Spec.Name = "base-image"
+
+			applied, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied.Kind).To(Equal(resource.KindComponent))
+			Expect(applied.Spec.ID).To(Equal("new-id"))
+			Expect(client.requests).To(HaveKey("NewComponent"))
+			Expect(client.requests).ToNot(HaveKey("ApplyComponent"))
+		})
+
+		It("should update components with an ID", func() {
+			declaration := resource.NewComponentDeclaration()
+			declaration.Spec.Name = "base-image"
+			declaration.Spec.ID = "existing-id"
+
+			applied, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied.Spec.ID).To(Equal("existing-id"))
+			Expect(client.requests).To(HaveKey("ApplyComponent"))
+			Expect(client.requests).ToNot(HaveKey("NewComponent"))
+		})
+
+		It("should send and read the details of non-container and OpenStack components", func() {
+			client.responses["NewComponent"] = `{"create_certification_project":{"data":{"_id":"new-id","name":"driver","type":"OpenStack-infra-noncontainer","non_container":{"source_system_id":"abc123"},"openstack":{"service":"cinder"},"redhat":{"product_name":"Red Hat OpenStack Platform","product_version_id":17},"self_certification":{"requested":true}}}}`
+			declaration := resource.NewComponentDeclaration()
+			declaration.Spec = resource.Component{
+				Name:         "driver",
+				Type:         resource.ComponentTypeOpenStackInfraNonContainer,
+				NonContainer: &resource.NonContainerComponent{SourceSystemID: "abc123"},
+				OpenStack:    &resource.OpenStackComponent{Service: "cinder"},
+				RedHat:       &resource.RedHatComponent{ProductName: "Red Hat OpenStack Platform", ProductVersionID: 17},
+			}
+
+			applied, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+
+			sent, err := json.Marshal(client.requests["NewComponent"][0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(ContainSubstring(`"non_container":{"source_system_id":"abc123"}`))
+			Expect(sent).To(ContainSubstring(`"openstack":{"service":"cinder"}`))
+			Expect(sent).To(ContainSubstring(`"product_version_id":17`))
+
+			Expect(applied.Spec.NonContainer).To(Equal(&resource.NonContainerComponent{SourceSystemID: "abc123"}))
+			Expect(applied.Spec.OpenStack).To(Equal(&resource.OpenStackComponent{Service: "cinder"}))
+			Expect(applied.Spec.RedHat.ProductVersionID).To(Equal(17))
+			Expect(applied.Spec.SelfCertification.Requested).To(HaveValue(BeTrue()))
+		})
+
+		It("should not send whether a chart is signed, as the backend has no such field", func() {
+			declaration := resource.NewComponentDeclaration()
+			declaration.Spec = resource.Component{
+				Name:      "chart",
+				Type:      resource.ComponentTypeHelmChart,
+				HelmChart: &resource.HelmChartComponent{ChartName: "mine", PublicPGPKey: "a2V5", Signed: true},
+			}
+
+			_, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+
+			sent, err := json.Marshal(client.requests["NewComponent"][0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(ContainSubstring(`"public_pgp_key":"a2V5"`))
+			Expect(sent).ToNot(ContainSubstring(`"signed"`))
+		})
+
+		It("should keep whether a chart is signed through apply and write-back", func() {
+			client.responses["NewComponent"] = `{"create_certification_project":{"data":{"_id":"new-id","name":"chart","type":"Helm Chart","helm_chart":{"chart_name":"mine","distribution_method":"redhat","public_pgp_key":"a2V5"}}}}`
+			stream, err := resource.ReadComponentStream(strings.NewReader(`kind: Component
+spec:
+  name: chart
+  type: Helm Chart
+  helm_chart:
+    chart_name: mine
+    distribution_method: redhat
+    public_pgp_key: a2V5
+    signed: true
+`))
+			Expect(err).ToNot(HaveOccurred())
+
+			applied, err := catalogapi.ApplyComponent(context.TODO(), client, stream.Declarations()[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.Replace(0, applied)).To(Succeed())
+
+			reread, err := resource.ReadComponentStream(bytes.NewReader(stream.Bytes()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reread.Declarations()[0].Spec.ID).To(Equal("new-id"))
+			Expect(reread.Declarations()[0].Spec.HelmChart.Signed).To(BeTrue())
+		})
+
+		It("should require a name", func() {
+			declaration := resource.NewComponentDeclaration()
+			_, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).To(MatchError(catalogapi.ErrMissingComponentName))
+		})
+
+		It("should report errors returned by the backend", func() {
+			client.responses["NewComponent"] = `{"create_certification_project":{"error":{"status":400,"detail":"bad component"}}}`
+			declaration := resource.NewComponentDeclaration()
+			declaration.Spec.Name = "base-image"
+
+			_, err := catalogapi.ApplyComponent(context.TODO(), client, &declaration)
+			Expect(err).To(MatchError(ContainSubstring("bad component")))
+		})
+	})
+
+	It("should populate a component by ID", func() {
+		component, err := catalogapi.PopulateComponent(context.TODO(), client, "existing-id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(component.Kind).To(Equal(resource.KindComponent))
+		Expect(component.Spec.Name).To(Equal("base-image"))
+	})
+
+	It("should populate every detail of Helm chart components", func() {
+		client.responses["ComponentByID"] = `{"get_certification_project":{"data":{"_id":"chart-id","name":"chart","type":"Helm Chart","helm_chart":{"chart_name":"mine","distribution_method":"redhat","namespace":"my-org","ocp_versions":["4.14"],"public_pgp_key":"a2V5","github_pull_request":"https://github.com/openshift-helm-charts/charts/pull/1","distribution_instructions":"helm repo add"}}}}`
+
+		component, err := catalogapi.PopulateComponent(context.TODO(), client, "chart-id")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(component.Spec.HelmChart).To(Equal(&resource.HelmChartComponent{
+			ChartName:                "mine",
+			DistributionMethod:       resource.HelmChartDistributionMethodRedHat,
+			Namespace:                "my-org",
+			OCPVersions:              []string{"4.14"},
+			PublicPGPKey:             "a2V5",
+			GitHubPullRequest:        "https://github.com/openshift-helm-charts/charts/pull/1",
+			DistributionInstructions: "helm repo add",
+		}))
+	})
+
+	When("applying a product listing referencing components", func() {
+		BeforeEach(func() {
+			client.responses["NewProductListing"] = `{"create_product_listing":{"data":{"_id":"listing-id","name":"listing","cert_projects":["new-id","referenced-id"]}}}`
+			client.responses["ComponentsForListing"] = `{"find_product_listing_certification_projects":{"data":[{"_id":"new-id","name":"embedded"},{"_id":"referenced-id","name":"shared"}],"total":2}}`
+		})
+
+		It("should attach referenced components without embedding them", func() {
+			declaration := resource.NewProductListing()
+			declaration.Spec.Name = "listing"
+			declaration.With.Components = []*resource.Component{{Name: "embedded"}}
+			declaration.With.ComponentRefs = []*resource.ComponentReference{{ID: "referenced-id"}}
+
+			applied, err := catalogapi.ApplyProduct(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied.Spec.CertProjects).To(ConsistOf("new-id", "referenced-id"))
+			Expect(applied.With.Components).To(HaveLen(1))
+			Expect(applied.With.Components[0].ID).To(Equal("new-id"))
+			Expect(applied.With.ComponentRefs).To(HaveLen(1))
+		})
+
+		It("should keep whether embedded charts are signed", func() {
+			client.responses["ComponentsForListing"] = `{"find_product_listing_certification_projects":{"data":[{"_id":"new-id","name":"embedded","helm_chart":{"chart_name":"mine"}}],"total":1}}`
+			declaration := resource.NewProductListing()
+			declaration.Spec.Name = "listing"
+			declaration.With.Components = []*resource.Component{{Name: "embedded", HelmChart: &resource.HelmChartComponent{ChartName: "mine", Signed: true}}}
+
+			applied, err := catalogapi.ApplyProduct(context.TODO(), client, &declaration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied.With.Components).To(HaveLen(1))
+			Expect(applied.With.Components[0].HelmChart.Signed).To(BeTrue())
+		})
+
+		It("should refuse unresolved references", func() {
+			declaration := resource.NewProductListing()
+			declaration.Spec.Name = "listing"
+			declaration.With.ComponentRefs = []*resource.ComponentReference{{File: "shared.yaml"}}
+
+			_, err := catalogapi.ApplyProduct(context.TODO(), client, &declaration)
+			Expect(err).To(MatchError(resource.ErrUnresolvedComponentReference))
+			Expect(client.requests).To(BeEmpty())
+		})
+	})
+})
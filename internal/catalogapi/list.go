@@ -0,0 +1,76 @@
+package catalogapi
+
+import (
+	"context"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+// ListProducts returns all product listings for orgID that have not been
+// deleted, most recently updated first.
+func ListProducts(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+) ([]*genpyxis.ProductListingSupportedFields, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if orgID == 0 {
+		return nil, ErrMissingOrgID
+	}
+
+	L.Debug("querying product listings for organization", "orgID", orgID)
+	return QueryAll(
+		ctx,
+		0,
+		DefaultPageSize,
+		func(page, pageSize int) (returnedItems []*genpyxis.ProductListingSupportedFields, totalItems int, queryError error) {
+			resp, err := genpyxis.MyProducts(ctx, client, orgID, page, pageSize)
+			if err != nil {
+				return nil, -10, err
+			}
+
+			if gqlErr := resp.Find_product_listings.GetError(); gqlErr != nil {
+				return nil, -10, ParseGraphQLResponseError(gqlErr)
+			}
+
+			return resp.GetFind_product_listings().GetData(), resp.GetFind_product_listings().GetTotal(), nil
+		},
+	)
+}
+
+// ListComponents returns all components for orgID that have not been
+// archived, most recently updated first.
+func ListComponents(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+) ([]*genpyxis.ComponentSupportedFields, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if orgID == 0 {
+		return nil, ErrMissingOrgID
+	}
+
+	L.Debug("querying components for organization", "orgID", orgID)
+	return QueryAll(
+		ctx,
+		0,
+		DefaultPageSize,
+		func(page, pageSize int) (returnedItems []*genpyxis.ComponentSupportedFields, totalItems int, queryError error) {
+			resp, err := genpyxis.MyProjects(ctx, client, orgID, page, pageSize)
+			if err != nil {
+				return nil, -10, err
+			}
+
+			if gqlErr := resp.Find_vendor_certification_projects_by_org_id.GetError(); gqlErr != nil {
+				return nil, -10, ParseGraphQLResponseError(gqlErr)
+			}
+
+			return resp.GetFind_vendor_certification_projects_by_org_id().GetData(), resp.GetFind_vendor_certification_projects_by_org_id().GetTotal(), nil
+		},
+	)
+}
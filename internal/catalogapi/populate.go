@@ -0,0 +1,59 @@
+package catalogapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// DefaultConcurrency is the default number of product listings populated at
+// the same time by PopulateProducts.
+const DefaultConcurrency = 4
+
+// PopulateResult is the outcome of populating a single product listing.
+type PopulateResult struct {
+	ListingID   string
+	Declaration *resource.ProductListingDeclaration
+	Err         error
+}
+
+// PopulateProducts calls PopulateProduct for each of listingIDs, with up to
+// concurrency calls in flight at a time. A result is returned for every
+// listing ID, in the same order as listingIDs. A failure to populate one
+// listing does not prevent others from being populated, so callers must check
+// each result's Err.
+func PopulateProducts(
+	ctx context.Context,
+	client graphql.Client,
+	listingIDs []string,
+	concurrency int,
+) []PopulateResult {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	L.Debug("populating product listings", "count", len(listingIDs), "concurrency", concurrency)
+	results := make([]PopulateResult, len(listingIDs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range listingIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			declaration, err := PopulateProduct(ctx, client, id)
+			results[i] = PopulateResult{ListingID: id, Declaration: declaration, Err: err}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
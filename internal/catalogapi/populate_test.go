@@ -0,0 +1,51 @@
+package catalogapi_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Khan/genqlient/graphql"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+)
+
+// failingClient is a graphql.Client that fails every request.
+type failingClient struct {
+	err error
+}
+
+func (c *failingClient) MakeRequest(_ context.Context, _ *graphql.Request, _ *graphql.Response) error {
+	return c.err
+}
+
+var _ = Describe("Populate", func() {
+	When("populating multiple product listings", func() {
+		var (
+			client     *failingClient
+			listingIDs []string
+		)
+
+		BeforeEach(func() {
+			client = &failingClient{err: errors.New("request failed")}
+			listingIDs = []string{"a", "b", "c", "d", "e"}
+		})
+
+		It("should return a result for every listing ID in the order they were provided", func() {
+			results := catalogapi.PopulateProducts(context.TODO(), client, listingIDs, 2)
+			Expect(results).To(HaveLen(len(listingIDs)))
+			for i, r := range results {
+				Expect(r.ListingID).To(Equal(listingIDs[i]))
+			}
+		})
+
+		It("should report errors for each listing", func() {
+			results := catalogapi.PopulateProducts(context.TODO(), client, listingIDs, 0)
+			for _, r := range results {
+				Expect(r.Err).To(MatchError(client.err))
+				Expect(r.Declaration).To(BeNil())
+			}
+		})
+	})
+})
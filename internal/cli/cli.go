@@ -14,6 +14,10 @@ import (
 
 var ErrAPIEndpointUnknown = errors.New("unknown api endpoint")
 
+// AnnotationOffline marks a command that never contacts the backend, and
+// therefore does not require an API token to be configured.
+const AnnotationOffline = "productctl.opdev.io/offline"
+
 // ConfigureLogger serves as a convenience function for configuring the CLI logger,
 // populating a context with it, and returning it to the user.
 func ConfigureLogger(logLevel string, logTarget io.Writer) (context.Context, *slog.Logger, error) {
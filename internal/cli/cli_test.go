@@ -2,14 +2,20 @@ package cli_test
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
 
 	"github.com/opdev/productctl/internal/catalogapi"
 	"github.com/opdev/productctl/internal/cli"
 	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
 )
 
 var _ = Describe("CLI", func() {
@@ -63,4 +69,67 @@ var _ = Describe("CLI", func() {
 			})
 		})
 	})
+	When("building clients of environments", func() {
+		It("should resolve the endpoint of the environment unless one is provided", func() {
+			_, err := cli.EnvironmentClient(context.TODO(), &cli.UserConfig{}, "foo", "", "")
+			Expect(err).To(MatchError(cli.ErrAPIEndpointUnknown))
+
+			tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(tokenFile, []byte("my-token\n"), 0o600)).To(Succeed())
+			client, err := cli.EnvironmentClient(context.TODO(), &cli.UserConfig{}, "foo", "https://example.com/graphql/", tokenFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client).ToNot(BeNil())
+		})
+
+		It("should report token files that cannot be read", func() {
+			_, err := cli.EnvironmentClient(context.TODO(), &cli.UserConfig{}, "stage", "", filepath.Join(GinkgoT().TempDir(), "missing"))
+			Expect(err).To(MatchError(cli.ErrReadingTokenFile))
+		})
+	})
+
+	When("resolving the variables of declarations", func() {
+		var cmd *cobra.Command
+
+		BeforeEach(func() {
+			cmd = &cobra.Command{}
+			cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+			cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
+			GinkgoT().Setenv("PRODUCTCTL_TEST_VARIABLE", "from the environment")
+		})
+
+		It("should not interpolate unless asked to, leaving a literal ${ as it is", func() {
+			variables, err := cli.Variables(cmd)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(BeNil())
+
+			declaration, err := resource.ReadProductListing(
+				strings.NewReader("kind: ProductListing\nspec:\n  name: ${PRODUCTCTL_TEST_VARIABLE} and ${PRODUCTCTL_TEST_UNDEFINED}\n"),
+				resource.WithVariables(variables),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(declaration.Spec.Name).To(Equal("${PRODUCTCTL_TEST_VARIABLE} and ${PRODUCTCTL_TEST_UNDEFINED}"))
+		})
+
+		It("should interpolate from the environment with --interpolate", func() {
+			Expect(cmd.Flags().Set(cli.FlagIDInterpolate, "true")).To(Succeed())
+
+			variables, err := cli.Variables(cmd)
+			Expect(err).ToNot(HaveOccurred())
+			value, ok := variables("PRODUCTCTL_TEST_VARIABLE")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("from the environment"))
+		})
+
+		It("should prefer values files over the environment", func() {
+			filename := filepath.Join(GinkgoT().TempDir(), "values.yaml")
+			Expect(os.WriteFile(filename, []byte("PRODUCTCTL_TEST_VARIABLE: from values\n"), 0o644)).To(Succeed())
+			Expect(cmd.Flags().Set(cli.FlagIDValues, filename)).To(Succeed())
+
+			variables, err := cli.Variables(cmd)
+			Expect(err).ToNot(HaveOccurred())
+			value, ok := variables("PRODUCTCTL_TEST_VARIABLE")
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("from values"))
+		})
+	})
 })
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+// EnvironmentClient returns a client of the catalog API environment env, e.g.
+// "stage", authenticated with the API key held in tokenFile, or with the
+// configured API key if tokenFile is empty. The client uses endpoint if it is
+// not empty, e.g. a custom endpoint, and the endpoint of env otherwise. It
+// serves commands that use more than one environment at once.
+func EnvironmentClient(ctx context.Context, cfg *UserConfig, env, endpoint, tokenFile string) (graphql.Client, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if endpoint == "" {
+		var err error
+		if endpoint, err = ResolveAPIEndpoint(env); err != nil {
+			return nil, err
+		}
+	}
+
+	var token string
+	if tokenFile != "" {
+		b, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, errors.Join(ErrReadingTokenFile, err)
+		}
+		token = strings.TrimSpace(string(b))
+	} else {
+		var err error
+		if token, err = cfg.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient", "env", env))
+	return graphql.NewClient(endpoint, httpClient), nil
+}
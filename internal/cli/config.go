@@ -52,6 +52,10 @@ type UserConfig struct {
 	APITokenFile string `mapstructure:"api-token-file"`
 	LogLevel     string `mapstructure:"log-level"`
 	Env          string `mapstructure:"env"`
+	OrgID        int    `mapstructure:"org-id"`
+	Strict       bool   `mapstructure:"strict"`
+	// LintDisable holds the IDs of the lint rules that are not checked.
+	LintDisable []string `mapstructure:"lint-disable"`
 
 	configFileSource string
 }
@@ -101,6 +105,7 @@ func initConfig(v *spfviper.Viper) {
 	// Bind them so either the config or the environment can be used.
 	_ = v.BindEnv("api-token")
 	_ = v.BindEnv("api-token-file")
+	_ = v.BindEnv("lint-disable")
 	v.AutomaticEnv()
 
 	v.SetConfigName("config")
@@ -13,4 +13,32 @@ const (
 	FlagIDCustomEndpoint          FlagID = "custom-endpoint"                 // For defining a GraphQL endpoint that isn't predefined.
 	FlagIDCreateBackupOnOverwrite FlagID = "backup-declaration-on-overwrite" // For creating declaration backups before overwriting
 	FlagIDFromDiscoveryJSON       FlagID = "from-discovery-json"             // For providing a discovery input to product listing generation
+	FlagIDOrgID                   FlagID = "org-id"                          // For identifying the organization that owns resources.
+	FlagIDDescription             FlagID = "description"                     // For describing resources like API keys.
+	FlagIDOutput                  FlagID = "output"                          // For choosing the output format of a command.
+	FlagIDNameFilter              FlagID = "name"                            // For filtering listed resources by name.
+	FlagIDTypeFilter              FlagID = "type"                            // For filtering listed resources by type.
+	FlagIDStatusFilter            FlagID = "status"                          // For filtering listed resources by status.
+	FlagIDDir                     FlagID = "dir"                             // For specifying a directory to read or write declarations.
+	FlagIDSanitize                FlagID = "sanitize"                        // For sanitizing declarations before writing them.
+	FlagIDOutputDir               FlagID = "output-dir"                      // For writing declarations to individual files in a directory.
+	FlagIDConcurrency             FlagID = "concurrency"                     // For limiting the number of concurrent API operations.
+	FlagIDRecursive               FlagID = "recursive"                       // For descending into subdirectories when reading declarations.
+	FlagIDStrict                  FlagID = "strict"                          // For rejecting declarations containing unknown fields.
+	FlagIDValidate                FlagID = "validate"                        // For validating declarations against the schema before applying them.
+	FlagIDSchemaURL               FlagID = "schema-url"                      // For referencing the declaration schema from generated declarations.
+	FlagIDKind                    FlagID = "kind"                            // For choosing the kind of declaration a command concerns.
+	FlagIDOverlay                 FlagID = "overlay"                         // For choosing the per-environment overlay applied to declarations.
+	FlagIDValues                  FlagID = "values"                          // For providing values of variables referenced in declarations.
+	FlagIDInterpolate             FlagID = "interpolate"                     // For interpolating variables referenced in declarations from the environment.
+	FlagIDMarkdown                FlagID = "markdown"                        // For writing fields that hold HTML in Markdown.
+	FlagIDDisable                 FlagID = "disable"                         // For disabling lint rules.
+	FlagIDFromEnv                 FlagID = "from-env"                        // For choosing the environment resources are copied from.
+	FlagIDToEnv                   FlagID = "to-env"                          // For choosing the environment resources are copied to.
+	FlagIDFromAPITokenFile        FlagID = "from-api-token-file"             // For authenticating with the environment resources are copied from.
+	FlagIDToAPITokenFile          FlagID = "to-api-token-file"               // For authenticating with the environment resources are copied to.
+	FlagIDFrom                    FlagID = "from"                            // For choosing the environment declarations are promoted from.
+	FlagIDTo                      FlagID = "to"                              // For choosing the environment declarations are promoted to.
+	FlagIDForce                   FlagID = "force"                           // For applying changes despite safety checks.
+	FlagIDDeclaration             FlagID = "declaration"                     // For providing the existing declaration of a fetched resource.
 )
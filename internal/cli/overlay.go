@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// OverlaySelection identifies the overlay applied to each declaration file,
+// chosen with --overlay or otherwise by the active environment.
+type OverlaySelection struct {
+	// Name is the name of the overlay. No overlay is applied if it is empty.
+	Name string
+	// Explicit is set if the overlay was chosen with --overlay.
+	Explicit bool
+}
+
+// SelectedOverlay returns the overlay chosen for cmd. This is the value of
+// --overlay if it was set, and the active environment otherwise.
+func SelectedOverlay(cmd *cobra.Command, cfg *UserConfig) OverlaySelection {
+	if cmd.Flags().Changed(FlagIDOverlay) {
+		name, _ := cmd.Flags().GetString(FlagIDOverlay)
+		return OverlaySelection{Name: name, Explicit: true}
+	}
+
+	return OverlaySelection{Name: cfg.Env}
+}
+
+// Filename returns the name of the selected overlay of the declaration file at
+// filename.
+func (s OverlaySelection) Filename(filename string) string {
+	return file.OverlayFilename(filename, s.Name)
+}
+
+// Read returns the selected overlay of the declaration file at filename, or nil
+// if there is none. An overlay chosen with --overlay that does not exist yet is
+// read as empty, so that it is created once IDs are recorded in it. The overlay
+// is read with opts, as its declarations are.
+func (s OverlaySelection) Read(filename string, opts ...resource.ReadOption) (*resource.Overlay, error) {
+	return readSelected(s, filename, resource.ReadOverlayFile, opts...)
+}
+
+// ReadComponent returns the selected overlay of the component declaration file
+// at filename, as Read does for product listing declaration files.
+func (s OverlaySelection) ReadComponent(filename string, opts ...resource.ReadOption) (*resource.ComponentOverlay, error) {
+	return readSelected(s, filename, resource.ReadComponentOverlayFile, opts...)
+}
+
+func readSelected[O any](
+	s OverlaySelection,
+	filename string,
+	read func(filename string, opts ...resource.ReadOption) (*O, bool, error),
+	opts ...resource.ReadOption,
+) (*O, error) {
+	if s.Name == "" {
+		return nil, nil
+	}
+
+	overlay, exists, err := read(s.Filename(filename), opts...)
+	if err != nil || exists || s.Explicit {
+		return overlay, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"maps"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// ValuesUsage is the usage of --values.
+const ValuesUsage = "A YAML file of values for variables referenced in declarations as ${NAME}. Variables are only interpolated if values are provided, or if --interpolate is set. May be repeated, with later files taking precedence. Values take precedence over environment variables"
+
+// InterpolateUsage is the usage of --interpolate.
+const InterpolateUsage = "Interpolate variables referenced in declarations as ${NAME} from environment variables, even if no --values are provided"
+
+// Variables returns the lookup of variables interpolated into the declarations
+// read by cmd, or nil if variables are not interpolated. Variables are only
+// interpolated if files are provided with --values, or if --interpolate is
+// set. Variables are read from those files, falling back to the environment.
+func Variables(cmd *cobra.Command) (resource.VariableLookup, error) {
+	filenames, _ := cmd.Flags().GetStringArray(FlagIDValues)
+	interpolate, _ := cmd.Flags().GetBool(FlagIDInterpolate)
+	if len(filenames) == 0 && !interpolate {
+		return nil, nil
+	}
+
+	values := resource.Values{}
+	for _, filename := range filenames {
+		v, err := resource.ReadValuesFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(values, v)
+	}
+
+	return resource.ValuesOver(values), nil
+}
@@ -2,108 +2,94 @@ package apply
 
 import (
 	"context"
-	"fmt"
-	"io"
-	"os"
+	"errors"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/yaml"
 
+	"github.com/opdev/productctl/internal/applier"
 	"github.com/opdev/productctl/internal/catalogapi"
 	"github.com/opdev/productctl/internal/cli"
-	"github.com/opdev/productctl/internal/file"
-	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
 	"github.com/opdev/productctl/internal/resource"
 )
 
+var (
+	ErrApplyIncomplete      = errors.New("some declarations could not be applied")
+	ErrValidationFailed     = errors.New("some declarations are invalid, nothing was applied")
+	ErrOverlayRequiresFiles = errors.New("overlays cannot be applied to declarations read from stdin")
+)
+
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "apply <your-declaration.yaml>",
+		Use:   "apply <your-declaration.yaml|directory|-> [...]",
 		Short: "Apply changes to Partner product listings from the input file.",
-		Long:  "Apply changes to partner product listings based on the provided configuration file",
-		Args:  cobra.ExactArgs(1),
-		RunE:  applyProductRunE,
-	}
-
-	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite. Note that this backups the on-disk declaration that was created/applied before overwriting it with new content.")
-
-	return cmd
-}
+		Long: `Apply changes to partner product listings based on the provided configuration file
 
-func applyProductRunE(cmd *cobra.Command, args []string) error {
-	L := logger.FromContextOrDiscard(cmd.Context())
-	cfg, err := cli.Config()
-	if err != nil {
-		return err
-	}
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Each declaration is applied in turn, and its file is updated as soon as it has been applied, leaving the other declarations in that file untouched. A declaration that fails to apply does not prevent the others from being applied. A summary is printed on completion.
 
-	token, err := cfg.Token()
-	if err != nil {
-		return err
-	}
+Use "-" to read declarations from stdin, in which case the updated declarations are written to stdout.
 
-	var endpoint string
-	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
-		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
-		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
-	} else {
-		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
-		if err != nil {
-			return err
-		}
-		L.Debug("endpoint resolved", "endpoint", endpoint)
-	}
+Use --output to print the declarations that were applied to stdout in another format, e.g. -o jsonpath={.spec._id} to print the IDs of the applied product listings.
 
-	if args[0] == "-" {
-		return runApply(cmd.Context(), os.Stdin, os.Stdout, token, endpoint)
-	}
+Every declaration is validated against the product listing schema before anything is applied, as with the validate command. If any declaration is invalid, nothing is applied. Use --validate=false to skip this.
 
-	// This is a read-only open.
-	f, err := os.Open(args[0])
-	if err != nil {
-		return err
+Declarations may be shared between environments using overlays. The overlay of my.product.yaml for the active environment, e.g. my.product.stage.overlay.yaml with --env stage, is merged into each declaration before it is applied, and the IDs assigned by the backend are written to the overlay rather than to the declaration. Use --overlay to choose an overlay by name instead, which is created if it does not exist, or --overlay="" to apply the declarations as they are.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: applier.Command[*resource.ProductListingDeclaration]{
+			Runner:                  runner,
+			Columns:                 printer.DeclarationColumns,
+			Kind:                    resource.KindProductListing,
+			Noun:                    noun,
+			ErrApplyIncomplete:      ErrApplyIncomplete,
+			ErrValidationFailed:     ErrValidationFailed,
+			ErrOverlayRequiresFiles: ErrOverlayRequiresFiles,
+		}.RunE,
 	}
 
-	backupOnOverwrite, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
-
-	updateFileOnSuccess := file.LazyOverwriter{
-		Filename:       args[0],
-		DoBackup:       backupOnOverwrite,
-		OptionalLogger: L.With("name", "fileIO"),
-	}
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite. Note that this backups the on-disk declaration that was created/applied before overwriting it with new content.")
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+	cmd.Flags().Bool(cli.FlagIDValidate, true, "Validate all declarations against the schema before applying any of them")
+	cmd.Flags().String(cli.FlagIDOverlay, "", "The overlay merged into each declaration, which also holds the IDs assigned by the backend. Defaults to the active environment, if its overlay exists")
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
 
-	defer f.Close()
-	return runApply(cmd.Context(), f, &updateFileOnSuccess, token, endpoint)
+	return cmd
 }
 
-func runApply(ctx context.Context, in io.Reader, outOnCompletion io.Writer, token string, endpoint catalogapi.APIEndpoint) error {
-	L := logger.FromContextOrDiscard(ctx)
-
-	L.Info("reading in desired product listing")
-	declaration, err := resource.ReadProductListing(in)
-	if err != nil {
-		return err
-	}
-
-	L.Debug("building graphql client")
-	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
-	client := graphql.NewClient(endpoint, httpClient)
-
-	applied, err := catalogapi.ApplyProduct(ctx, client, declaration)
-	if err != nil {
-		return err
-	}
-
-	L.Info("Updating provided resource declaration.")
-	b, err := yaml.Marshal(applied)
-	if err != nil {
-		return err
-	}
-	_, err = fmt.Fprint(outOnCompletion, string(b))
-	if err != nil {
-		return err
+// noun names product listing declarations in summaries.
+const noun = "declaration"
+
+// runner returns a runner applying product listing declarations with client,
+// merging the selected overlay of each declaration file into its
+// declarations. Components referenced by file are resolved before each
+// declaration is applied.
+func runner(
+	client graphql.Client,
+	backupOnOverwrite bool,
+	overlaySelection cli.OverlaySelection,
+	readOpts ...resource.ReadOption,
+) applier.Runner[*resource.ProductListingDeclaration] {
+	return applier.Runner[*resource.ProductListingDeclaration]{
+		Read: resource.ReadDeclarationStream,
+		Name: func(d *resource.ProductListingDeclaration) string { return d.Spec.Name },
+		Apply: func(ctx context.Context, d *resource.ProductListingDeclaration, baseDir string) (*resource.ProductListingDeclaration, error) {
+			if err := d.ResolveComponentReferences(baseDir, overlaySelection.Name, readOpts...); err != nil {
+				return nil, err
+			}
+
+			return catalogapi.ApplyProduct(ctx, client, d)
+		},
+		Overlay: func(filename string) (applier.Overlay[*resource.ProductListingDeclaration], string, error) {
+			ov, err := overlaySelection.Read(filename, readOpts...)
+			if ov == nil {
+				return nil, "", err
+			}
+
+			return ov, overlaySelection.Filename(filename), err
+		},
+		BackupOnOverwrite: backupOnOverwrite,
+		ReadOpts:          readOpts,
 	}
-
-	return nil
 }
@@ -0,0 +1,252 @@
+package apply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/applier"
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// stubClient answers product listing creation with a new ID, updates with
+// the ID updated, and fails any other operation.
+func stubClient() *testutils.FakeClient {
+	client := &testutils.FakeClient{}
+	client.Responses = map[string]testutils.Response{
+		"NewProductListing": func(map[string]any) (any, error) {
+			return json.RawMessage(fmt.Sprintf(`{"create_product_listing":{"data":{"_id":"created-%d","name":"created"}}}`, client.Count("NewProductListing"))), nil
+		},
+		"SetComponentsForProduct": testutils.JSON(`{"update_product_listing":{"data":{}}}`),
+		"ApplyProductListing": func(variables map[string]any) (any, error) {
+			return json.RawMessage(fmt.Sprintf(`{"update_product_listing":{"data":{"_id":%q,"name":"updated"}}}`, variables["id"])), nil
+		},
+		"ComponentsForListing": testutils.JSON(`{"find_product_listing_certification_projects":{"data":[],"total":0}}`),
+	}
+
+	return client
+}
+
+// echoClient answers the creation of product listings and components with the
+// input it was given and a new ID, as the backend does.
+func echoClient() *testutils.FakeClient {
+	var components []map[string]any
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"NewComponent": func(variables map[string]any) (any, error) {
+			component := variables["new"].(map[string]any)
+			component["_id"] = fmt.Sprintf("component-%d", len(components)+1)
+			components = append(components, component)
+			return map[string]any{"create_certification_project": map[string]any{"data": component}}, nil
+		},
+		"NewProductListing": func(variables map[string]any) (any, error) {
+			listing := variables["new"].(map[string]any)
+			listing["_id"] = "listing-1"
+			return map[string]any{"create_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"ComponentsForListing": func(map[string]any) (any, error) {
+			return map[string]any{"find_product_listing_certification_projects": map[string]any{"data": components, "total": len(components)}}, nil
+		},
+	}}
+}
+
+var _ = Describe("Apply (internal)", func() {
+	const content = `# first listing
+kind: ProductListing
+spec:
+  name:   first
+---
+kind: ProductListing
+spec:
+  description: this one has no name
+---
+kind: ProductListing
+spec:
+  name: third
+`
+
+	It("should apply every declaration it can, and write back only those documents", func() {
+		out := &bytes.Buffer{}
+		var lastWrite []byte
+		writer := writerFunc(func(p []byte) (int, error) {
+			lastWrite = bytes.Clone(p)
+			return out.Write(p)
+		})
+
+		stream, o, err := runner(stubClient(), false, cli.OverlaySelection{}).Run(context.TODO(), "test", "", bytes.NewBufferString(content), applier.Output[*resource.ProductListingDeclaration]{Stream: writer})
+		Expect(err).To(MatchError(catalogapi.ErrMissingName))
+		Expect(err.Error()).To(ContainSubstring("declaration 2"))
+		Expect(o.Applied).To(HaveLen(2))
+		Expect(o.Failed).To(Equal(1))
+		Expect(stream.Bytes()).To(Equal(lastWrite))
+
+		reread, err := resource.ReadDeclarationStream(bytes.NewBuffer(lastWrite))
+		Expect(err).ToNot(HaveOccurred())
+		declarations := reread.Declarations()
+		Expect(declarations).To(HaveLen(3))
+		Expect(declarations[0].Spec.ID).To(Equal("created-1"))
+		Expect(declarations[1].Spec.ID).To(BeEmpty())
+		Expect(string(lastWrite)).To(ContainSubstring("spec:\n  description: this one has no name\n---\n"))
+		Expect(declarations[2].Spec.ID).To(Equal("created-2"))
+	})
+
+	It("should update each file in a directory with the declarations applied", func() {
+		dir := GinkgoT().TempDir()
+		first := filepath.Join(dir, "a.product.yaml")
+		second := filepath.Join(dir, "b.product.yaml")
+		Expect(os.WriteFile(first, []byte(content), 0o644)).To(Succeed())
+		Expect(os.WriteFile(second, []byte("kind: ProductListing\nspec:\n  name: fourth\n"), 0o644)).To(Succeed())
+
+		client := stubClient()
+		o, err := runner(client, true, cli.OverlaySelection{}).ApplyFile(context.TODO(), first)
+		Expect(err).To(HaveOccurred())
+		Expect(o.Applied).To(HaveLen(2))
+		Expect(o.Failed).To(Equal(1))
+
+		o, err = runner(client, false, cli.OverlaySelection{}).ApplyFile(context.TODO(), second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.Applied).To(HaveLen(1))
+		Expect(o.Applied[0].Spec.ID).To(Equal("created-3"))
+		Expect(o.Failed).To(BeZero())
+
+		b, err := os.ReadFile(second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("_id: created-3"))
+
+		backups, err := filepath.Glob(filepath.Join(dir, "*.a.product.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(backups).To(HaveLen(1))
+		b, err = os.ReadFile(backups[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(Equal(content))
+	})
+
+	It("should not apply declarations whose component references cannot be resolved", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "unapplied.component.yaml"), []byte("kind: Component\nspec:\n  name: unapplied\n"), 0o644)).To(Succeed())
+		listing := filepath.Join(dir, "listing.product.yaml")
+		Expect(os.WriteFile(listing, []byte("kind: ProductListing\nspec:\n  name: listing\nwith:\n  component_refs:\n  - file: unapplied.component.yaml\n"), 0o644)).To(Succeed())
+
+		o, err := runner(stubClient(), false, cli.OverlaySelection{}).ApplyFile(context.TODO(), listing)
+		Expect(err).To(MatchError(resource.ErrUnresolvedComponentReference))
+		Expect(err.Error()).To(ContainSubstring("has not been applied"))
+		Expect(o.Applied).To(BeEmpty())
+		Expect(o.Failed).To(Equal(1))
+	})
+
+	It("should never write interpolated values back to the declaration", func() {
+		dir := GinkgoT().TempDir()
+		listing := filepath.Join(dir, "listing.product.yaml")
+		Expect(os.WriteFile(listing, []byte("kind: ProductListing\nspec:\n  name: ${NAME}\n"), 0o644)).To(Succeed())
+
+		variables := resource.Values{"NAME": "listing"}
+		o, err := runner(stubClient(), false, cli.OverlaySelection{}, resource.WithVariables(func(name string) (string, bool) {
+			v, ok := variables[name]
+			return v, ok
+		})).ApplyFile(context.TODO(), listing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.Applied).To(HaveLen(1))
+
+		b, err := os.ReadFile(listing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("_id: created-1"))
+		Expect(string(b)).To(ContainSubstring("name: ${NAME}"))
+	})
+
+	It("should update included files rather than inlining them", func() {
+		dir := GinkgoT().TempDir()
+		listing := filepath.Join(dir, "listing.product.yaml")
+		Expect(os.WriteFile(listing, []byte("kind: ProductListing\nspec:\n  name: listing\n  descriptions:\n    long: {$file: long.html}\nwith:\n  components:\n  - {$file: components/api.yaml}\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "long.html"), []byte("<p>Long.</p>\n"), 0o644)).To(Succeed())
+		Expect(os.Mkdir(filepath.Join(dir, "components"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "components", "api.yaml"), []byte("name: api\ntype: Containers\n"), 0o644)).To(Succeed())
+
+		o, err := runner(echoClient(), false, cli.OverlaySelection{}).ApplyFile(context.TODO(), listing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.Applied).To(HaveLen(1))
+
+		b, err := os.ReadFile(listing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("_id: listing-1"))
+		Expect(string(b)).To(ContainSubstring("long: {$file: long.html}"))
+		Expect(string(b)).To(ContainSubstring("- {$file: components/api.yaml}"))
+
+		b, err = os.ReadFile(filepath.Join(dir, "components", "api.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("_id: component-1"))
+		Expect(string(b)).To(ContainSubstring("name: api"))
+
+		b, err = os.ReadFile(filepath.Join(dir, "long.html"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(Equal("<p>Long.</p>\n"))
+	})
+
+	When("applying an overlay", func() {
+		const base = "kind: ProductListing\nspec:\n  _id: prod-id\n  name: listing\n"
+
+		var dir, listing string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			listing = filepath.Join(dir, "listing.product.yaml")
+			Expect(os.WriteFile(listing, []byte(base), 0o644)).To(Succeed())
+		})
+
+		It("should create a new overlay holding the IDs of that environment", func() {
+			selection := cli.OverlaySelection{Name: "stage", Explicit: true}
+			o, err := runner(stubClient(), false, selection, resource.WithStrict(true)).ApplyFile(context.TODO(), listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(o.Applied).To(HaveLen(1))
+
+			b, err := os.ReadFile(filepath.Join(dir, "listing.product.stage.overlay.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal("spec:\n  _id: created-1\n"))
+
+			b, err = os.ReadFile(listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal(base))
+		})
+
+		It("should update the objects identified by the overlay of the active environment", func() {
+			overlayFile := filepath.Join(dir, "listing.product.stage.overlay.yaml")
+			Expect(os.WriteFile(overlayFile, []byte("spec:\n  _id: stage-id\n"), 0o644)).To(Succeed())
+
+			client := stubClient()
+			o, err := runner(client, false, cli.OverlaySelection{Name: "stage"}, resource.WithStrict(true)).ApplyFile(context.TODO(), listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(o.Applied).To(HaveLen(1))
+			Expect(client.Requests["ApplyProductListing"]).To(ConsistOf(HaveKeyWithValue("id", "stage-id")))
+			Expect(client.Count("NewProductListing")).To(BeZero())
+		})
+
+		It("should apply the declaration as-is if the environment has no overlay", func() {
+			client := stubClient()
+			_, err := runner(client, false, cli.OverlaySelection{Name: "stage"}, resource.WithStrict(true)).ApplyFile(context.TODO(), listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Requests["ApplyProductListing"]).To(ConsistOf(HaveKeyWithValue("id", "prod-id")))
+			Expect(filepath.Join(dir, "listing.product.stage.overlay.yaml")).ToNot(BeAnExistingFile())
+		})
+
+		It("should validate declarations with the overlay applied", func() {
+			overlayFile := filepath.Join(dir, "listing.product.stage.overlay.yaml")
+			Expect(os.WriteFile(overlayFile, []byte("spec:\n  type: not a type\n"), 0o644)).To(Succeed())
+
+			Expect(runner(nil, false, cli.OverlaySelection{}, resource.WithStrict(true)).ValidateFiles([]string{listing})).To(Succeed())
+			err := runner(nil, false, cli.OverlaySelection{Name: "stage"}, resource.WithStrict(true)).ValidateFiles([]string{listing})
+			Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		})
+	})
+
+})
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -10,7 +10,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/apply"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
 )
 
 const testfixturesdir = "../testutils/testdata"
@@ -67,6 +69,32 @@ var _ = Describe("Apply", func() {
 					os.Setenv("PRODUCTCTL_API_TOKEN", "")
 				})
 
+				When("the declaration is invalid", func() {
+					const invalid = "kind: ProductListing\nspec:\n  name: invalid-product\n  type: not a type\n"
+
+					BeforeEach(func() {
+						Expect(os.WriteFile(file, []byte(invalid), 0o644)).To(Succeed())
+					})
+
+					It("should fail validation before contacting the backend", func() {
+						output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "apply", file, "--custom-endpoint", "http://localhost:9630")
+						Expect(err).To(MatchError(apply.ErrValidationFailed))
+						Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+						Expect(output).To(ContainSubstring("spec.type: "))
+						Expect(output).ToNot(ContainSubstring(syscall.ECONNREFUSED.Error()))
+
+						b, err := os.ReadFile(file)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(b)).To(Equal(invalid))
+					})
+
+					It("should reach the apply phase if validation is disabled", func() {
+						output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "apply", file, "--validate=false", "--custom-endpoint", "http://localhost:9630")
+						Expect(err).To(HaveOccurred())
+						Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+					})
+				})
+
 				It("should reach the apply phase, then fail", func() {
 					// Endpoint is spoofed to avoid spamming actual endpoints with requests
 					output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "apply", file, "--custom-endpoint", "http://localhost:9630")
@@ -76,5 +104,27 @@ var _ = Describe("Apply", func() {
 				})
 			})
 		})
+
+		When("a directory of declarations is provided", func() {
+			BeforeEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+
+				fixture, err := os.ReadFile(fixtureMinimalProduct)
+				Expect(err).ToNot(HaveOccurred())
+				multi := string(fixture) + "\n---\n" + string(fixture)
+				Expect(os.WriteFile(filepath.Join(tempDirPath, "multi.product.yaml"), []byte(multi), 0o644)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "")
+			})
+
+			It("should attempt every declaration in every file and summarize the failures", func() {
+				output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "apply", tempDirPath, "--custom-endpoint", "http://localhost:9630")
+				Expect(err).To(HaveOccurred())
+				Expect(output).To(ContainSubstring("0 declaration(s) applied, 3 failed"))
+				Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+			})
+		})
 	})
 })
@@ -0,0 +1,91 @@
+// Package applycomponent implements the component apply subcommand.
+package applycomponent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/applier"
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrApplyIncomplete      = errors.New("some component declarations could not be applied")
+	ErrValidationFailed     = errors.New("some component declarations are invalid, nothing was applied")
+	ErrOverlayRequiresFiles = errors.New("overlays cannot be applied to declarations read from stdin")
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <your-component.yaml|directory|-> [...]",
+		Short: "Apply changes to components from the input file.",
+		Long: `Apply changes to components based on the provided declarations of kind Component
+
+Components declared this way are managed independently of any product listing, and product listings attach them by listing them under with.component_refs, either by ID or by the file containing their declaration.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Each declaration is applied in turn, and its file is updated as soon as it has been applied, leaving the other declarations in that file untouched. A declaration that fails to apply does not prevent the others from being applied. A summary is printed on completion.
+
+Use "-" to read declarations from stdin, in which case the updated declarations are written to stdout.
+
+Every declaration is validated against the component schema before anything is applied. If any declaration is invalid, nothing is applied. Use --validate=false to skip this.
+
+Declarations may be shared between environments using overlays, as product listing declarations are. The overlay of my.component.yaml for the active environment, e.g. my.component.stage.overlay.yaml with --env stage, is merged into each declaration before it is applied, and the ID assigned by the backend is written to the overlay rather than to the declaration. Product listings referencing the component by file read its ID from the overlay of the same name. Use --overlay to choose an overlay by name instead, which is created if it does not exist, or --overlay="" to apply the declarations as they are.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: applier.Command[*resource.ComponentDeclaration]{
+			Runner:                  runner,
+			Columns:                 printer.ComponentColumns,
+			Kind:                    resource.KindComponent,
+			Noun:                    noun,
+			ErrApplyIncomplete:      ErrApplyIncomplete,
+			ErrValidationFailed:     ErrValidationFailed,
+			ErrOverlayRequiresFiles: ErrOverlayRequiresFiles,
+		}.RunE,
+	}
+
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite. Note that this backups the on-disk declaration that was created/applied before overwriting it with new content.")
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+	cmd.Flags().Bool(cli.FlagIDValidate, true, "Validate all declarations against the schema before applying any of them")
+	cmd.Flags().String(cli.FlagIDOverlay, "", "The overlay merged into each declaration, which also holds the IDs assigned by the backend. Defaults to the active environment, if its overlay exists")
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
+
+	return cmd
+}
+
+// noun names component declarations in summaries.
+const noun = "component declaration"
+
+// runner returns a runner applying component declarations with client,
+// merging the selected overlay of each declaration file into its
+// declarations.
+func runner(
+	client graphql.Client,
+	backupOnOverwrite bool,
+	overlaySelection cli.OverlaySelection,
+	readOpts ...resource.ReadOption,
+) applier.Runner[*resource.ComponentDeclaration] {
+	return applier.Runner[*resource.ComponentDeclaration]{
+		Read: resource.ReadComponentStream,
+		Name: func(d *resource.ComponentDeclaration) string { return d.Spec.Name },
+		Apply: func(ctx context.Context, d *resource.ComponentDeclaration, _ string) (*resource.ComponentDeclaration, error) {
+			return catalogapi.ApplyComponent(ctx, client, d)
+		},
+		Overlay: func(filename string) (applier.Overlay[*resource.ComponentDeclaration], string, error) {
+			ov, err := overlaySelection.ReadComponent(filename, readOpts...)
+			if ov == nil {
+				return nil, "", err
+			}
+
+			return ov, overlaySelection.Filename(filename), err
+		},
+		BackupOnOverwrite: backupOnOverwrite,
+		ReadOpts:          readOpts,
+	}
+}
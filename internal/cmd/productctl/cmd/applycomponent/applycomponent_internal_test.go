@@ -0,0 +1,72 @@
+package applycomponent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// stubClient answers component creation with a new ID, and fails any other
+// operation.
+func stubClient() *testutils.FakeClient {
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"NewComponent": testutils.JSON(`{"create_certification_project":{"data":{"_id":"created","name":"base-image","type":"Containers"}}}`),
+	}}
+}
+
+var _ = Describe("ApplyComponent (internal)", func() {
+	It("should apply every declaration it can, and write back the IDs assigned", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "components.yaml")
+		content := "# the base image\nkind: Component\nspec:\n  name: base-image\n  type: Containers\n---\nkind: Component\nspec:\n  type: Containers\n"
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+
+		o, err := runner(stubClient(), false, cli.OverlaySelection{}).ApplyFile(context.TODO(), filename)
+		Expect(err).To(MatchError(catalogapi.ErrMissingComponentName))
+		Expect(err.Error()).To(ContainSubstring("declaration 2"))
+		Expect(o.Applied).To(HaveLen(1))
+		Expect(o.Failed).To(Equal(1))
+
+		b, err := os.ReadFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(HavePrefix("# the base image\n"))
+
+		stream, err := resource.ReadComponentStream(bytes.NewReader(b))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stream.Declarations()[0].Spec.ID).To(Equal("created"))
+		Expect(stream.Declarations()[1].Spec.ID).To(BeEmpty())
+	})
+
+	It("should record the IDs assigned in the selected overlay, leaving the declaration as it was", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "base-image.yaml")
+		content := "kind: Component\nspec:\n  _id: prod-id\n  name: base-image\n  type: Containers\n"
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+
+		o, err := runner(stubClient(), false, cli.OverlaySelection{Name: "stage", Explicit: true}).ApplyFile(context.TODO(), filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(o.Applied).To(HaveLen(1))
+
+		b, err := os.ReadFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(Equal(content))
+
+		overlay, exists, err := resource.ReadComponentOverlayFile(file.OverlayFilename(filename, "stage"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		declaration, err := resource.ReadComponent(bytes.NewBufferString(content))
+		Expect(err).ToNot(HaveOccurred())
+		merged, err := overlay.Apply(0, declaration)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.Spec.ID).To(Equal("created"))
+	})
+})
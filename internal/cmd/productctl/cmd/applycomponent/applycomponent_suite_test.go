@@ -0,0 +1,13 @@
+package applycomponent_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyComponent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ApplyComponent Suite")
+}
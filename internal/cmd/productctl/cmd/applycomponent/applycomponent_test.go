@@ -0,0 +1,86 @@
+package applycomponent_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/applycomponent"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("ApplyComponent", func() {
+	var file string
+
+	BeforeEach(func() {
+		file = filepath.Join(GinkgoT().TempDir(), "base.component.yaml")
+		Expect(os.WriteFile(file, []byte("kind: Component\nspec:\n  name: base-image\n  type: Containers\n"), 0o644)).To(Succeed())
+	})
+
+	It("should fail if the minimum environment variables are not set", func() {
+		output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "apply", file, "--custom-endpoint", "http://localhost:9630")
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring(cmd.ErrMinOneAPITokenConfig.Error()))
+	})
+
+	When("the appropriate environment variables are in place", func() {
+		BeforeEach(func() {
+			os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+		})
+
+		AfterEach(func() {
+			os.Setenv("PRODUCTCTL_API_TOKEN", "")
+		})
+
+		It("should reach the apply phase, then fail", func() {
+			output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "apply", file, "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(applycomponent.ErrApplyIncomplete))
+			Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+			Expect(output).To(ContainSubstring("0 component declaration(s) applied, 1 failed"))
+		})
+
+		It("should fail validation before contacting the backend", func() {
+			const invalid = "kind: Component\nspec:\n  name: base-image\n  type: not a type\n"
+			Expect(os.WriteFile(file, []byte(invalid), 0o644)).To(Succeed())
+
+			output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "apply", file, "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(applycomponent.ErrValidationFailed))
+			Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+			Expect(output).ToNot(ContainSubstring(syscall.ECONNREFUSED.Error()))
+
+			b, err := os.ReadFile(file)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal(invalid))
+		})
+
+		It("should refuse to apply an overlay to declarations read from stdin", func() {
+			root := cmd.RootCmd()
+			root.SetIn(strings.NewReader("kind: Component\nspec:\n  name: base-image\n  type: Containers\n"))
+
+			_, err := testutils.ExecuteCommand(root, "component", "apply", "-", "--overlay", "stage", "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(applycomponent.ErrOverlayRequiresFiles))
+		})
+
+		It("should validate declarations read from stdin before contacting the backend", func() {
+			root := cmd.RootCmd()
+			root.SetIn(strings.NewReader("kind: Component\nspec:\n  name: base-image\n  type: not a type\n"))
+
+			output, err := testutils.ExecuteCommand(root, "component", "apply", "-", "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(applycomponent.ErrValidationFailed))
+			Expect(output).ToNot(ContainSubstring(syscall.ECONNREFUSED.Error()))
+		})
+
+		It("should refuse product listing declarations", func() {
+			Expect(os.WriteFile(file, []byte("kind: ProductListing\nspec:\n  name: listing\n"), 0o644)).To(Succeed())
+
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "apply", file, "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(resource.ErrUnknownKind))
+		})
+	})
+})
@@ -0,0 +1,522 @@
+// Package bundle implements the product bundle subcommands.
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	libbundle "github.com/opdev/productctl/internal/bundle"
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+	libversion "github.com/opdev/productctl/internal/version"
+)
+
+var (
+	ErrNotOneDeclaration  = errors.New("the file must contain exactly one product listing declaration")
+	ErrEnvMismatch        = errors.New("the bundle was created for another environment")
+	ErrChangedSinceFetch  = errors.New("the product listing has changed since the bundle was created, use --force to apply it anyway")
+	ErrInvalidDeclaration = errors.New("the declaration is invalid")
+)
+
+func CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <your-declaration.yaml> <bundle.tar.gz>",
+		Short: "Write a declaration and the files it reads from to a bundle",
+		Long: `Write a product listing declaration, and every file it reads from, to a bundle: a gzipped tarball that can be reviewed, archived and applied later with bundle apply.
+
+The declaration must be the only one in its file. Files included by the declaration, and the declarations of components referenced by file, are bundled with it, along with the overlay of the declaration and the files provided with --values. Every file must be within the root of the bundle, which is the directory of the declaration unless --dir is set, and references to files are written relative to the files that hold them, so that the bundle reads nothing outside itself once extracted.
+
+If the declaration has been applied, its product listing is fetched from the active environment, and the time it was fetched and when it was last updated are recorded in the bundle, along with the environment.
+
+The bundle holds a manifest of the SHA-256 digest of each file, SHA256SUMS, which is verified when the bundle is applied, and which can be verified by hand with sha256sum -c once the bundle is extracted.`,
+		Args: cobra.ExactArgs(2),
+		RunE: createRunE,
+	}
+
+	cmd.Flags().String(cli.FlagIDDir, "", "The root of the bundle, which every file bundled must be within. Defaults to the directory of the declaration")
+	cmd.Flags().String(cli.FlagIDOverlay, "", "The overlay bundled with the declaration, which also holds the IDs assigned by the backend. Defaults to the active environment")
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage+". The files are bundled")
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage+". Variables are then also interpolated when the bundle is applied")
+
+	return cmd
+}
+
+func ApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <bundle.tar.gz>",
+		Short: "Verify a bundle and apply the declaration it holds",
+		Long: `Verify that the content of a bundle matches its manifest, and apply the declaration it holds to the environment the bundle was created for.
+
+Nothing is applied if any file of the bundle is missing, modified or unexpected, if the declaration reads from files outside the bundle, if the active environment is not the one the bundle was created for, or if the product listing has been updated since it was fetched into the bundle. Use --force to apply the declaration even if the product listing has been updated since.
+
+Variables are interpolated from the values files of the bundle, and the overlay of the bundle is merged into the declaration. The bundle is not modified: the IDs assigned by the backend are recorded in the overlay next to the bundle, named after the declaration and the overlay of the bundle, which is created from the overlay of the bundle if it does not exist. The IDs recorded in the overlay next to the bundle are merged into the declaration along with the overlay of the bundle, or the overlay next to the bundle is merged in its place if the bundle holds none, so that applying a bundle again updates what was created before. The declaration is printed as it was applied.`,
+		Args: cobra.ExactArgs(1),
+		RunE: applyRunE,
+	}
+
+	cmd.Flags().Bool(cli.FlagIDForce, false, "Apply the declaration even if the product listing has been updated since it was bundled")
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the overlay next to the bundle before overwriting it")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
+	return cmd
+}
+
+func createRunE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	env, client, err := environment(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	variables, err := cli.Variables(cmd)
+	if err != nil {
+		return err
+	}
+
+	filename, out := args[0], args[1]
+	opts := createOptions{overlay: cli.SelectedOverlay(cmd, cfg).Name}
+	opts.root, _ = cmd.Flags().GetString(cli.FlagIDDir)
+	opts.values, _ = cmd.Flags().GetStringArray(cli.FlagIDValues)
+	opts.interpolate, _ = cmd.Flags().GetBool(cli.FlagIDInterpolate)
+
+	b, err := create(cmd.Context(), client, env, filename, opts, time.Now(), resource.WithStrict(cfg.Strict), resource.WithVariables(variables))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	L.Debug("writing bundle", "filename", out, "files", len(b.Files), "values", len(b.Values))
+	if err := b.Write(f); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "wrote %s with %d file(s) for %s\n", out, len(b.Files)+len(b.Values), env)
+	return nil
+}
+
+func applyRunE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, printer.DeclarationColumns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	b, err := libbundle.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	L.Debug("bundle verified", "filename", args[0], "declaration", b.Metadata.Declaration, "created", b.Metadata.CreatedAt)
+
+	env, client, err := environment(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "productctl-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	opts := applyOptions{sidecar: sidecarFilename(args[0], b.Metadata)}
+	opts.force, _ = cmd.Flags().GetBool(cli.FlagIDForce)
+	opts.backup, _ = cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
+
+	applied, err := apply(cmd.Context(), client, env, b, dir, opts, resource.WithStrict(cfg.Strict))
+	if err != nil {
+		return err
+	}
+
+	return p.Print(cmd.OutOrStdout(), applied)
+}
+
+// sidecarFilename returns the overlay next to the bundle at filename that
+// records the IDs assigned when it is applied, or an empty string if the
+// bundle has no overlay.
+func sidecarFilename(filename string, metadata libbundle.Metadata) string {
+	if metadata.Overlay == "" {
+		return ""
+	}
+
+	return file.OverlayFilename(filepath.Join(filepath.Dir(filename), path.Base(metadata.Declaration)), metadata.Overlay)
+}
+
+// environment returns the name of the active environment, or the custom
+// endpoint if one is set, and a client of it.
+func environment(cmd *cobra.Command, cfg *cli.UserConfig) (string, graphql.Client, error) {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	env := cfg.Env
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		env = endpoint
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	}
+
+	client, err := cli.EnvironmentClient(cmd.Context(), cfg, cfg.Env, endpoint, "")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return env, client, nil
+}
+
+// readDeclaration reads the only declaration in filename.
+func readDeclaration(filename string, readOpts ...resource.ReadOption) (*resource.ProductListingDeclaration, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stream, err := resource.ReadDeclarationStream(f, append(slices.Clip(readOpts), resource.WithBaseDir(filepath.Dir(filename)))...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	declarations := stream.Declarations()
+	if len(declarations) != 1 {
+		return nil, fmt.Errorf("%s: %w, found %d", filename, ErrNotOneDeclaration, len(declarations))
+	}
+
+	return declarations[0], nil
+}
+
+// overlaid returns declaration, read from filename, with overlay merged if
+// it is not nil, once validated.
+func overlaid(filename string, declaration *resource.ProductListingDeclaration, overlay *resource.Overlay) (*resource.ProductListingDeclaration, error) {
+	if overlay != nil {
+		var err error
+		if declaration, err = overlay.Apply(0, declaration); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+	}
+
+	if err := declaration.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", filename, ErrInvalidDeclaration, err)
+	}
+
+	return declaration, nil
+}
+
+// createOptions holds what is bundled alongside a declaration.
+type createOptions struct {
+	// root is the directory every file bundled must be within. Defaults to
+	// the directory of the declaration.
+	root string
+	// overlay is the name of the overlay of the declaration, which is
+	// bundled if it exists.
+	overlay string
+	// values are the values files bundled.
+	values []string
+	// interpolate is set if variables are interpolated from the environment
+	// when the bundle is applied, even if no values files are bundled.
+	interpolate bool
+}
+
+// create returns a bundle of the declaration in filename, for env. If the
+// declaration has been applied, its product listing is fetched from env
+// with client.
+func create(
+	ctx context.Context,
+	client graphql.Client,
+	env string,
+	filename string,
+	opts createOptions,
+	now time.Time,
+	readOpts ...resource.ReadOption,
+) (*libbundle.Bundle, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	declaration, err := readDeclaration(filename, readOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := resource.ReferencedFiles(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlay *resource.Overlay
+	if opts.overlay != "" {
+		// The overlays of the components referenced by file hold their IDs
+		// in the environment, and are bundled alongside them.
+		for _, referenced := range slices.Clone(files) {
+			componentOverlay := file.OverlayFilename(referenced, opts.overlay)
+			if _, err := os.Stat(componentOverlay); err == nil {
+				L.Debug("bundling component overlay", "overlay", componentOverlay)
+				files = append(files, componentOverlay)
+			}
+		}
+
+		overlayFilename := file.OverlayFilename(filename, opts.overlay)
+		ov, exists, err := resource.ReadOverlayFile(overlayFilename, readOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			L.Debug("bundling overlay", "overlay", overlayFilename)
+			overlay = ov
+			files = append(files, overlayFilename)
+		}
+	}
+
+	if declaration, err = overlaid(filename, declaration, overlay); err != nil {
+		return nil, err
+	}
+
+	metadata := libbundle.Metadata{
+		Overlay:     opts.overlay,
+		Interpolate: opts.interpolate,
+		SourceEnv:   env,
+		CreatedAt:   now.UTC().Truncate(time.Second),
+		CreatedBy:   libversion.Version.BaseName + " " + libversion.Version.Version,
+	}
+
+	if declaration.Spec.HasID() {
+		L.Debug("fetching product listing", "env", env, "_id", declaration.Spec.ID)
+		current, err := catalogapi.PopulateProduct(ctx, client, declaration.Spec.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: fetching product listing %s: %w", env, declaration.Spec.ID, err)
+		}
+
+		metadata.ListingID = declaration.Spec.ID
+		metadata.FetchedAt = &metadata.CreatedAt
+		metadata.LastUpdateDate = current.Spec.LastUpdateDate
+	}
+
+	root := opts.root
+	if root == "" {
+		root = filepath.Dir(filename)
+	}
+
+	b, err := libbundle.FromFiles(metadata, root, filename, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.AddValues(opts.values); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// applyOptions holds how a bundle is applied.
+type applyOptions struct {
+	// sidecar is the overlay the IDs assigned by the backend are recorded
+	// in, if it is not empty.
+	sidecar string
+	// backup creates a backup of sidecar before it is overwritten.
+	backup bool
+	// force applies the declaration even if the product listing has been
+	// updated since it was fetched into the bundle.
+	force bool
+}
+
+// apply applies the declaration held by b to env with client, once it has
+// been extracted to dir, and records the IDs assigned in opts.sidecar. Unless
+// opts.force is true, nothing is applied if the product listing has been
+// updated since it was fetched into b.
+func apply(
+	ctx context.Context,
+	client graphql.Client,
+	env string,
+	b *libbundle.Bundle,
+	dir string,
+	opts applyOptions,
+	readOpts ...resource.ReadOption,
+) (*resource.ProductListingDeclaration, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if b.Metadata.SourceEnv != env {
+		return nil, fmt.Errorf("%w: created for %s, applying to %s", ErrEnvMismatch, b.Metadata.SourceEnv, env)
+	}
+
+	if b.Metadata.ListingID != "" && b.Metadata.FetchedAt != nil && !opts.force {
+		L.Debug("checking product listing for updates", "env", env, "_id", b.Metadata.ListingID)
+		current, err := catalogapi.PopulateProduct(ctx, client, b.Metadata.ListingID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: fetching product listing %s: %w", env, b.Metadata.ListingID, err)
+		}
+
+		if !sameTime(current.Spec.LastUpdateDate, b.Metadata.LastUpdateDate) {
+			return nil, fmt.Errorf("%w: fetched at %s", ErrChangedSinceFetch, b.Metadata.FetchedAt.Format(time.RFC3339))
+		}
+	}
+
+	filename, err := b.Extract(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := b.Variables()
+	if err != nil {
+		return nil, err
+	}
+	readOpts = append(slices.Clip(readOpts), resource.WithVariables(variables))
+
+	declaration, err := readDeclaration(filename, readOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := bundledOverlay(b, dir, opts.sidecar, declaration, readOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if declaration, err = overlaid(filename, declaration, overlay); err != nil {
+		return nil, err
+	}
+
+	if err := declaration.ResolveComponentReferences(filepath.Dir(filename), b.Metadata.Overlay, readOpts...); err != nil {
+		return nil, err
+	}
+
+	L.Info("applying bundled declaration", "env", env, "name", declaration.Spec.Name)
+	applied, err := catalogapi.ApplyProduct(ctx, client, declaration)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlay == nil {
+		overlay = &resource.Overlay{}
+	}
+
+	if opts.sidecar != "" && overlay.SetServerIDs(0, applied) {
+		L.Info("recording the IDs assigned", "overlay", opts.sidecar)
+		w := file.LazyOverwriter{
+			Filename:        opts.sidecar,
+			DoBackup:        opts.backup,
+			CreateIfMissing: true,
+			OptionalLogger:  L.With("name", "fileIO"),
+		}
+		if _, err := w.Write(overlay.Bytes()); err != nil {
+			return applied, err
+		}
+	}
+
+	return applied, nil
+}
+
+// bundledOverlay returns the overlay of b, once it has been extracted to dir,
+// with the IDs recorded in the overlay at sidecar when b, or another bundle of
+// the same declaration, was applied before. If b holds no overlay, the
+// overlay at sidecar is returned as it is. It returns nil if neither exists.
+func bundledOverlay(
+	b *libbundle.Bundle,
+	dir string,
+	sidecar string,
+	declaration *resource.ProductListingDeclaration,
+	readOpts ...resource.ReadOption,
+) (*resource.Overlay, error) {
+	var overlay *resource.Overlay
+	if name := b.Metadata.OverlayFile(); name != "" {
+		if _, ok := b.Files[name]; ok {
+			var err error
+			if overlay, _, err = resource.ReadOverlayFile(filepath.Join(dir, filepath.FromSlash(name)), readOpts...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if sidecar == "" {
+		return overlay, nil
+	}
+
+	recorded, exists, err := resource.ReadOverlayFile(sidecar, readOpts...)
+	if err != nil || !exists {
+		return overlay, err
+	}
+
+	if overlay == nil {
+		return recorded, nil
+	}
+
+	if err := mergeRecordedIDs(overlay, recorded, declaration); err != nil {
+		return nil, fmt.Errorf("%s: %w", sidecar, err)
+	}
+
+	return overlay, nil
+}
+
+// mergeRecordedIDs records the IDs that recorded holds for declaration in
+// overlay. IDs that recorded does not hold are left as overlay holds them.
+func mergeRecordedIDs(overlay, recorded *resource.Overlay, declaration *resource.ProductListingDeclaration) error {
+	current, err := overlay.Apply(0, declaration)
+	if err != nil {
+		return err
+	}
+
+	ids, err := recorded.Apply(0, declaration)
+	if err != nil {
+		return err
+	}
+
+	if ids.Spec.ID == "" {
+		ids.Spec.ID = current.Spec.ID
+	}
+
+	for _, c := range ids.With.Components {
+		if c.ID != "" {
+			continue
+		}
+
+		for _, held := range current.With.Components {
+			if held.Name == c.Name {
+				c.ID = held.ID
+			}
+		}
+	}
+
+	overlay.SetServerIDs(0, ids)
+	return nil
+}
+
+// sameTime returns true if a and b are both nil, or are the same instant.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
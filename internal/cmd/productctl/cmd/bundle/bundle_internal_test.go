@@ -0,0 +1,172 @@
+package bundle
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// fakeEnvironment holds listing, a single product listing without components,
+// which is replaced when a product listing is created.
+func fakeEnvironment(listing map[string]any) *testutils.FakeClient {
+	apply := func(variables map[string]any) (any, error) {
+		if created, ok := variables["new"].(map[string]any); ok {
+			clear(listing)
+			listing["_id"] = "p456"
+			maps.Copy(listing, created)
+		}
+		if updated, ok := variables["update"].(map[string]any); ok {
+			maps.Copy(listing, updated)
+		}
+		listing["last_update_date"] = "2026-10-02T00:00:00Z"
+		return map[string]any{"update_product_listing": map[string]any{"data": listing}, "create_product_listing": map[string]any{"data": listing}}, nil
+	}
+
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"ProductByID": func(map[string]any) (any, error) {
+			return map[string]any{"get_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"ComponentsForListing": testutils.JSON(`{"find_product_listing_certification_projects":{"data":[],"total":0}}`),
+		"SetComponentsForProduct": func(map[string]any) (any, error) {
+			return map[string]any{"update_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"NewProductListing":   apply,
+		"ApplyProductListing": apply,
+	}}
+}
+
+var _ = Describe("Bundle (internal)", func() {
+	var stage *testutils.FakeClient
+	var listing map[string]any
+	var dir, declaration string
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		listing = map[string]any{"_id": "p123", "name": "mine", "type": "container stack", "last_update_date": "2026-09-30T00:00:00Z"}
+		stage = fakeEnvironment(listing)
+
+		dir = GinkgoT().TempDir()
+		declaration = filepath.Join(dir, "my.product.yaml")
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  _id: p123\n  name: mine\n  type: container stack\n  descriptions:\n    long: {$file: long.md}\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "long.md"), []byte("# Long\n"), 0o644)).To(Succeed())
+	})
+
+	It("should record when the product listing was fetched, and when it was last updated", func() {
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.Metadata.ListingID).To(Equal("p123"))
+		Expect(b.Metadata.FetchedAt).To(HaveValue(Equal(now)))
+		Expect(b.Metadata.LastUpdateDate).To(HaveValue(BeTemporally("==", time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC))))
+		Expect(b.Files).To(HaveKeyWithValue("long.md", []byte("# Long\n")))
+	})
+
+	It("should apply the bundled declaration with the files it includes", func() {
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{}, now)
+		Expect(err).ToNot(HaveOccurred())
+
+		applied, err := apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied.Spec.ID).To(Equal("p123"))
+		Expect(listing).To(HaveKeyWithValue("descriptions", HaveKeyWithValue("long", ContainSubstring("<h1>Long</h1>"))))
+	})
+
+	It("should refuse to apply a bundle if the product listing was updated since it was fetched", func() {
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{}, now)
+		Expect(err).ToNot(HaveOccurred())
+		listing["last_update_date"] = "2026-10-01T13:00:00Z"
+
+		_, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{})
+		Expect(err).To(MatchError(ErrChangedSinceFetch))
+		Expect(stage.Count("ApplyProductListing")).To(BeZero())
+
+		By("applying it anyway when forced")
+		_, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{force: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stage.Count("ApplyProductListing")).To(Equal(1))
+	})
+
+	It("should bundle the overlay, and merge it before checking for updates", func() {
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n  type: container stack\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "my.product.stage.overlay.yaml"), []byte("spec:\n  _id: p123\n"), 0o644)).To(Succeed())
+
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{overlay: "stage"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.Metadata.Overlay).To(Equal("stage"))
+		Expect(b.Metadata.ListingID).To(Equal("p123"))
+		Expect(b.Files).To(HaveKey("my.product.stage.overlay.yaml"))
+
+		listing["last_update_date"] = "2026-10-01T13:00:00Z"
+		_, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{})
+		Expect(err).To(MatchError(ErrChangedSinceFetch))
+	})
+
+	It("should record the IDs assigned in the overlay next to the bundle, and use them when applied again", func() {
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n  type: container stack\n"), 0o644)).To(Succeed())
+
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{overlay: "stage"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.Files).ToNot(HaveKey("my.product.stage.overlay.yaml"))
+
+		sidecar := filepath.Join(GinkgoT().TempDir(), "my.product.stage.overlay.yaml")
+		applied, err := apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{sidecar: sidecar})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied.Spec.ID).To(Equal("p456"))
+		Expect(os.ReadFile(sidecar)).To(ContainSubstring("_id: p456"))
+
+		By("applying the bundle again")
+		_, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{sidecar: sidecar})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stage.Count("NewProductListing")).To(Equal(1))
+		Expect(stage.Count("ApplyProductListing")).To(Equal(1))
+	})
+
+	It("should merge the IDs recorded next to the bundle over the overlay it holds when applied again", func() {
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n  type: container stack\n"), 0o644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "my.product.stage.overlay.yaml"), []byte("# stage\nspec:\n  type: traditional application\n"), 0o644)).To(Succeed())
+
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{overlay: "stage"}, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.Files).To(HaveKey("my.product.stage.overlay.yaml"))
+		Expect(b.Metadata.ListingID).To(BeEmpty())
+
+		sidecar := filepath.Join(GinkgoT().TempDir(), "my.product.stage.overlay.yaml")
+		applied, err := apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{sidecar: sidecar})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied.Spec.ID).To(Equal("p456"))
+		Expect(applied.Spec.Type).To(Equal("traditional application"))
+		Expect(os.ReadFile(sidecar)).To(And(ContainSubstring("_id: p456"), ContainSubstring("type: traditional application")))
+
+		By("applying the bundle again")
+		applied, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{sidecar: sidecar})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(applied.Spec.ID).To(Equal("p456"))
+		Expect(stage.Count("NewProductListing")).To(Equal(1))
+		Expect(stage.Count("ApplyProductListing")).To(Equal(1))
+		Expect(os.ReadFile(sidecar)).To(And(ContainSubstring("_id: p456"), ContainSubstring("type: traditional application")))
+	})
+
+	It("should interpolate the values bundled", func() {
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  _id: p123\n  name: mine\n  type: container stack\n  descriptions:\n    short: ${SHORT}\n"), 0o644)).To(Succeed())
+		values := filepath.Join(GinkgoT().TempDir(), "values.yaml")
+		Expect(os.WriteFile(values, []byte("SHORT: A short description of the product listing, read from the bundle\n"), 0o644)).To(Succeed())
+
+		variables, err := resource.ReadValuesFile(values)
+		Expect(err).ToNot(HaveOccurred())
+		b, err := create(context.TODO(), stage, "stage", declaration, createOptions{values: []string{values}}, now, resource.WithVariables(resource.ValuesOver(variables)))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b.Metadata.Values).To(Equal([]string{"1-values.yaml"}))
+		Expect(os.Remove(values)).To(Succeed())
+
+		_, err = apply(context.TODO(), stage, "stage", b, GinkgoT().TempDir(), applyOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing).To(HaveKeyWithValue("descriptions", HaveKeyWithValue("short", "A short description of the product listing, read from the bundle")))
+	})
+})
@@ -0,0 +1,101 @@
+package bundle_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	libbundle "github.com/opdev/productctl/internal/bundle"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/bundle"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("Bundle", func() {
+	var dir string
+
+	BeforeEach(func() {
+		os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+		DeferCleanup(os.Setenv, "PRODUCTCTL_API_TOKEN", "")
+		dir = GinkgoT().TempDir()
+	})
+
+	When("creating a bundle", func() {
+		It("should require the declaration and the bundle", func() {
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "create", "my.product.yaml")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should refuse files that do not hold exactly one declaration", func() {
+			declaration := filepath.Join(dir, "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n---\nkind: ProductListing\nspec:\n  name: other\n"), 0o644)).To(Succeed())
+
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "create", declaration, filepath.Join(dir, "my.bundle.tar.gz"))
+			Expect(err).To(MatchError(bundle.ErrNotOneDeclaration))
+			Expect(filepath.Join(dir, "my.bundle.tar.gz")).ToNot(BeAnExistingFile())
+		})
+
+		It("should bundle declarations that have not been applied without contacting the backend", func() {
+			declaration := filepath.Join(dir, "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n  descriptions:\n    long: {$file: long.md}\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "long.md"), []byte("# Long\n"), 0o644)).To(Succeed())
+
+			out, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "create", declaration, filepath.Join(dir, "my.bundle.tar.gz"), "--env", "stage")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring("with 2 file(s) for stage"))
+
+			b, err := libbundle.ReadFile(filepath.Join(dir, "my.bundle.tar.gz"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.Metadata.Declaration).To(Equal("my.product.yaml"))
+			Expect(b.Metadata.SourceEnv).To(Equal("stage"))
+			Expect(b.Metadata.FetchedAt).To(BeNil())
+			Expect(b.Files).To(HaveKey("long.md"))
+		})
+		It("should refuse files outside the root of the bundle unless it is set with --dir", func() {
+			Expect(os.MkdirAll(filepath.Join(dir, "product"), 0o755)).To(Succeed())
+			declaration := filepath.Join(dir, "product", "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n  descriptions:\n    long: {$file: ../long.md}\n"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "long.md"), []byte("# Long\n"), 0o644)).To(Succeed())
+
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "create", declaration, filepath.Join(dir, "my.bundle.tar.gz"), "--env", "stage")
+			Expect(err).To(MatchError(libbundle.ErrOutsideBundle))
+
+			_, err = testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "create", declaration, filepath.Join(dir, "my.bundle.tar.gz"), "--env", "stage", "--dir", dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			b, err := libbundle.ReadFile(filepath.Join(dir, "my.bundle.tar.gz"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(b.Metadata.Declaration).To(Equal("product/my.product.yaml"))
+			Expect(b.Metadata.Overlay).To(Equal("stage"))
+			Expect(b.Files).To(HaveKey("long.md"))
+		})
+	})
+
+	When("applying a bundle", func() {
+		It("should refuse bundles that do not match their manifest", func() {
+			filename := filepath.Join(dir, "my.bundle.tar.gz")
+			Expect(os.WriteFile(filename, []byte("not a bundle"), 0o644)).To(Succeed())
+
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "apply", filename)
+			Expect(err).To(MatchError(libbundle.ErrInvalidBundle))
+		})
+
+		It("should refuse bundles created for another environment", func() {
+			b := &libbundle.Bundle{
+				Metadata: libbundle.Metadata{Declaration: "my.product.yaml", SourceEnv: "stage", CreatedAt: time.Now()},
+				Files:    map[string][]byte{"my.product.yaml": []byte("kind: ProductListing\nspec:\n  name: mine\n")},
+			}
+			var out bytes.Buffer
+			Expect(b.Write(&out)).To(Succeed())
+			filename := filepath.Join(dir, "my.bundle.tar.gz")
+			Expect(os.WriteFile(filename, out.Bytes(), 0o644)).To(Succeed())
+
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "bundle", "apply", filename, "--env", "prod")
+			Expect(err).To(MatchError(bundle.ErrEnvMismatch))
+		})
+	})
+})
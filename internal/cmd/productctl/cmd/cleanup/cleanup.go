@@ -2,109 +2,86 @@ package cleanup
 
 import (
 	"context"
-	"fmt"
-	"io"
-	"os"
+	"errors"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/yaml"
 
+	"github.com/opdev/productctl/internal/applier"
 	"github.com/opdev/productctl/internal/catalogapi"
 	"github.com/opdev/productctl/internal/cli"
-	"github.com/opdev/productctl/internal/file"
-	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
 	"github.com/opdev/productctl/internal/resource"
 )
 
+var (
+	ErrCleanupIncomplete    = errors.New("some declarations could not be cleaned up")
+	ErrOverlayRequiresFiles = errors.New("overlays cannot be applied to declarations read from stdin")
+)
+
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "cleanup [my.product.yaml]",
+		Use:   "cleanup [my.product.yaml|directory|-] [...]",
 		Short: "Detaches and archives components. Deletes the product listing. This is destructive. Use with caution.",
-		Args:  cobra.MinimumNArgs(1), // The product declaration
-		RunE:  runE,
-	}
+		Long: `Detaches and archives components. Deletes the product listing. This is destructive. Use with caution.
 
-	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite. Note that this backups the on-disk declaration that was created/applied before overwriting it with new content.")
-
-	return cmd
-}
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Each declaration is cleaned up in turn, and its file is updated as soon as it has been cleaned up, leaving the other declarations in that file untouched. A declaration that fails to clean up does not prevent the others from being cleaned up. A summary is printed on completion.
 
-func runE(cmd *cobra.Command, args []string) error {
-	L := logger.FromContextOrDiscard(cmd.Context())
-
-	cfg, err := cli.Config()
-	if err != nil {
-		return err
-	}
-
-	token, err := cfg.Token()
-	if err != nil {
-		return err
-	}
-
-	var endpoint string
-	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
-		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
-		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
-	} else {
-		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
-		if err != nil {
-			return err
-		}
-		L.Debug("endpoint resolved", "endpoint", endpoint)
-	}
+Use "-" to read declarations from stdin, in which case the updated declarations are written to stdout.
 
-	if args[0] == "-" {
-		return runCleanup(cmd.Context(), os.Stdin, os.Stdout, token, endpoint)
-	}
+Use --output to print the declarations that were cleaned up to stdout in another format.
 
-	// This is a read-only open.
-	f, err := os.Open(args[0])
-	if err != nil {
-		return err
+As with apply, the overlay of each file for the active environment is merged into its declarations, so that the objects of that environment are cleaned up, and their IDs are removed from the overlay rather than from the declaration. Use --overlay to choose an overlay by name instead, or --overlay="" to clean up the declarations as they are.`,
+		Args: cobra.MinimumNArgs(1), // The product declaration
+		RunE: applier.Command[*resource.ProductListingDeclaration]{
+			Runner:                  runner,
+			Columns:                 printer.DeclarationColumns,
+			Kind:                    resource.KindProductListing,
+			Noun:                    noun,
+			Verb:                    "cleaned up",
+			ErrApplyIncomplete:      ErrCleanupIncomplete,
+			ErrOverlayRequiresFiles: ErrOverlayRequiresFiles,
+		}.RunE,
 	}
 
-	backupOnOverwrite, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
-
-	updateFileOnSuccess := file.LazyOverwriter{
-		Filename:       args[0],
-		DoBackup:       backupOnOverwrite,
-		OptionalLogger: L.With("name", "fileIO"),
-	}
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite. Note that this backups the on-disk declaration that was created/applied before overwriting it with new content.")
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+	cmd.Flags().String(cli.FlagIDOverlay, "", "The overlay merged into each declaration, which also holds the IDs of the objects cleaned up. Defaults to the active environment, if its overlay exists")
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
 
-	defer f.Close()
-	return runCleanup(cmd.Context(), f, &updateFileOnSuccess, token, endpoint)
+	return cmd
 }
 
-func runCleanup(ctx context.Context, in io.Reader, outOnCompletion io.Writer, token string, endpoint catalogapi.APIEndpoint) error {
-	L := logger.FromContextOrDiscard(ctx)
-
-	L.Info("reading in product listing")
-	declaration, err := resource.ReadProductListing(in)
-	if err != nil {
-		return err
+// noun names product listing declarations in summaries.
+const noun = "declaration"
+
+// runner returns a runner cleaning up product listing declarations with
+// client, merging the selected overlay of each declaration file into its
+// declarations. The IDs of the objects cleaned up are removed from the overlay
+// if it exists, and from the declaration otherwise.
+func runner(
+	client graphql.Client,
+	backupOnOverwrite bool,
+	overlaySelection cli.OverlaySelection,
+	readOpts ...resource.ReadOption,
+) applier.Runner[*resource.ProductListingDeclaration] {
+	return applier.Runner[*resource.ProductListingDeclaration]{
+		Read: resource.ReadDeclarationStream,
+		Name: func(d *resource.ProductListingDeclaration) string { return d.Spec.Name },
+		Apply: func(ctx context.Context, d *resource.ProductListingDeclaration, _ string) (*resource.ProductListingDeclaration, error) {
+			return catalogapi.CleanupProduct(ctx, client, d)
+		},
+		Overlay: func(filename string) (applier.Overlay[*resource.ProductListingDeclaration], string, error) {
+			ov, err := overlaySelection.Read(filename, readOpts...)
+			if ov == nil {
+				return nil, "", err
+			}
+
+			return ov, overlaySelection.Filename(filename), err
+		},
+		BackupOnOverwrite: backupOnOverwrite,
+		ReadOpts:          readOpts,
 	}
-
-	L.Debug("building graphql client")
-	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
-	client := graphql.NewClient(endpoint, httpClient)
-
-	L.Debug("starting cleanup")
-	cleaned, err := catalogapi.CleanupProduct(ctx, client, declaration)
-	if err != nil {
-		return err
-	}
-
-	L.Info("Updating provided resource declaration.")
-	b, err := yaml.Marshal(cleaned)
-	if err != nil {
-		return err
-	}
-	_, err = fmt.Fprint(outOnCompletion, string(b))
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
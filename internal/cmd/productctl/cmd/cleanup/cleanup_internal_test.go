@@ -0,0 +1,62 @@
+package cleanup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// stubClient answers the operations of cleaning up a listing without
+// components.
+func stubClient() *testutils.FakeClient {
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"SetComponentsForProduct": testutils.JSON(`{"update_product_listing":{"data":{}}}`),
+		"DeleteProduct":           testutils.JSON(`{"update_product_listing":{"data":{"deleted":true}}}`),
+	}}
+}
+
+var _ = Describe("Cleanup (internal)", func() {
+	When("applying an overlay", func() {
+		const base = "kind: ProductListing\nspec:\n  _id: prod-id\n  name: listing\n"
+
+		var dir, listing, overlayFile string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			listing = filepath.Join(dir, "listing.product.yaml")
+			overlayFile = filepath.Join(dir, "listing.product.stage.overlay.yaml")
+			Expect(os.WriteFile(listing, []byte(base), 0o644)).To(Succeed())
+			Expect(os.WriteFile(overlayFile, []byte("# stage\nspec:\n  _id: stage-id\n"), 0o644)).To(Succeed())
+		})
+
+		It("should clean up the objects of the environment and remove their IDs from the overlay", func() {
+			client := stubClient()
+			o, err := runner(client, false, cli.OverlaySelection{Name: "stage"}, resource.WithStrict(true)).ApplyFile(context.TODO(), listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(o.Applied).To(HaveLen(1))
+			Expect(client.Requests["DeleteProduct"]).To(ConsistOf(HaveKeyWithValue("id", "stage-id")))
+
+			b, err := os.ReadFile(overlayFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal("# stage\nspec: {}\n"))
+
+			b, err = os.ReadFile(listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(b)).To(Equal(base))
+		})
+
+		It("should clean up the declaration as-is if overlays are disabled", func() {
+			client := stubClient()
+			_, err := runner(client, false, cli.OverlaySelection{Explicit: true}, resource.WithStrict(true)).ApplyFile(context.TODO(), listing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(client.Requests["DeleteProduct"]).To(ConsistOf(HaveKeyWithValue("id", "prod-id")))
+		})
+	})
+})
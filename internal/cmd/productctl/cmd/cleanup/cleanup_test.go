@@ -1,6 +1,7 @@
 package cleanup_test
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/cleanup"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
 )
 
@@ -74,6 +76,44 @@ var _ = Describe("Cleanup", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
 				})
+
+				It("should clean up declarations read from stdin, then fail", func() {
+					fixture, err := os.ReadFile(file)
+					Expect(err).ToNot(HaveOccurred())
+					root := cmd.RootCmd()
+					root.SetIn(bytes.NewReader(fixture))
+
+					output, err := testutils.ExecuteCommand(root, "product", "cleanup", "-", "--custom-endpoint", "http://localhost:9630")
+					Expect(err).To(MatchError(syscall.ECONNREFUSED))
+					Expect(output).To(ContainSubstring("0 declaration(s) cleaned up, 1 failed"))
+				})
+
+				It("should refuse to apply an overlay to declarations read from stdin", func() {
+					_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "cleanup", "-", "--overlay", "stage", "--custom-endpoint", "http://localhost:9630")
+					Expect(err).To(MatchError(cleanup.ErrOverlayRequiresFiles))
+				})
+			})
+		})
+
+		When("a directory of declarations is provided", func() {
+			BeforeEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+
+				fixture, err := os.ReadFile(fixtureMinimalProduct)
+				Expect(err).ToNot(HaveOccurred())
+				multi := string(fixture) + "\n---\n" + string(fixture)
+				Expect(os.WriteFile(filepath.Join(tempDirPath, "multi.product.yaml"), []byte(multi), 0o644)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "")
+			})
+
+			It("should attempt every declaration in every file and summarize the failures", func() {
+				output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "cleanup", tempDirPath, "--custom-endpoint", "http://localhost:9630")
+				Expect(err).To(HaveOccurred())
+				Expect(output).To(ContainSubstring("0 declaration(s) cleaned up, 3 failed"))
+				Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
 			})
 		})
 	})
@@ -0,0 +1,197 @@
+// Package clone implements the product clone subcommand.
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrSameEnv               = errors.New("the product listing must be cloned to another environment")
+	ErrCustomEndpointFromEnv = errors.New("--custom-endpoint replaces --env, and cannot be used with --from-env")
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clone <productID> --to-env <env>",
+		Short: "Copy a product listing and its components to another environment",
+		Long: `Copy a product listing, and the components attached to it, from one catalog API environment to another, e.g. to reproduce a production listing in stage.
+
+The product listing is fetched from the environment named by --from-env, which defaults to --env, as with the fetch command. Everything that ties it to that environment, such as its IDs, is removed as with the sanitize command, and it is created in the environment named by --to-env, along with each of its components.
+
+A --custom-endpoint is used in place of --env, as the environment the listing is copied from, and so cannot be used with --from-env.
+
+The ID of the product listing and of each component in both environments is printed once the listing has been created. Components are created before the listing, and if creating any of them or the listing fails, the IDs of the components already created are printed along with the error. Use "fetch" with --env set to the target environment to store the new listing's declaration.
+
+Environments usually require API keys of their own. Use --from-api-token-file and --to-api-token-file to provide a file holding the key for each environment, otherwise your configured API key is used for both.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runE,
+	}
+
+	cmd.Flags().String(cli.FlagIDFromEnv, "", "The catalog API environment the product listing is copied from. Defaults to --env")
+	cmd.Flags().String(cli.FlagIDToEnv, "", "The catalog API environment the product listing is copied to")
+	cmd.Flags().String(cli.FlagIDFromAPITokenFile, "", "A file holding the API key used with the environment the product listing is copied from")
+	cmd.Flags().String(cli.FlagIDToAPITokenFile, "", "A file holding the API key used with the environment the product listing is copied to")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatTable, printer.Usage(true))
+	_ = cmd.MarkFlagRequired(cli.FlagIDToEnv)
+
+	return cmd
+}
+
+// mapping relates an object in the source environment to its copy in the
+// target environment.
+type mapping struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	fromEnv, _ := cmd.Flags().GetString(cli.FlagIDFromEnv)
+	toEnv, _ := cmd.Flags().GetString(cli.FlagIDToEnv)
+
+	// The custom endpoint supersedes --env, which names the environment
+	// copied from unless --from-env does.
+	var fromEndpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		if fromEnv != "" {
+			return ErrCustomEndpointFromEnv
+		}
+		fromEndpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		fromEnv = fromEndpoint
+		L.Debug("custom endpoint set, using it over env value", "endpoint", fromEndpoint)
+	} else {
+		if fromEnv == "" {
+			fromEnv = cfg.Env
+		}
+		if fromEndpoint, err = cli.ResolveAPIEndpoint(fromEnv); err != nil {
+			return fmt.Errorf("%s: %w", fromEnv, err)
+		}
+	}
+
+	toEndpoint, err := cli.ResolveAPIEndpoint(toEnv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", toEnv, err)
+	}
+
+	if fromEndpoint == toEndpoint {
+		return fmt.Errorf("%w: both are %s", ErrSameEnv, toEnv)
+	}
+
+	source, err := cli.EnvironmentClient(cmd.Context(), cfg, fromEnv, fromEndpoint, cmd.Flag(cli.FlagIDFromAPITokenFile).Value.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", fromEnv, err)
+	}
+
+	target, err := cli.EnvironmentClient(cmd.Context(), cfg, toEnv, toEndpoint, cmd.Flag(cli.FlagIDToAPITokenFile).Value.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", toEnv, err)
+	}
+
+	L.Debug("cloning product listing", "_id", args[0], "from", fromEnv, "to", toEnv)
+	mappings, err := run(cmd.Context(), source, target, args[0])
+	if err != nil {
+		// What was created before the failure is printed, so that it can be
+		// found and removed or reused.
+		if len(mappings) > 0 {
+			return errors.Join(err, p.Print(cmd.OutOrStdout(), mappings))
+		}
+		return err
+	}
+
+	return p.Print(cmd.OutOrStdout(), mappings)
+}
+
+// run copies the product listing with listingID from source to target, and
+// returns the mapping of the IDs of the listing and its components. The
+// components are created before the listing, and the mappings of those that
+// were created are returned even if a later step fails, so that they can be
+// found.
+func run(ctx context.Context, source, target graphql.Client, listingID string) ([]mapping, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	declaration, err := catalogapi.PopulateProduct(ctx, source, listingID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching product listing %s: %w", listingID, err)
+	}
+
+	mappings := []mapping{{Kind: resource.KindProductListing, Name: declaration.Spec.Name, From: declaration.Spec.ID}}
+	for _, c := range declaration.With.Components {
+		mappings = append(mappings, mapping{Kind: resource.KindComponent, Name: c.Name, From: c.ID})
+	}
+
+	declaration.Sanitize()
+
+	// Components are created one at a time, rather than with the listing, so
+	// that each that was created is known if a later one fails.
+	listing := resource.NewProductListing()
+	listing.Spec = declaration.Spec
+	for i, c := range declaration.With.Components {
+		L.Debug("creating component", "name", c.Name)
+		created, err := catalogapi.ApplyComponent(ctx, target, &resource.ComponentDeclaration{APIVersion: resource.CurrentAPIVersion, Kind: resource.KindComponent, Spec: *c})
+		if err != nil {
+			return copied(mappings), fmt.Errorf("creating component %q: %w", c.Name, err)
+		}
+
+		mappings[i+1].To = created.Spec.ID
+		listing.With.ComponentRefs = append(listing.With.ComponentRefs, &resource.ComponentReference{ID: created.Spec.ID})
+	}
+
+	L.Debug("creating product listing", "name", listing.Spec.Name, "components", len(listing.With.ComponentRefs))
+	created, err := catalogapi.ApplyProduct(ctx, target, &listing)
+	if err != nil {
+		return copied(mappings), fmt.Errorf("creating product listing %q: %w", listing.Spec.Name, err)
+	}
+
+	mappings[0].To = created.Spec.ID
+	return mappings, nil
+}
+
+// copied returns the mappings of the objects that have been copied.
+func copied(mappings []mapping) []mapping {
+	var done []mapping
+	for _, m := range mappings {
+		if m.To != "" {
+			done = append(done, m)
+		}
+	}
+
+	return done
+}
+
+// columns renders mappings as a table.
+var columns = &printer.Columns[[]mapping]{
+	Header: []string{"KIND", "NAME", "FROM", "TO"},
+	Rows: func(mappings []mapping) [][]string {
+		rows := make([][]string, 0, len(mappings))
+		for _, m := range mappings {
+			rows = append(rows, []string{m.Kind, m.Name, m.From, m.To})
+		}
+
+		return rows
+	},
+}
@@ -0,0 +1,91 @@
+package clone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+// sourceClient answers queries for a product listing with two components.
+func sourceClient() *testutils.FakeClient {
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"ProductByID": testutils.JSON(`{"get_product_listing":{"data":{"_id":"prod-listing","org_id":1,"name":"My Product","type":"container stack","cert_projects":["prod-api","prod-ui"]}}}`),
+		"ComponentsForListing": testutils.JSON(`{"find_product_listing_certification_projects":{"data":[
+			{"_id":"prod-api","org_id":1,"name":"api","type":"Containers","badges":["certified"],"container":{"isv_pid":"pid-1","registry":"quay.io"}},
+			{"_id":"prod-ui","org_id":1,"name":"ui","type":"Containers"}
+		],"total":2}}`),
+	}}
+}
+
+// targetClient answers the creation of product listings and components with
+// the input it was given and a new ID, returning components in reverse order
+// of creation. It fails the creation of product listings if failListing is
+// set.
+func targetClient(failListing bool) *testutils.FakeClient {
+	var components []map[string]any
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"NewComponent": func(variables map[string]any) (any, error) {
+			component := maps.Clone(variables["new"].(map[string]any))
+			component["_id"] = fmt.Sprintf("stage-%s", component["name"])
+			components = append([]map[string]any{component}, components...)
+			return map[string]any{"create_certification_project": map[string]any{"data": component}}, nil
+		},
+		"NewProductListing": func(variables map[string]any) (any, error) {
+			if failListing {
+				return nil, errors.New("listing rejected")
+			}
+			listing := map[string]any{"_id": "stage-listing", "name": variables["new"].(map[string]any)["name"]}
+			return map[string]any{"create_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"ComponentsForListing": func(map[string]any) (any, error) {
+			return map[string]any{"find_product_listing_certification_projects": map[string]any{"data": components, "total": len(components)}}, nil
+		},
+	}}
+}
+
+var _ = Describe("Clone (internal)", func() {
+	It("should create the listing and its components in the target, mapping their IDs", func() {
+		target := targetClient(false)
+		mappings, err := run(context.TODO(), sourceClient(), target, "prod-listing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mappings).To(Equal([]mapping{
+			{Kind: "ProductListing", Name: "My Product", From: "prod-listing", To: "stage-listing"},
+			{Kind: "Component", Name: "api", From: "prod-api", To: "stage-api"},
+			{Kind: "Component", Name: "ui", From: "prod-ui", To: "stage-ui"},
+		}))
+
+		By("sending nothing that ties the listing to the source environment")
+		Expect(target.Requests["NewProductListing"]).To(HaveLen(1))
+		listing := target.Requests["NewProductListing"][0]["new"]
+		Expect(listing).ToNot(HaveKey("_id"))
+		Expect(listing).ToNot(HaveKey("org_id"))
+		Expect(listing).To(HaveKeyWithValue("cert_projects", ConsistOf("stage-api", "stage-ui")))
+		components := target.Requests["NewComponent"]
+		Expect(components).To(HaveLen(2))
+		for _, c := range components {
+			Expect(c["new"]).ToNot(HaveKey("org_id"))
+			Expect(c["new"]).ToNot(HaveKey("badges"))
+		}
+		Expect(components[0]["new"]).To(HaveKeyWithValue("container", Equal(map[string]any{"registry": "quay.io"})))
+	})
+
+	It("should return the components created before the listing could not be", func() {
+		mappings, err := run(context.TODO(), sourceClient(), targetClient(true), "prod-listing")
+		Expect(err).To(MatchError(ContainSubstring("listing rejected")))
+		Expect(mappings).To(Equal([]mapping{
+			{Kind: "Component", Name: "api", From: "prod-api", To: "stage-api"},
+			{Kind: "Component", Name: "ui", From: "prod-ui", To: "stage-ui"},
+		}))
+	})
+
+	It("should report listings that cannot be fetched", func() {
+		_, err := run(context.TODO(), targetClient(false), targetClient(false), "prod-listing")
+		Expect(err).To(MatchError(ContainSubstring("fetching product listing prod-listing")))
+	})
+})
@@ -0,0 +1,46 @@
+package clone_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/clone"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("Clone", func() {
+	BeforeEach(func() {
+		os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+		DeferCleanup(os.Setenv, "PRODUCTCTL_API_TOKEN", "")
+	})
+
+	It("should require the environment to clone to", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "clone", "123")
+		Expect(err).To(MatchError(ContainSubstring(cli.FlagIDToEnv)))
+	})
+
+	It("should refuse to clone a listing to its own environment", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "clone", "123", "--from-env", "stage", "--to-env", "stage")
+		Expect(err).To(MatchError(clone.ErrSameEnv))
+	})
+
+	It("should refuse to clone a listing from a custom endpoint to the same endpoint", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "clone", "123", "--custom-endpoint", catalogapi.EndpointStage, "--to-env", "stage")
+		Expect(err).To(MatchError(clone.ErrSameEnv))
+	})
+
+	It("should refuse a custom endpoint along with the environment to clone from", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "clone", "123", "--custom-endpoint", "https://example.com/graphql", "--from-env", "prod", "--to-env", "stage")
+		Expect(err).To(MatchError(clone.ErrCustomEndpointFromEnv))
+	})
+
+	It("should refuse unknown environments", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "clone", "123", "--to-env", "nowhere")
+		Expect(err).To(MatchError(cli.ErrAPIEndpointUnknown))
+	})
+})
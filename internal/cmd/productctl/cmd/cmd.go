@@ -10,14 +10,31 @@ import (
 
 	"github.com/opdev/productctl/internal/cli"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/apply"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/applycomponent"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/archivecomponent"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/bridge"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/bundle"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/cleanup"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/clone"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/create"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/createapikey"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/deleteapikey"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/deleteproductlisting"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/exportproducts"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/fetch"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/fetchcomponent"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/importmarkdown"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/jsonschema"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/lintproducts"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/listapikeys"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/listcomponents"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/listproducts"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/migrate"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/promote"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/rotateapikey"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/sanitize"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/sanitizecomponent"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/validate"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/version"
 	libversion "github.com/opdev/productctl/internal/version"
 )
@@ -50,8 +67,12 @@ func RootCmd() *cobra.Command {
 	commonFlags := pflag.NewFlagSet("common", pflag.ContinueOnError)
 	commonFlags.String(cli.FlagIDEnv, cli.DefaultEnv, "The catalog API environment to use. Choose from stage, prod")
 	commonFlags.String(cli.FlagIDCustomEndpoint, "", "Define a custom API endpoint. Supersedes predefined environment values like \"prod\" if set")
+	commonFlags.Int(cli.FlagIDOrgID, 0, "The ID of the organization that owns the resources being managed")
+	commonFlags.Bool(cli.FlagIDStrict, false, "Reject declarations containing fields that are not part of their resource, e.g. misspelled fields")
 	envFlag := commonFlags.Lookup(cli.FlagIDEnv)
 	customEndpointFlag := commonFlags.Lookup(cli.FlagIDCustomEndpoint)
+	orgIDFlag := commonFlags.Lookup(cli.FlagIDOrgID)
+	strictFlag := commonFlags.Lookup(cli.FlagIDStrict)
 
 	cmd.AddCommand(version.Command())
 	cmd.PersistentFlags().String(cli.FlagIDLogLevel, cli.DefaultLogLevel, "The verbosity of the tool itself. Ex. error, warn, info, debug")
@@ -66,14 +87,54 @@ func RootCmd() *cobra.Command {
 	product := bridge.Command("product", "Manage your Product Listing")
 	product.PersistentFlags().AddFlag(envFlag)
 	product.PersistentFlags().AddFlag(customEndpointFlag)
+	product.PersistentFlags().AddFlag(orgIDFlag)
+	product.PersistentFlags().AddFlag(strictFlag)
 	product.AddCommand(create.Command())
 	product.AddCommand(apply.Command())
 	product.AddCommand(fetch.Command())
 	product.AddCommand(sanitize.Command())
+	product.AddCommand(validate.Command())
+	product.AddCommand(lintproducts.Command())
+	product.AddCommand(migrate.Command())
+	product.AddCommand(importmarkdown.FAQsCommand())
+	product.AddCommand(importmarkdown.FeaturesCommand())
 	product.AddCommand(cleanup.Command())
 	product.AddCommand(jsonschema.Command())
+	product.AddCommand(listproducts.Command())
+	product.AddCommand(exportproducts.Command())
+	product.AddCommand(clone.Command())
+	product.AddCommand(promote.Command())
+	bundles := bridge.Command("bundle", "Create and apply auditable bundles of declarations")
+	bundles.AddCommand(bundle.CreateCommand())
+	bundles.AddCommand(bundle.ApplyCommand())
+	product.AddCommand(bundles)
 	cmd.AddCommand(product)
 
+	// Build the component management command tree.
+	component := bridge.Command("component", "Manage your Components")
+	component.PersistentFlags().AddFlag(envFlag)
+	component.PersistentFlags().AddFlag(customEndpointFlag)
+	component.PersistentFlags().AddFlag(orgIDFlag)
+	component.PersistentFlags().AddFlag(strictFlag)
+	component.AddCommand(applycomponent.Command())
+	component.AddCommand(fetchcomponent.Command())
+	component.AddCommand(sanitizecomponent.Command())
+	component.AddCommand(listcomponents.Command())
+	cmd.AddCommand(component)
+
+	// Build the authentication management command tree.
+	auth := bridge.Command("auth", "Manage authentication to the Catalog API")
+	auth.PersistentFlags().AddFlag(envFlag)
+	auth.PersistentFlags().AddFlag(customEndpointFlag)
+	auth.PersistentFlags().AddFlag(orgIDFlag)
+	keys := bridge.Command("keys", "Manage your organization's API keys")
+	keys.AddCommand(createapikey.Command())
+	keys.AddCommand(deleteapikey.Command())
+	keys.AddCommand(listapikeys.Command())
+	keys.AddCommand(rotateapikey.Command())
+	auth.AddCommand(keys)
+	cmd.AddCommand(auth)
+
 	// These commands and their subcommands require an API token to be
 	// configured. cobra.MatchAll is a bit of a misnomer, intended for use with
 	// cobra.PositionalArgs. In effect, we use it to chain together multiple
@@ -86,11 +147,21 @@ func RootCmd() *cobra.Command {
 		configureCLIPreRunE,
 		ensureAtLeastOneTokenConfigured,
 	)
+	component.PersistentPreRunE = cobra.MatchAll(
+		configureCLIPreRunE,
+		ensureAtLeastOneTokenConfigured,
+	)
+	auth.PersistentPreRunE = cobra.MatchAll(
+		configureCLIPreRunE,
+		ensureAtLeastOneTokenConfigured,
+	)
 
 	// Bind flags to configuration
 	rawC := cli.RawConfig()
 	_ = rawC.BindPFlag(cli.FlagIDLogLevel, cmd.PersistentFlags().Lookup(cli.FlagIDLogLevel))
 	_ = rawC.BindPFlag(cli.FlagIDEnv, commonFlags.Lookup(cli.FlagIDEnv))
+	_ = rawC.BindPFlag(cli.FlagIDOrgID, commonFlags.Lookup(cli.FlagIDOrgID))
+	_ = rawC.BindPFlag(cli.FlagIDStrict, commonFlags.Lookup(cli.FlagIDStrict))
 
 	return cmd
 }
@@ -118,7 +189,11 @@ func configureCLIPreRunE(cmd *cobra.Command, args []string) error {
 
 var ErrMinOneAPITokenConfig = errors.New("either api-token or api-token-file must be configured in your config file or environment")
 
-func ensureAtLeastOneTokenConfigured(_ *cobra.Command, _ []string) error {
+func ensureAtLeastOneTokenConfigured(cmd *cobra.Command, _ []string) error {
+	if _, offline := cmd.Annotations[cli.AnnotationOffline]; offline {
+		return nil
+	}
+
 	cfg, err := cli.Config()
 	if err != nil {
 		return errors.Join(ErrConfiguringCLI, err)
@@ -8,18 +8,22 @@ import (
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 
+	"github.com/opdev/productctl/internal/cli"
 	libdiscovery "github.com/opdev/productctl/internal/discovery"
 
 	"github.com/opdev/productctl/internal/resource"
 )
 
-var flagDiscoveredWorkloadManifestFilename string
+var (
+	flagDiscoveredWorkloadManifestFilename string
+	flagSchemaURL                          string
+)
 
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create <your-declaration.yaml>",
 		Short: "Start building a new product listing declaration on your filesystem",
-		Long:  "Scaffolds a new product listing for you to the specified filename. The contents will be a base template for you to update.",
+		Long:  "Scaffolds a new product listing for you to the specified filename. The contents will be a base template for you to update. The template begins with a yaml-language-server modeline referencing the product listing schema, so that editors supporting it validate and autocomplete the declaration.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			return writeInitialTemplate(args[0])
@@ -33,6 +37,13 @@ func Command() *cobra.Command {
 		"Path to the discovered workload manifest.",
 	)
 
+	cmd.PersistentFlags().StringVar(
+		&flagSchemaURL,
+		cli.FlagIDSchemaURL,
+		resource.SchemaID,
+		"The location of the product listing schema referenced by the modeline, e.g. a file written by the jsonschema command. Set to an empty string to omit the modeline.",
+	)
+
 	return cmd
 }
 
@@ -60,6 +71,10 @@ func writeInitialTemplate(toFile string) error {
 		return err
 	}
 
+	if flagSchemaURL != "" {
+		b = append([]byte(resource.SchemaModeline(flagSchemaURL)), b...)
+	}
+
 	err = os.WriteFile(toFile, b, 0o644)
 	if err != nil {
 		return err
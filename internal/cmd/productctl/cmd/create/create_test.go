@@ -12,6 +12,7 @@ import (
 	"github.com/opdev/productctl/internal/cli"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/create"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
 )
 
 const testfixturesdir = "../testutils/testdata"
@@ -69,6 +70,30 @@ var _ = Describe("Create", func() {
 				Expect(stat.Size).ToNot(BeZero())
 			})
 
+			It("should reference the schema in a modeline", func() {
+				_, err := testutils.ExecuteCommand(create.Command(), outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				b, err := os.ReadFile(outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(b)).To(HavePrefix("# yaml-language-server: $schema=" + resource.SchemaID + "\n"))
+			})
+
+			It("should reference the provided schema location", func() {
+				_, err := testutils.ExecuteCommand(create.Command(), "--schema-url", "./productlisting.schema.json", outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				b, err := os.ReadFile(outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(b)).To(HavePrefix("# yaml-language-server: $schema=./productlisting.schema.json\n"))
+			})
+
+			It("should omit the modeline if no schema location is provided", func() {
+				_, err := testutils.ExecuteCommand(create.Command(), "--schema-url", "", outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				b, err := os.ReadFile(outputFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(b)).ToNot(ContainSubstring("yaml-language-server"))
+			})
+
 			When("a discover JSON is provided", func() {
 				It("should include components in the discovery", func() {
 					_, err := testutils.ExecuteCommand(create.Command(), fmt.Sprintf("--%s", cli.FlagIDFromDiscoveryJSON), tempDiscoveryJSON, outputFile)
@@ -0,0 +1,101 @@
+// Package createapikey implements the auth keys create subcommand.
+package createapikey
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a new API key for your organization",
+		Long: `Create a new API key for your organization and print it to stdout.
+
+The API key value is only available at creation time. Store it somewhere safe, e.g. the file configured as your api-token-file.`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	cmd.Flags().String(cli.FlagIDDescription, "", "A description for the new API key")
+	_ = cmd.MarkFlagRequired(cli.FlagIDDescription)
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
+	return cmd
+}
+
+// createdKey is the output representation of a newly created API key.
+type createdKey struct {
+	*genpyxis.APIKeySupportedFields
+	APIKey string `json:"api_key"`
+}
+
+// columns renders a created key as a table.
+var columns = &printer.Columns[createdKey]{
+	Header: []string{"ID", "DESCRIPTION", "API KEY"},
+	Rows: func(k createdKey) [][]string {
+		return [][]string{{strconv.Itoa(k.GetId()), k.GetDescription(), k.APIKey}}
+	},
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	description, _ := cmd.Flags().GetString(cli.FlagIDDescription)
+
+	return run(cmd.Context(), cmd.OutOrStdout(), p, cfg.OrgID, description, token, endpoint)
+}
+
+func run(ctx context.Context, out io.Writer, p *printer.Printer[createdKey], orgID int, description string, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	key, keyData, err := catalogapi.CreateAPIKey(ctx, client, orgID, description)
+	if err != nil {
+		return err
+	}
+
+	L.Info("created api key", "id", keyData.GetId())
+	return p.Print(out, createdKey{APIKeySupportedFields: keyData, APIKey: key})
+}
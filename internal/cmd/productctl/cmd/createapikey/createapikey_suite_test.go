@@ -0,0 +1,13 @@
+package createapikey_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CreateAPIKey Suite")
+}
@@ -0,0 +1,46 @@
+package createapikey_test
+
+import (
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("CreateAPIKey", func() {
+	When("using the auth keys create command", func() {
+		It("should fail if the minimum environment variables are not set", func() {
+			output, err := testutils.ExecuteCommand(cmd.RootCmd(), "auth", "keys", "create", "--description", "test", "--org-id", "1", "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(HaveOccurred())
+			Expect(output).To(ContainSubstring(cmd.ErrMinOneAPITokenConfig.Error()))
+		})
+
+		When("the appropriate environment variables are in place", func() {
+			BeforeEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+			})
+
+			AfterEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "")
+			})
+
+			It("should require a description", func() {
+				_, err := testutils.ExecuteCommand(cmd.RootCmd(), "auth", "keys", "create", "--org-id", "1", "--custom-endpoint", "http://localhost:9630")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("description"))
+			})
+
+			It("should reach the creation phase, then fail", func() {
+				// Endpoint is spoofed to avoid spamming actual endpoints with requests
+				output, err := testutils.ExecuteCommand(cmd.RootCmd(), "auth", "keys", "create", "--description", "test", "--org-id", "1", "--custom-endpoint", "http://localhost:9630")
+				// We still expect an error here until business logic mocks have been implemented.
+				Expect(err).To(HaveOccurred())
+				Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+			})
+		})
+	})
+})
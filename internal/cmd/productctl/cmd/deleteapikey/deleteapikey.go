@@ -0,0 +1,78 @@
+// Package deleteapikey implements the auth keys delete subcommand.
+package deleteapikey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <key-id>",
+		Short: "Deletes the API key with the specified key ID",
+		Long: `Deletes the API key with the specified key ID
+
+This should be considered a destructive operation. Any tooling still using the deleted key will no longer be able to authenticate.`,
+		Args: cobra.ExactArgs(1), // The key ID
+		RunE: runE,
+	}
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	keyID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("key ID must be an integer: %w", err)
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	return run(cmd.Context(), cfg.OrgID, keyID, token, endpoint)
+}
+
+func run(ctx context.Context, orgID int, keyID int, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+	L.Info("deleting api key", "id", keyID)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	if err := catalogapi.DeleteAPIKey(ctx, client, orgID, keyID); err != nil {
+		return err
+	}
+
+	L.Info("done")
+	return nil
+}
@@ -0,0 +1,13 @@
+package deleteapikey_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDeleteAPIKey(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DeleteAPIKey Suite")
+}
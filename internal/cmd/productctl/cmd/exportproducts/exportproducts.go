@@ -0,0 +1,167 @@
+// Package exportproducts implements the product export subcommand.
+package exportproducts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+var ErrExportIncomplete = errors.New("some product listings could not be exported")
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export --dir <directory>",
+		Short: "Write a declaration for every product listing in your organization to a directory",
+		Long: `Write a declaration for every product listing in your organization that has not been deleted to a directory.
+
+Each declaration is written to a file named after its product listing and its ID, e.g. "my-product-<id>.product.yaml", so that the same product listing is always exported to the same file. Existing files with the same name are overwritten.
+
+Only components attached to each product listing with an "active" status will be included.`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	cmd.Flags().String(cli.FlagIDDir, "", "The directory to write declarations to. Created if it does not exist")
+	cmd.Flags().Bool(cli.FlagIDSanitize, false, "Remove data that ties each declaration to its product listing in the backend, for re-use")
+	cmd.Flags().String(cli.FlagIDNameFilter, "", "Only export product listings with names containing this value. Case insensitive")
+	cmd.Flags().Int(cli.FlagIDConcurrency, catalogapi.DefaultConcurrency, "The number of product listings to fetch at the same time")
+	_ = cmd.MarkFlagRequired(cli.FlagIDDir)
+
+	return cmd
+}
+
+type options struct {
+	dir         string
+	sanitize    bool
+	nameFilter  string
+	concurrency int
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	var opts options
+	opts.dir, _ = cmd.Flags().GetString(cli.FlagIDDir)
+	opts.sanitize, _ = cmd.Flags().GetBool(cli.FlagIDSanitize)
+	opts.nameFilter, _ = cmd.Flags().GetString(cli.FlagIDNameFilter)
+	opts.concurrency, _ = cmd.Flags().GetInt(cli.FlagIDConcurrency)
+
+	return run(cmd.Context(), opts, cfg.OrgID, token, endpoint)
+}
+
+func run(ctx context.Context, opts options, orgID int, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	listings, err := catalogapi.ListProducts(ctx, client, orgID)
+	if err != nil {
+		return err
+	}
+
+	listings = filterByName(listings, opts.nameFilter)
+	if len(listings) == 0 {
+		L.Info("no product listings to export")
+		return nil
+	}
+
+	if err := os.MkdirAll(opts.dir, 0o755); err != nil {
+		return err
+	}
+
+	namesByID := make(map[string]string, len(listings))
+	listingIDs := make([]string, 0, len(listings))
+	for _, l := range listings {
+		namesByID[l.GetId()] = l.GetName()
+		listingIDs = append(listingIDs, l.GetId())
+	}
+	filenames := file.DeclarationFilenames(namesByID)
+
+	L.Info("exporting product listings", "count", len(listingIDs), "dir", opts.dir)
+	var errs []error
+	for _, result := range catalogapi.PopulateProducts(ctx, client, listingIDs, opts.concurrency) {
+		if result.Err != nil {
+			L.Error("unable to fetch product listing", "_id", result.ListingID, "error", result.Err)
+			errs = append(errs, fmt.Errorf("product listing %s: %w", result.ListingID, result.Err))
+			continue
+		}
+
+		if opts.sanitize {
+			result.Declaration.Sanitize()
+		}
+
+		b, err := yaml.Marshal(result.Declaration)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("product listing %s: %w", result.ListingID, err))
+			continue
+		}
+
+		path := filepath.Join(opts.dir, filenames[result.ListingID])
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("product listing %s: %w", result.ListingID, err))
+			continue
+		}
+
+		L.Info("exported product listing", "_id", result.ListingID, "file", path)
+	}
+
+	if len(errs) > 0 {
+		L.Error("export incomplete", "exported", len(listingIDs)-len(errs), "failed", len(errs))
+		return errors.Join(append([]error{ErrExportIncomplete}, errs...)...)
+	}
+
+	L.Info("export complete", "exported", len(listingIDs))
+	return nil
+}
+
+// filterByName returns listings whose names contain nameFilter, ignoring case.
+func filterByName(listings []*genpyxis.ProductListingSupportedFields, nameFilter string) []*genpyxis.ProductListingSupportedFields {
+	nameFilter = strings.ToLower(nameFilter)
+
+	filtered := make([]*genpyxis.ProductListingSupportedFields, 0, len(listings))
+	for _, l := range listings {
+		if strings.Contains(strings.ToLower(l.GetName()), nameFilter) {
+			filtered = append(filtered, l)
+		}
+	}
+
+	return filtered
+}
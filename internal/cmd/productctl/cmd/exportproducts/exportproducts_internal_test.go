@@ -0,0 +1,17 @@
+package exportproducts
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+)
+
+var _ = Describe("ExportProducts (internal)", func() {
+	When("filtering listings by name", func() {
+		It("should ignore case", func() {
+			filtered := filterByName([]*genpyxis.ProductListingSupportedFields{{Name: "My Product"}, {Name: "Other"}}, "my")
+			Expect(filtered).To(HaveLen(1))
+		})
+	})
+})
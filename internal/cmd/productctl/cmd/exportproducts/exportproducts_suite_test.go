@@ -0,0 +1,13 @@
+package exportproducts_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestExportProducts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ExportProducts Suite")
+}
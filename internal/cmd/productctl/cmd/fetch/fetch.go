@@ -2,8 +2,13 @@
 package fetch
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 
 	"github.com/Khan/genqlient/graphql"
 	"github.com/spf13/cobra"
@@ -11,29 +16,67 @@ import (
 
 	"github.com/opdev/productctl/internal/catalogapi"
 	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
 	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrFetchIncomplete      = errors.New("some product listings could not be fetched")
+	ErrMarkdownRequiresYAML = errors.New("--markdown requires YAML output")
 )
 
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "fetch <productID>",
-		Short: "Get a pre-existing product listing",
-		Long: `Get data about a pre-existing product listing by its ID and generate its declaration for storage on disk.
+		Use:   "fetch <productID> [<productID>...]",
+		Short: "Get pre-existing product listings",
+		Long: `Get data about pre-existing product listings by their IDs and generate their declarations for storage on disk.
+
+Only components attached to these product listings with an "active" status will be returned.
+
+When more than one product listing ID is provided, the declarations are emitted as a multi-document YAML stream, in the order the IDs were provided. If some product listings cannot be fetched, the others are still emitted and the command reports each failure.
+
+This command does not overwrite an existing file, and relies in output redirection to store the contents to disk at any location you would prefer. Alternatively, use --output-dir to write each declaration to its own file, named after its product listing and its ID.
+
+Use --output to emit the declarations in another format, e.g. -o json, or -o jsonpath={.with.components[*]._id} to print only component IDs.
 
-Only components attached to this product listing with an "active" status will be returned.
+Use --markdown to write fields that hold HTML, such as FAQ answers, in Markdown where the Markdown renders as the same HTML. Other HTML is written as it is.
 
-This command does not overwrite an existing file, and relies in output redirection to store the contents to disk at any location you would prefer.
+Use --declaration to provide the declarations the product listings were applied from. Components that a declaration references under with.component_refs are then kept as references, by ID or by file, rather than embedded in the fetched declaration. Declarations are matched to product listings by ID, or to the only product listing fetched if the file holds a single declaration.
 `,
 		Args: cobra.MinimumNArgs(1),
 		RunE: getProductListingRunE,
 	}
 
+	cmd.Flags().String(cli.FlagIDOutputDir, "", "Write each declaration to its own file in this directory instead of stdout. Created if it does not exist")
+	cmd.Flags().Int(cli.FlagIDConcurrency, catalogapi.DefaultConcurrency, "The number of product listings to fetch at the same time")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+	cmd.Flags().Bool(cli.FlagIDMarkdown, false, "Write fields that hold HTML in Markdown where possible. Requires YAML output")
+	cmd.Flags().String(cli.FlagIDDeclaration, "", "A file holding the declarations of the product listings, whose component references are kept")
+	cmd.MarkFlagsMutuallyExclusive(cli.FlagIDOutput, cli.FlagIDOutputDir)
+
 	return cmd
 }
 
 func getProductListingRunE(cmd *cobra.Command, args []string) error {
 	L := logger.FromContextOrDiscard(cmd.Context())
 
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, printer.DeclarationColumns)
+	if err != nil {
+		return err
+	}
+
+	marshal := yaml.Marshal
+	if markdown, _ := cmd.Flags().GetBool(cli.FlagIDMarkdown); markdown {
+		if output != printer.FormatYAML {
+			return ErrMarkdownRequiresYAML
+		}
+		marshal = resource.MarshalMarkdown
+		p = nil
+	}
+
 	cfg, err := cli.Config()
 	if err != nil {
 		return err
@@ -44,8 +87,6 @@ func getProductListingRunE(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	productID := args[0]
-
 	var endpoint string
 	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
 		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
@@ -58,22 +99,178 @@ func getProductListingRunE(cmd *cobra.Command, args []string) error {
 		L.Debug("endpoint resolved", "endpoint", endpoint)
 	}
 
-	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
-	client := graphql.NewClient(endpoint, httpClient)
+	var declared []*resource.ProductListingDeclaration
+	if filename, _ := cmd.Flags().GetString(cli.FlagIDDeclaration); filename != "" {
+		if declared, err = readDeclared(filename, cfg.Env, resource.WithStrict(cfg.Strict)); err != nil {
+			return err
+		}
+	}
+
+	outputDir, _ := cmd.Flags().GetString(cli.FlagIDOutputDir)
+	concurrency, _ := cmd.Flags().GetInt(cli.FlagIDConcurrency)
 
-	newListing, err := catalogapi.PopulateProduct(cmd.Context(), client, productID)
+	return run(cmd.Context(), cmd.OutOrStdout(), p, marshal, outputDir, concurrency, args, declared, token, endpoint)
+}
+
+// readDeclared reads the declarations in filename, resolving the components
+// they reference with their IDs in the overlay of that name.
+func readDeclared(filename string, overlay string, readOpts ...resource.ReadOption) ([]*resource.ProductListingDeclaration, error) {
+	f, err := os.Open(filename)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer f.Close()
 
-	b, err := yaml.Marshal(newListing)
+	baseDir := filepath.Dir(filename)
+	stream, err := resource.ReadDeclarationStream(f, append(slices.Clip(readOpts), resource.WithBaseDir(baseDir))...)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("%s: %w", filename, err)
 	}
-	_, err = fmt.Fprint(os.Stdout, string(b))
-	if err != nil {
-		return err
+
+	declarations := stream.Declarations()
+	for i, d := range declarations {
+		if err := d.ResolveComponentReferences(baseDir, overlay, readOpts...); err != nil {
+			return nil, fmt.Errorf("%s: declaration %d (%s): %w", filename, i+1, d.Spec.Name, err)
+		}
+	}
+
+	return declarations, nil
+}
+
+// keepComponentRefs keeps the component references of each of declared in
+// the fetched declaration of the same product listing. A single declaration
+// is matched to a single fetched declaration whatever its ID, as its ID may
+// be held by an overlay.
+func keepComponentRefs(fetched, declared []*resource.ProductListingDeclaration) {
+	if len(fetched) == 1 && len(declared) == 1 {
+		fetched[0].KeepComponentRefs(declared[0].With.ComponentRefs)
+		return
+	}
+
+	for _, f := range fetched {
+		for _, d := range declared {
+			if d.Spec.ID == f.Spec.ID {
+				f.KeepComponentRefs(d.With.ComponentRefs)
+				break
+			}
+		}
+	}
+}
+
+// run fetches the product listings and prints them with p, or writes them to
+// outputDir if it is set. If p is nil, the declarations are printed as a YAML
+// stream, each written with marshal, which is also used to write them to
+// outputDir. The component references of declared are kept in the fetched
+// declarations.
+func run(
+	ctx context.Context,
+	out io.Writer,
+	p *printer.Printer[*resource.ProductListingDeclaration],
+	marshal func(any) ([]byte, error),
+	outputDir string,
+	concurrency int,
+	productIDs []string,
+	declared []*resource.ProductListingDeclaration,
+	token string,
+	endpoint catalogapi.APIEndpoint,
+) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	results := catalogapi.PopulateProducts(ctx, client, productIDs, concurrency)
+
+	var errs []error
+	fetched := make([]*resource.ProductListingDeclaration, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			L.Error("unable to fetch product listing", "_id", result.ListingID, "error", result.Err)
+			errs = append(errs, fmt.Errorf("product listing %s: %w", result.ListingID, result.Err))
+			continue
+		}
+
+		fetched = append(fetched, result.Declaration)
+	}
+
+	keepComponentRefs(fetched, declared)
+
+	switch {
+	case outputDir != "":
+		errs = append(errs, writeToDir(ctx, outputDir, fetched, marshal)...)
+	case p == nil:
+		if err := printStream(out, fetched, marshal); err != nil {
+			return err
+		}
+	default:
+		if err := p.Print(out, fetched...); err != nil {
+			return err
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrFetchIncomplete}, errs...)...)
 	}
 
 	return nil
 }
+
+// printStream writes declarations to out as a YAML stream, each written with
+// marshal.
+func printStream(out io.Writer, declarations []*resource.ProductListingDeclaration, marshal func(any) ([]byte, error)) error {
+	for i, d := range declarations {
+		if i > 0 {
+			if _, err := io.WriteString(out, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		b, err := marshal(d)
+		if err != nil {
+			return err
+		}
+
+		if _, err := out.Write(b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeToDir writes each of declarations to its own file in dir, written with
+// marshal. Errors are returned for each declaration that could not be
+// written.
+func writeToDir(ctx context.Context, dir string, declarations []*resource.ProductListingDeclaration, marshal func(any) ([]byte, error)) []error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return []error{err}
+	}
+
+	namesByID := make(map[string]string, len(declarations))
+	for _, d := range declarations {
+		namesByID[d.Spec.ID] = d.Spec.Name
+	}
+	filenames := file.DeclarationFilenames(namesByID)
+
+	var errs []error
+	for _, d := range declarations {
+		id := d.Spec.ID
+		b, err := marshal(d)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("product listing %s: %w", id, err))
+			continue
+		}
+
+		path := filepath.Join(dir, filenames[id])
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			errs = append(errs, fmt.Errorf("product listing %s: %w", id, err))
+			continue
+		}
+
+		L.Info("wrote product listing declaration", "_id", id, "file", path)
+	}
+
+	return errs
+}
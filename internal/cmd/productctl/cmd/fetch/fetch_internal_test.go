@@ -0,0 +1,72 @@
+package fetch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Fetch (internal)", func() {
+	var declarations []*resource.ProductListingDeclaration
+
+	BeforeEach(func() {
+		first := resource.NewProductListing()
+		first.Spec.ID = "111"
+		first.Spec.Name = "first"
+		third := resource.NewProductListing()
+		third.Spec.ID = "333"
+		third.Spec.Name = "third"
+		declarations = []*resource.ProductListingDeclaration{&first, &third}
+	})
+
+	When("writing declarations to a directory", func() {
+		It("should write each declaration to a file named after its listing", func() {
+			dir := filepath.Join(GinkgoT().TempDir(), "out")
+			Expect(writeToDir(GinkgoT().Context(), dir, declarations, yaml.Marshal)).To(BeEmpty())
+
+			content, err := os.ReadFile(filepath.Join(dir, "first-111.product.yaml"))
+			Expect(err).ToNot(HaveOccurred())
+			expected, err := yaml.Marshal(declarations[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(Equal(expected))
+			Expect(filepath.Join(dir, "third-333.product.yaml")).To(BeAnExistingFile())
+		})
+	})
+	When("keeping the component references of declarations", func() {
+		It("should reference the components the declaration of the same listing referenced", func() {
+			dir := GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "shared.yaml"), []byte("kind: Component\nspec:\n  _id: shared-id\n  name: shared\n"), 0o644)).To(Succeed())
+			declaration := filepath.Join(dir, "listings.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  _id: \"333\"\nwith:\n  component_refs:\n  - file: shared.yaml\n"), 0o644)).To(Succeed())
+			for _, d := range declarations {
+				d.With.Components = []*resource.Component{{ID: "shared-id", Name: "shared"}}
+			}
+
+			declared, err := readDeclared(declaration, "")
+			Expect(err).ToNot(HaveOccurred())
+			keepComponentRefs(declarations, declared)
+
+			Expect(declarations[0].With.Components).To(HaveLen(1))
+			Expect(declarations[0].With.ComponentRefs).To(BeEmpty())
+			Expect(declarations[1].With.Components).To(BeEmpty())
+			Expect(declarations[1].With.ComponentRefs).To(HaveExactElements(HaveField("File", "shared.yaml")))
+		})
+	})
+
+	When("writing fields that hold HTML in Markdown", func() {
+		It("should print a YAML stream of declarations written in Markdown", func() {
+			declarations[0].Spec.FAQs = []resource.FAQ{{Question: "Supported?", Answer: "<p>Yes, <em>always</em>.</p>"}}
+
+			var out bytes.Buffer
+			Expect(printStream(&out, declarations, resource.MarshalMarkdown)).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("value: Yes, *always*."))
+			Expect(out.String()).To(ContainSubstring("\n---\n"))
+		})
+	})
+})
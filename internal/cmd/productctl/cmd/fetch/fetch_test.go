@@ -8,7 +8,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/fetch"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/printer"
 )
 
 var _ = Describe("Fetch", func() {
@@ -56,6 +58,25 @@ var _ = Describe("Fetch", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
 				})
+
+				It("should report a failure for each product listing ID", func() {
+					// Endpoint is spoofed to avoid spamming actual endpoints with requests
+					output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "fetch", listingID, "456", "--custom-endpoint", "http://localhost:9630")
+					Expect(err).To(MatchError(fetch.ErrFetchIncomplete))
+					Expect(output).To(ContainSubstring("product listing " + listingID))
+					Expect(output).To(ContainSubstring("product listing 456"))
+				})
+
+				It("should reject unknown output formats before fetching", func() {
+					output, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "fetch", listingID, "-o", "xml", "--custom-endpoint", "http://localhost:9630")
+					Expect(err).To(MatchError(printer.ErrUnknownFormat))
+					Expect(output).ToNot(ContainSubstring(syscall.ECONNREFUSED.Error()))
+				})
+
+				It("should not accept an output format alongside an output directory", func() {
+					_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "fetch", listingID, "-o", "json", "--output-dir", GinkgoT().TempDir(), "--custom-endpoint", "http://localhost:9630")
+					Expect(err).To(HaveOccurred())
+				})
 			})
 		})
 	})
@@ -0,0 +1,108 @@
+// Package fetchcomponent implements the component fetch subcommand.
+package fetchcomponent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrFetchIncomplete = errors.New("some components could not be fetched")
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fetch <componentID> [<componentID>...]",
+		Short: "Get pre-existing components",
+		Long: `Get data about pre-existing components by their IDs and generate declarations of kind Component for storage on disk.
+
+When more than one component ID is provided, the declarations are emitted as a multi-document YAML stream, in the order the IDs were provided. If some components cannot be fetched, the others are still emitted and the command reports each failure.
+
+This command does not overwrite an existing file, and relies in output redirection to store the contents to disk at any location you would prefer.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: fetchComponentRunE,
+	}
+
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
+	return cmd
+}
+
+func fetchComponentRunE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, printer.ComponentColumns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	return run(cmd.Context(), cmd.OutOrStdout(), p, client, args)
+}
+
+func run(
+	ctx context.Context,
+	out io.Writer,
+	p *printer.Printer[*resource.ComponentDeclaration],
+	client graphql.Client,
+	componentIDs []string,
+) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	var errs []error
+	fetched := make([]*resource.ComponentDeclaration, 0, len(componentIDs))
+	for _, id := range componentIDs {
+		component, err := catalogapi.PopulateComponent(ctx, client, id)
+		if err != nil {
+			L.Error("unable to fetch component", "_id", id, "error", err)
+			errs = append(errs, fmt.Errorf("component %s: %w", id, err))
+			continue
+		}
+
+		fetched = append(fetched, component)
+	}
+
+	if err := p.Print(out, fetched...); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrFetchIncomplete}, errs...)...)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package fetchcomponent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// stubClient returns a component for any ID except "missing".
+func stubClient() *testutils.FakeClient {
+	return &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"ComponentByID": func(variables map[string]any) (any, error) {
+			if variables["componentID"] == "missing" {
+				return nil, errors.New("not found")
+			}
+
+			return json.RawMessage(`{"get_certification_project":{"data":{"_id":"found","name":"base-image","type":"Containers"}}}`), nil
+		},
+	}}
+}
+
+var _ = Describe("FetchComponent (internal)", func() {
+	It("should emit the components it could fetch and report the others", func() {
+		p, err := printer.New(printer.FormatYAML, printer.ComponentColumns)
+		Expect(err).ToNot(HaveOccurred())
+
+		out := &bytes.Buffer{}
+		err = run(context.TODO(), out, p, stubClient(), []string{"found", "missing"})
+		Expect(err).To(MatchError(ErrFetchIncomplete))
+		Expect(err.Error()).To(ContainSubstring("component missing: not found"))
+
+		component, err := resource.ReadComponent(out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(component.Spec.ID).To(Equal("found"))
+	})
+})
@@ -0,0 +1,13 @@
+package fetchcomponent_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestFetchComponent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "FetchComponent Suite")
+}
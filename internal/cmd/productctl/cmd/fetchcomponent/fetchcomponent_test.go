@@ -0,0 +1,43 @@
+package fetchcomponent_test
+
+import (
+	"os"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/fetchcomponent"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("FetchComponent", func() {
+	It("should fail if the minimum environment variables are not set", func() {
+		output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "fetch", "some-id", "--custom-endpoint", "http://localhost:9630")
+		Expect(err).To(HaveOccurred())
+		Expect(output).To(ContainSubstring(cmd.ErrMinOneAPITokenConfig.Error()))
+	})
+
+	When("the appropriate environment variables are in place", func() {
+		BeforeEach(func() {
+			os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+		})
+
+		AfterEach(func() {
+			os.Setenv("PRODUCTCTL_API_TOKEN", "")
+		})
+
+		It("should reach the fetch phase, then fail", func() {
+			output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "fetch", "some-id", "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(MatchError(fetchcomponent.ErrFetchIncomplete))
+			Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+		})
+
+		It("should reject unknown output formats", func() {
+			_, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "fetch", "some-id", "-o", "xml", "--custom-endpoint", "http://localhost:9630")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown output format"))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+// Package importmarkdown implements the import-faqs and import-features
+// subcommands, which merge content written in Markdown into a declaration.
+package importmarkdown
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrNotOneDeclaration = errors.New("the file must contain exactly one product listing declaration")
+
+// importer reads entries from a Markdown document and merges them into a
+// declaration, returning the number of entries added and replaced.
+type importer func(markdown string, declaration *resource.ProductListingDeclaration) (added, replaced int, err error)
+
+func FAQsCommand() *cobra.Command {
+	return command(
+		"import-faqs",
+		"FAQs",
+		"Merges FAQs written in Markdown into a declaration",
+		"question",
+		func(markdown string, declaration *resource.ProductListingDeclaration) (int, int, error) {
+			faqs, err := resource.ImportFAQs(markdown)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			var added, replaced int
+			declaration.Spec.FAQs, added, replaced = resource.MergeFAQs(declaration.Spec.FAQs, faqs)
+			return added, replaced, nil
+		},
+	)
+}
+
+func FeaturesCommand() *cobra.Command {
+	return command(
+		"import-features",
+		"features",
+		"Merges features written in Markdown into a declaration",
+		"title",
+		func(markdown string, declaration *resource.ProductListingDeclaration) (int, int, error) {
+			features, err := resource.ImportFeatures(markdown)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			var added, replaced int
+			declaration.Spec.Features, added, replaced = resource.MergeFeatures(declaration.Spec.Features, features)
+			return added, replaced, nil
+		},
+	)
+}
+
+func command(name, entries, short, key string, imp importer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   name + " <" + entries + ".md> <your-declaration.yaml>",
+		Short: short,
+		Long: fmt.Sprintf(`Reads %[1]s from a Markdown document, where each second level heading, e.g. "## Heading", is the %[2]s of an entry, and the Markdown under it is its content, rendered to HTML. Content before the first such heading is ignored.
+
+Each entry replaces the entry of the declaration with the same %[2]s, or is added after the existing entries. Other entries are left as they are. Entries that exceed the limits of the schema, such as their length, are reported, and nothing is changed.
+
+The declaration file is updated in place. Does not contact the backend.`, entries, key),
+		Args: cobra.ExactArgs(2),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd, args, entries, imp)
+		},
+	}
+
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite.")
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string, entries string, imp importer) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	filename := args[1]
+	backupOnOverwrite, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
+	overwriters := &file.Overwriters{DoBackup: backupOnOverwrite, OptionalLogger: L.With("name", "fileIO")}
+
+	added, replaced, err := importFile(args[0], filename, imp, overwriters, resource.WithStrict(cfg.Strict), resource.WithWarningHandler(func(warning string) {
+		L.Warn(warning, "source", filename)
+	}))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "%d %s added, %d updated in %s\n", added, entries, replaced, filename)
+	return nil
+}
+
+// importFile merges the entries of the Markdown document source into the
+// declaration in filename, writing the declaration, and any files it includes
+// that changed, with overwriters.
+func importFile(source, filename string, imp importer, overwriters *file.Overwriters, readOpts ...resource.ReadOption) (added, replaced int, err error) {
+	markdown, err := os.ReadFile(source)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stream, err := resource.ReadDeclarationStream(f, append(readOpts, resource.WithBaseDir(filepath.Dir(filename)))...)
+	f.Close()
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	declarations := stream.Declarations()
+	if len(declarations) != 1 {
+		return 0, 0, fmt.Errorf("%s: %w, found %d", filename, ErrNotOneDeclaration, len(declarations))
+	}
+
+	if added, replaced, err = imp(string(markdown), declarations[0]); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", source, err)
+	}
+
+	if err := stream.Replace(0, declarations[0]); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if err := overwriters.WriteAll(stream.IncludeUpdates()); err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := overwriters.For(filename).Write(stream.Bytes()); err != nil {
+		return 0, 0, err
+	}
+
+	return added, replaced, nil
+}
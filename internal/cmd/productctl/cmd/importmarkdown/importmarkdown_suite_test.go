@@ -0,0 +1,13 @@
+package importmarkdown_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestImportMarkdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ImportMarkdown Suite")
+}
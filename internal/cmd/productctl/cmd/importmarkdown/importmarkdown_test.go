@@ -0,0 +1,109 @@
+package importmarkdown_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/importmarkdown"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+const declaration = `# yaml-language-server: $schema=https://example.com/schema.json
+apiVersion: productctl.opdev.io/v1
+kind: ProductListing
+spec:
+  name: my product
+  faqs:
+  - question: Is it supported?
+    answer: <p>Maybe.</p>
+  - question: Who maintains it?
+    answer: <p>We do.</p>
+`
+
+var _ = Describe("ImportMarkdown", func() {
+	var dir string
+
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	readDeclaration := func(path string) *resource.ProductListingDeclaration {
+		f, err := os.Open(path)
+		Expect(err).ToNot(HaveOccurred())
+		defer f.Close()
+
+		declaration, err := resource.ReadProductListing(f)
+		Expect(err).ToNot(HaveOccurred())
+		return declaration
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	When("the Markdown document is not found", func() {
+		It("should throw an error", func() {
+			path := write("my.product.yaml", declaration)
+			_, err := testutils.ExecuteCommand(importmarkdown.FAQsCommand(), "missing.md", path)
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+	})
+
+	It("should merge FAQs by question, keeping unrelated FAQs", func() {
+		path := write("my.product.yaml", declaration)
+		faqs := write("faq.md", "# FAQ\n\n## Is it supported?\n\nYes, *always*.\n\n## Is it free?\n\nNo.\n")
+
+		output, err := testutils.ExecuteCommand(importmarkdown.FAQsCommand(), faqs, path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("1 FAQs added, 1 updated"))
+
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(HavePrefix("# yaml-language-server"))
+		Expect(readDeclaration(path).Spec.FAQs).To(Equal([]resource.FAQ{
+			{Question: "Is it supported?", Answer: "<p>Yes, <em>always</em>.</p>"},
+			{Question: "Who maintains it?", Answer: "<p>We do.</p>"},
+			{Question: "Is it free?", Answer: "<p>No.</p>"},
+		}))
+	})
+
+	It("should merge features by title", func() {
+		path := write("my.product.yaml", declaration)
+		features := write("features.md", "## Fast\n\nIt is fast.\n")
+
+		_, err := testutils.ExecuteCommand(importmarkdown.FeaturesCommand(), features, path)
+		Expect(err).ToNot(HaveOccurred())
+		imported := readDeclaration(path)
+		Expect(imported.Spec.Features).To(Equal([]resource.ProductListingFeature{{Title: "Fast", Description: "<p>It is fast.</p>"}}))
+		Expect(imported.Spec.FAQs).To(HaveLen(2))
+	})
+
+	It("should change nothing if any entry exceeds the limits of the schema", func() {
+		path := write("my.product.yaml", declaration)
+		features := write("features.md", "## Fast\n\nIt is fast.\n\n## "+strings.Repeat("a", 61)+"\n\nToo long.\n")
+
+		_, err := testutils.ExecuteCommand(importmarkdown.FeaturesCommand(), features, path)
+		Expect(err).To(MatchError(resource.ErrInvalidImport))
+		Expect(err.Error()).To(ContainSubstring("must be at most 60 characters long"))
+
+		content, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal(declaration))
+	})
+
+	It("should refuse files holding several declarations", func() {
+		path := write("my.products.yaml", declaration+"---\n"+declaration)
+		faqs := write("faq.md", "## Is it free?\n\nNo.\n")
+
+		_, err := testutils.ExecuteCommand(importmarkdown.FAQsCommand(), faqs, path)
+		Expect(err).To(MatchError(importmarkdown.ErrNotOneDeclaration))
+	})
+})
@@ -1,12 +1,13 @@
 package jsonschema
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/invopop/jsonschema"
 	"github.com/spf13/cobra"
 
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/printer"
 	"github.com/opdev/productctl/internal/resource"
 )
 
@@ -14,20 +15,30 @@ func Command() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "jsonschema",
 		Short: "Generate resource jsonschema for LSPs that support it.",
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			// TODO: If this functionality will remain and be useful, we should
-			// add comments, enums, and warnings when things are immutable after
-			// being applied to the resource declaration itself.
-			schema := jsonschema.Reflect(&resource.ProductListingDeclaration{})
-			b, err := json.MarshalIndent(schema, "", "  ")
+			output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+			p, err := printer.New[*jsonschema.Schema](output, nil)
 			if err != nil {
 				return err
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), string(b))
-			return nil
+			kind, _ := cmd.Flags().GetString(cli.FlagIDKind)
+			switch kind {
+			case resource.KindProductListing:
+				return p.Print(cmd.OutOrStdout(), resource.JSONSchema())
+			case resource.KindComponent:
+				return p.Print(cmd.OutOrStdout(), resource.ComponentJSONSchema())
+			default:
+				return fmt.Errorf("%w %q, choose from %s, %s", resource.ErrUnknownKind, kind, resource.KindProductListing, resource.KindComponent)
+			}
 		},
 	}
 
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatJSON, printer.Usage(false))
+	cmd.Flags().String(cli.FlagIDKind, resource.KindProductListing, fmt.Sprintf("The kind of declaration to generate the schema for. Choose from %s, %s", resource.KindProductListing, resource.KindComponent))
+
 	return cmd
 }
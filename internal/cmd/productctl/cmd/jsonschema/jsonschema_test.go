@@ -19,7 +19,7 @@ var _ = Describe("jsonschema", func() {
 			args   []string
 		)
 
-		BeforeEach(func() {
+		JustBeforeEach(func() {
 			cmdOut, cmdErr = testutils.ExecuteCommand(jsonschema.Command(), args...)
 		})
 
@@ -43,5 +43,35 @@ var _ = Describe("jsonschema", func() {
 			Expect(cmdOut).To(ContainSubstring("github_usernames"))
 			Expect(cmdOut).To(ContainSubstring(resource.ContentTypeUBI))
 		})
+
+		When("the component kind is requested", func() {
+			BeforeEach(func() {
+				args = []string{"--kind", resource.KindComponent}
+			})
+
+			AfterEach(func() {
+				args = nil
+			})
+
+			It("should generate the component schema", func() {
+				Expect(cmdErr).ToNot(HaveOccurred())
+				Expect(cmdOut).To(ContainSubstring(resource.ComponentSchemaID))
+				Expect(cmdOut).ToNot(ContainSubstring("functional_categories"))
+			})
+		})
+
+		When("an unknown kind is requested", func() {
+			BeforeEach(func() {
+				args = []string{"--kind", "Unknown"}
+			})
+
+			AfterEach(func() {
+				args = nil
+			})
+
+			It("should fail", func() {
+				Expect(cmdErr).To(MatchError(resource.ErrUnknownKind))
+			})
+		})
 	})
 })
@@ -0,0 +1,157 @@
+// Package lintproducts implements the lint subcommand.
+package lintproducts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/lint"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrLintFailed = errors.New("some declarations do not follow every lint rule")
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint <your-declaration.yaml|directory|-> [...]",
+		Short: "Checks declarations against best practices, without contacting the backend",
+		Long: fmt.Sprintf(`Checks declarations against best practices that go beyond the constraints of the product listing schema, such as the use of HTTPS URLs, reporting each finding with its location. Use validate to check declarations against the schema itself.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Use "-" to read declarations from stdin.
+
+Rules are disabled with --disable, or with lint-disable in your configuration. The rules are:
+
+%s
+Use --output to write findings as GitHub Actions annotations (github), or as a SARIF log (sarif) for code scanning services.`, ruleList()),
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: runE,
+	}
+
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringSlice(cli.FlagIDDisable, nil, "The IDs of lint rules not to check, in addition to those disabled by lint-disable in your configuration")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", lint.FormatText, "The format findings are written in. One of: "+strings.Join(lint.Formats, ", "))
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
+
+	return cmd
+}
+
+// ruleList describes each rule, one per line.
+func ruleList() string {
+	var b strings.Builder
+	for _, rule := range lint.Rules() {
+		fmt.Fprintf(&b, "  %-28s %s (%s)\n", rule.ID, rule.Description, rule.Level)
+	}
+
+	return b.String()
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	if !slices.Contains(lint.Formats, output) {
+		return fmt.Errorf("%w %q, expected one of %s", lint.ErrUnknownFormat, output, strings.Join(lint.Formats, ", "))
+	}
+
+	disabled, _ := cmd.Flags().GetStringSlice(cli.FlagIDDisable)
+	linter, err := lint.New(append(slices.Clone(cfg.LintDisable), disabled...))
+	if err != nil {
+		return err
+	}
+
+	variables, err := cli.Variables(cmd)
+	if err != nil {
+		return err
+	}
+
+	readOpts := []resource.ReadOption{resource.WithStrict(cfg.Strict), resource.WithVariables(variables)}
+
+	var results []lint.Result
+	var errs []error
+	if len(args) == 1 && args[0] == "-" {
+		results, err = lintStream(cmd.Context(), linter, "stdin", cmd.InOrStdin(), readOpts...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
+		for _, arg := range args {
+			files, err := resource.DeclarationFiles(arg, recursive, resource.KindProductListing)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			for _, filename := range files {
+				found, err := lintFile(cmd.Context(), linter, filename, readOpts...)
+				results = append(results, found...)
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	if err := lint.Write(cmd.OutOrStdout(), output, linter.Rules(), results); err != nil {
+		return err
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if len(results) > 0 {
+		return fmt.Errorf("%w: %d finding(s)", ErrLintFailed, len(results))
+	}
+
+	return nil
+}
+
+func lintFile(ctx context.Context, linter *lint.Linter, filename string, readOpts ...resource.ReadOption) ([]lint.Result, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return lintStream(ctx, linter, filename, f, append(slices.Clip(readOpts), resource.WithBaseDir(filepath.Dir(filename)))...)
+}
+
+// lintStream lints each declaration read from in, locating each finding
+// within source.
+func lintStream(ctx context.Context, linter *lint.Linter, source string, in io.Reader, readOpts ...resource.ReadOption) ([]lint.Result, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	stream, err := resource.ReadDeclarationStream(in, append(slices.Clip(readOpts), resource.WithWarningHandler(func(warning string) {
+		L.Warn(warning, "source", source)
+	}))...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	var results []lint.Result
+	for i, declaration := range stream.Declarations() {
+		for _, finding := range linter.Lint(declaration) {
+			results = append(results, lint.Result{Finding: finding, File: source, Line: stream.Line(i, finding.Path)})
+		}
+	}
+
+	return results, nil
+}
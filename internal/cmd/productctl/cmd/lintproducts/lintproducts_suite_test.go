@@ -0,0 +1,13 @@
+package lintproducts_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLintProducts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LintProducts Suite")
+}
@@ -0,0 +1,137 @@
+package lintproducts_test
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/lintproducts"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/lint"
+)
+
+const (
+	cleanDeclaration = `kind: ProductListing
+spec:
+  name: clean-product
+  support:
+    url: https://example.com/support
+`
+	lintedDeclaration = `kind: ProductListing
+spec:
+  name: My New Product
+  support:
+    url: http://example.com/support
+`
+)
+
+var _ = Describe("Lint", func() {
+	var tempDirPath string
+
+	BeforeEach(func() {
+		var err error
+		tempDirPath, err = os.MkdirTemp("", "productctl-unit-test-*")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDirPath)).To(Succeed())
+	})
+
+	write := func(name, contents string) string {
+		path := filepath.Join(tempDirPath, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	When("the file is not found", func() {
+		It("should throw an error", func() {
+			_, err := testutils.ExecuteCommand(lintproducts.Command(), "foofile")
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+	})
+
+	It("should succeed when every declaration follows every rule", func() {
+		path := write("clean.yaml", cleanDeclaration)
+		output, err := testutils.ExecuteCommand(lintproducts.Command(), path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(BeEmpty())
+	})
+
+	It("should report every finding with its file and line", func() {
+		path := write("linted.yaml", cleanDeclaration+"---\n"+lintedDeclaration)
+		output, err := testutils.ExecuteCommand(lintproducts.Command(), path)
+		Expect(err).To(MatchError(lintproducts.ErrLintFailed))
+		Expect(output).To(ContainSubstring(path + ":9: error: spec.name: \"My New Product\" is placeholder text from product create [placeholder-text]"))
+		Expect(output).To(ContainSubstring(path + ":11: warning: spec.support.url: \"http://example.com/support\" should use HTTPS [https-url]"))
+	})
+
+	It("should lint declarations read from stdin", func() {
+		command := lintproducts.Command()
+		command.SetIn(strings.NewReader(lintedDeclaration))
+		output, err := testutils.ExecuteCommand(command, "-")
+		Expect(err).To(MatchError(lintproducts.ErrLintFailed))
+		Expect(output).To(ContainSubstring("stdin:3: error: spec.name: \"My New Product\" is placeholder text from product create [placeholder-text]"))
+	})
+
+	It("should lint every declaration in a directory", func() {
+		write("a.yaml", cleanDeclaration)
+		path := write("b.yaml", lintedDeclaration)
+		output, err := testutils.ExecuteCommand(lintproducts.Command(), tempDirPath)
+		Expect(err).To(MatchError(lintproducts.ErrLintFailed))
+		Expect(output).To(ContainSubstring(path + ":3: "))
+		Expect(output).ToNot(ContainSubstring("a.yaml"))
+	})
+
+	It("should not check disabled rules", func() {
+		path := write("linted.yaml", lintedDeclaration)
+		output, err := testutils.ExecuteCommand(lintproducts.Command(), path, "--disable", "https-url,placeholder-text")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(BeEmpty())
+	})
+
+	It("should refuse to disable unknown rules", func() {
+		path := write("linted.yaml", lintedDeclaration)
+		_, err := testutils.ExecuteCommand(lintproducts.Command(), path, "--disable", "no-such-rule")
+		Expect(err).To(MatchError(lint.ErrUnknownRule))
+	})
+
+	It("should write GitHub Actions annotations", func() {
+		path := write("linted.yaml", lintedDeclaration)
+		output, err := testutils.ExecuteCommand(lintproducts.Command(), path, "-o", "github")
+		Expect(err).To(MatchError(lintproducts.ErrLintFailed))
+		Expect(output).To(ContainSubstring("::warning file=" + path + ",line=5,title=https-url::spec.support.url: "))
+	})
+
+	It("should write a SARIF log", func() {
+		path := write("linted.yaml", lintedDeclaration)
+		cmd := lintproducts.Command()
+		// Print only the log, so that it can be parsed.
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		output, err := testutils.ExecuteCommand(cmd, path, "-o", "sarif")
+		Expect(err).To(MatchError(lintproducts.ErrLintFailed))
+
+		var log struct {
+			Runs []struct {
+				Results []struct {
+					RuleID string `json:"ruleId"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		Expect(json.Unmarshal([]byte(output), &log)).To(Succeed())
+		Expect(log.Runs).To(HaveLen(1))
+		Expect(log.Runs[0].Results).To(HaveLen(2))
+	})
+
+	It("should refuse unknown output formats", func() {
+		path := write("clean.yaml", cleanDeclaration)
+		_, err := testutils.ExecuteCommand(lintproducts.Command(), path, "-o", "xml")
+		Expect(err).To(MatchError(lint.ErrUnknownFormat))
+	})
+})
@@ -0,0 +1,104 @@
+// Package listapikeys implements the auth keys list subcommand.
+package listapikeys
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the API keys associated with your organization",
+		Long: `List the API keys associated with your organization.
+
+The API key values themselves are never returned. Use the key IDs listed here with the delete and rotate subcommands.`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
+	return cmd
+}
+
+// columns renders API keys as a table.
+var columns = &printer.Columns[[]*genpyxis.APIKeySupportedFields]{
+	Header: []string{"ID", "DESCRIPTION", "CREATED", "LAST USED", "CREATED BY"},
+	Rows: func(keys []*genpyxis.APIKeySupportedFields) [][]string {
+		rows := make([][]string, 0, len(keys))
+		for _, k := range keys {
+			rows = append(rows, []string{strconv.Itoa(k.GetId()), k.GetDescription(), formatTime(k.GetCreated()), formatTime(k.GetLast_used()), k.GetCreated_by()})
+		}
+
+		return rows
+	},
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	return run(cmd.Context(), cmd.OutOrStdout(), p, cfg.OrgID, token, endpoint)
+}
+
+func run(ctx context.Context, out io.Writer, p *printer.Printer[[]*genpyxis.APIKeySupportedFields], orgID int, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	keys, err := catalogapi.ListAPIKeys(ctx, client, orgID)
+	if err != nil {
+		return err
+	}
+
+	return p.Print(out, keys)
+}
@@ -0,0 +1,13 @@
+package listapikeys_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestListAPIKeys(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ListAPIKeys Suite")
+}
@@ -0,0 +1,162 @@
+// Package listcomponents implements the component list subcommand.
+package listcomponents
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the components in your organization",
+		Long: `List the components (certification projects) in your organization that have not been archived, most recently updated first.
+
+Use the listed IDs with other subcommands, e.g. "util archive-component", or add them to a product listing declaration.`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatTable, printer.Usage(true))
+	cmd.Flags().String(cli.FlagIDNameFilter, "", "Only list components with names containing this value. Case insensitive")
+	cmd.Flags().String(cli.FlagIDTypeFilter, "", "Only list components of this type. E.g. Containers, \"Helm Chart\", OpenShift-cnf, RHEL, OpenStack-infra-container")
+	cmd.Flags().String(cli.FlagIDStatusFilter, "", "Only list components with this certification status. E.g. Started, Published")
+
+	return cmd
+}
+
+// summary is the listed representation of a component.
+type summary struct {
+	ID                  string   `json:"_id"`
+	Name                string   `json:"name"`
+	Type                string   `json:"type"`
+	ProjectStatus       string   `json:"project_status"`
+	CertificationStatus string   `json:"certification_status"`
+	ProductListings     []string `json:"product_listings"`
+}
+
+// filters narrow the components that are listed. Empty values match
+// everything.
+type filters struct {
+	name   string
+	typ    string
+	status string
+}
+
+func (f filters) match(c *genpyxis.ComponentSupportedFields) bool {
+	if !strings.Contains(strings.ToLower(c.GetName()), strings.ToLower(f.name)) {
+		return false
+	}
+
+	if f.typ != "" && !strings.EqualFold(c.GetType(), f.typ) {
+		return false
+	}
+
+	if f.status != "" && !strings.EqualFold(c.GetCertification_status(), f.status) {
+		return false
+	}
+
+	return true
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	var f filters
+	f.name, _ = cmd.Flags().GetString(cli.FlagIDNameFilter)
+	f.typ, _ = cmd.Flags().GetString(cli.FlagIDTypeFilter)
+	f.status, _ = cmd.Flags().GetString(cli.FlagIDStatusFilter)
+
+	return run(cmd.Context(), cmd.OutOrStdout(), p, f, cfg.OrgID, token, endpoint)
+}
+
+func run(ctx context.Context, out io.Writer, p *printer.Printer[[]summary], f filters, orgID int, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	components, err := catalogapi.ListComponents(ctx, client, orgID)
+	if err != nil {
+		return err
+	}
+
+	summaries := summarize(components, f)
+	L.Debug("components found", "total", len(components), "matched", len(summaries))
+
+	return p.Print(out, summaries)
+}
+
+// summarize converts components to their listed representation, excluding
+// those that do not match f.
+func summarize(components []*genpyxis.ComponentSupportedFields, f filters) []summary {
+	summaries := make([]summary, 0, len(components))
+	for _, c := range components {
+		if !f.match(c) {
+			continue
+		}
+
+		summaries = append(summaries, summary{
+			ID:                  c.GetId(),
+			Name:                c.GetName(),
+			Type:                c.GetType(),
+			ProjectStatus:       c.GetProject_status(),
+			CertificationStatus: c.GetCertification_status(),
+			ProductListings:     c.GetProduct_listings(),
+		})
+	}
+
+	return summaries
+}
+
+// columns renders summaries as a table.
+var columns = &printer.Columns[[]summary]{
+	Header: []string{"ID", "NAME", "TYPE", "PROJECT STATUS", "CERTIFICATION STATUS", "PRODUCT LISTINGS"},
+	Rows: func(summaries []summary) [][]string {
+		rows := make([][]string, 0, len(summaries))
+		for _, s := range summaries {
+			rows = append(rows, []string{s.ID, s.Name, s.Type, s.ProjectStatus, s.CertificationStatus, strings.Join(s.ProductListings, ",")})
+		}
+
+		return rows
+	},
+}
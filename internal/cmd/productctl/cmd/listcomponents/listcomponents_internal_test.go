@@ -0,0 +1,71 @@
+package listcomponents
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+var _ = Describe("ListComponents (internal)", func() {
+	var components []*genpyxis.ComponentSupportedFields
+
+	BeforeEach(func() {
+		components = []*genpyxis.ComponentSupportedFields{
+			{Id: "1", Name: "api-server", Type: "Containers", Project_status: "active", Certification_status: "Published", Product_listings: []string{"p1", "p2"}},
+			{Id: "2", Name: "api-chart", Type: "Helm Chart", Project_status: "active", Certification_status: "Started"},
+			{Id: "3", Name: "worker", Type: "Containers", Project_status: "active", Certification_status: "Started"},
+		}
+	})
+
+	DescribeTable("when filtering components",
+		func(f filters, expectedIDs ...string) {
+			summaries := summarize(components, f)
+			ids := []string{}
+			for _, s := range summaries {
+				ids = append(ids, s.ID)
+			}
+			Expect(ids).To(Equal(expectedIDs))
+		},
+		Entry("without filters", filters{}, "1", "2", "3"),
+		Entry("by name", filters{name: "API"}, "1", "2"),
+		Entry("by type", filters{typ: "containers"}, "1", "3"),
+		Entry("by status", filters{status: "started"}, "2", "3"),
+		Entry("by multiple filters", filters{name: "api", status: "Started"}, "2"),
+	)
+
+	When("writing summaries", func() {
+		var (
+			out   *bytes.Buffer
+			write func(output string, summaries []summary) error
+		)
+
+		BeforeEach(func() {
+			out = &bytes.Buffer{}
+			write = func(output string, summaries []summary) error {
+				p, err := printer.New(output, columns)
+				if err != nil {
+					return err
+				}
+				return p.Print(out, summaries)
+			}
+		})
+
+		It("should write a table including attached product listings", func() {
+			Expect(write(printer.FormatTable, summarize(components, filters{}))).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("CERTIFICATION STATUS"))
+			Expect(out.String()).To(ContainSubstring("p1,p2"))
+		})
+
+		It("should write valid JSON", func() {
+			Expect(write(printer.FormatJSON, summarize(components, filters{}))).To(Succeed())
+			var decoded []map[string]any
+			Expect(json.Unmarshal(out.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded).To(HaveLen(3))
+		})
+	})
+})
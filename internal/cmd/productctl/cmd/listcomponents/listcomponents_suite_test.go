@@ -0,0 +1,13 @@
+package listcomponents_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestListComponents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ListComponents Suite")
+}
@@ -0,0 +1,141 @@
+// Package listproducts implements the product list subcommand.
+package listproducts
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the product listings in your organization",
+		Long: `List the product listings in your organization that have not been deleted, most recently updated first.
+
+Use the listed IDs with other subcommands, e.g. "fetch".`,
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatTable, printer.Usage(true))
+	cmd.Flags().String(cli.FlagIDNameFilter, "", "Only list product listings with names containing this value. Case insensitive")
+
+	return cmd
+}
+
+// summary is the listed representation of a product listing.
+type summary struct {
+	ID             string     `json:"_id"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Published      bool       `json:"published"`
+	ComponentCount int        `json:"component_count"`
+	LastUpdateDate *time.Time `json:"last_update_date,omitempty"`
+}
+
+func runE(cmd *cobra.Command, _ []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	nameFilter, _ := cmd.Flags().GetString(cli.FlagIDNameFilter)
+
+	return run(cmd.Context(), cmd.OutOrStdout(), p, nameFilter, cfg.OrgID, token, endpoint)
+}
+
+func run(ctx context.Context, out io.Writer, p *printer.Printer[[]summary], nameFilter string, orgID int, token string, endpoint catalogapi.APIEndpoint) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	L.Debug("building graphql client")
+	httpClient := catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient"))
+	client := graphql.NewClient(endpoint, httpClient)
+
+	listings, err := catalogapi.ListProducts(ctx, client, orgID)
+	if err != nil {
+		return err
+	}
+
+	summaries := summarize(listings, nameFilter)
+	L.Debug("product listings found", "total", len(listings), "matched", len(summaries))
+
+	return p.Print(out, summaries)
+}
+
+// summarize converts listings to their listed representation, excluding those
+// whose names do not contain nameFilter.
+func summarize(listings []*genpyxis.ProductListingSupportedFields, nameFilter string) []summary {
+	nameFilter = strings.ToLower(nameFilter)
+
+	summaries := make([]summary, 0, len(listings))
+	for _, l := range listings {
+		if !strings.Contains(strings.ToLower(l.GetName()), nameFilter) {
+			continue
+		}
+
+		summaries = append(summaries, summary{
+			ID:             l.GetId(),
+			Name:           l.GetName(),
+			Type:           l.GetType(),
+			Published:      l.GetPublished(),
+			ComponentCount: len(l.GetCert_projects()),
+			LastUpdateDate: l.GetLast_update_date(),
+		})
+	}
+
+	return summaries
+}
+
+// columns renders summaries as a table.
+var columns = &printer.Columns[[]summary]{
+	Header: []string{"ID", "NAME", "TYPE", "PUBLISHED", "COMPONENTS", "LAST UPDATED"},
+	Rows: func(summaries []summary) [][]string {
+		rows := make([][]string, 0, len(summaries))
+		for _, s := range summaries {
+			lastUpdated := ""
+			if s.LastUpdateDate != nil {
+				lastUpdated = s.LastUpdateDate.Format(time.RFC3339)
+			}
+			rows = append(rows, []string{s.ID, s.Name, s.Type, strconv.FormatBool(s.Published), strconv.Itoa(s.ComponentCount), lastUpdated})
+		}
+
+		return rows
+	},
+}
@@ -0,0 +1,75 @@
+package listproducts
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/genpyxis"
+	"github.com/opdev/productctl/internal/printer"
+)
+
+var _ = Describe("ListProducts (internal)", func() {
+	var listings []*genpyxis.ProductListingSupportedFields
+
+	BeforeEach(func() {
+		listings = []*genpyxis.ProductListingSupportedFields{
+			{Id: "1", Name: "My Operator", Type: "container stack", Published: true, Cert_projects: []string{"a", "b"}},
+			{Id: "2", Name: "Another Product", Type: "traditional application"},
+		}
+	})
+
+	When("summarizing product listings", func() {
+		It("should include every listing when no filter is provided", func() {
+			summaries := summarize(listings, "")
+			Expect(summaries).To(HaveLen(2))
+			Expect(summaries[0].ComponentCount).To(Equal(2))
+			Expect(summaries[0].Published).To(BeTrue())
+		})
+
+		It("should filter listings by name without regard to case", func() {
+			summaries := summarize(listings, "operator")
+			Expect(summaries).To(HaveLen(1))
+			Expect(summaries[0].ID).To(Equal("1"))
+		})
+	})
+
+	When("writing summaries", func() {
+		var (
+			out   *bytes.Buffer
+			write func(output string, summaries []summary) error
+		)
+
+		BeforeEach(func() {
+			out = &bytes.Buffer{}
+			write = func(output string, summaries []summary) error {
+				p, err := printer.New(output, columns)
+				if err != nil {
+					return err
+				}
+				return p.Print(out, summaries)
+			}
+		})
+
+		It("should write a table with a header", func() {
+			Expect(write(printer.FormatTable, summarize(listings, ""))).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("PUBLISHED"))
+			Expect(out.String()).To(ContainSubstring("My Operator"))
+		})
+
+		It("should write valid JSON", func() {
+			Expect(write(printer.FormatJSON, summarize(listings, ""))).To(Succeed())
+			var decoded []map[string]any
+			Expect(json.Unmarshal(out.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded).To(HaveLen(2))
+			Expect(decoded[0]).To(HaveKeyWithValue("component_count", BeEquivalentTo(2)))
+		})
+
+		It("should write YAML", func() {
+			Expect(write(printer.FormatYAML, summarize(listings, ""))).To(Succeed())
+			Expect(out.String()).To(ContainSubstring("name: Another Product"))
+		})
+	})
+})
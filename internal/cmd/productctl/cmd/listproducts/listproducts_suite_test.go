@@ -0,0 +1,13 @@
+package listproducts_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestListProducts(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ListProducts Suite")
+}
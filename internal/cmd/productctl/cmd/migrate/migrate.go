@@ -0,0 +1,137 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrMigrationIncomplete = errors.New("some declarations could not be migrated")
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <your-declaration.yaml|directory|-> [...]",
+		Short: "Upgrades declarations to the current apiVersion",
+		Long: `Upgrades declarations of any kind of a previous apiVersion, including those without an apiVersion, to the current apiVersion. Files are updated in place, retaining comments. Declarations that are already of the current apiVersion are left untouched. Does not contact the backend.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Use "-" to read declarations from stdin, in which case the upgraded declarations are written to stdout.`,
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: runE,
+	}
+
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the declaration on overwrite.")
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	if len(args) == 1 && args[0] == "-" {
+		result, o, err := migrateStream(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("stdin: %w", err)
+		}
+
+		if _, err := cmd.OutOrStdout().Write(result.Content); err != nil {
+			return err
+		}
+		o.print(cmd.ErrOrStderr())
+		return nil
+	}
+
+	backupOnOverwrite, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
+	recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
+
+	total := outcome{}
+	var errs []error
+	for _, arg := range args {
+		files, err := resource.DeclarationFiles(arg, recursive, resource.KindProductListing, resource.KindComponent)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, filename := range files {
+			o, err := migrateFile(filename, &file.LazyOverwriter{
+				Filename:       filename,
+				DoBackup:       backupOnOverwrite,
+				OptionalLogger: L.With("name", "fileIO"),
+			})
+			total.add(o)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", filename, err))
+			}
+		}
+	}
+
+	total.print(cmd.ErrOrStderr())
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrMigrationIncomplete}, errs...)...)
+	}
+
+	return nil
+}
+
+// migrateFile upgrades the declarations in filename, writing them to out if
+// any were upgraded.
+func migrateFile(filename string, out io.Writer) (outcome, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return outcome{unreadable: 1}, err
+	}
+
+	result, o, err := migrateStream(f)
+	f.Close()
+	if err != nil || o.migrated == 0 {
+		return o, err
+	}
+
+	_, err = out.Write(result.Content)
+	return o, err
+}
+
+func migrateStream(in io.Reader) (*resource.MigrationResult, outcome, error) {
+	result, err := resource.MigrateDeclarations(in)
+	if err != nil {
+		return nil, outcome{unreadable: 1}, err
+	}
+
+	return result, outcome{migrated: result.Migrated, current: result.Current}, nil
+}
+
+// outcome counts the declarations that were migrated, and those that did not
+// need to be.
+type outcome struct {
+	migrated   int
+	current    int
+	unreadable int
+}
+
+func (o *outcome) add(other outcome) {
+	o.migrated += other.migrated
+	o.current += other.current
+	o.unreadable += other.unreadable
+}
+
+// print writes a summary of o to w.
+func (o outcome) print(w io.Writer) {
+	fmt.Fprintf(w, "%d declaration(s) migrated to %s, %d already current", o.migrated, resource.CurrentAPIVersion, o.current)
+	if o.unreadable > 0 {
+		fmt.Fprintf(w, ", %d file(s) could not be read", o.unreadable)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,110 @@
+package migrate_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/migrate"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+const (
+	unversioned = `# notes about this product
+kind: ProductListing
+spec:
+  name: old-product
+`
+	current = "apiVersion: " + resource.CurrentAPIVersion + `
+kind: ProductListing
+spec:
+  name:   current-product
+`
+)
+
+var _ = Describe("Migrate", func() {
+	var tempDirPath string
+
+	BeforeEach(func() {
+		var err error
+		tempDirPath, err = os.MkdirTemp("", "productctl-unit-test-*")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDirPath)).To(Succeed())
+	})
+
+	write := func(name, contents string) string {
+		path := filepath.Join(tempDirPath, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	read := func(path string) string {
+		b, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		return string(b)
+	}
+
+	When("the file is not found", func() {
+		It("should throw an error", func() {
+			_, err := testutils.ExecuteCommand(migrate.Command(), "foofile")
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+	})
+
+	It("should upgrade declarations of previous versions in place", func() {
+		path := write("mixed.yaml", unversioned+"---\n"+current)
+		output, err := testutils.ExecuteCommand(migrate.Command(), path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("1 declaration(s) migrated to " + resource.CurrentAPIVersion + ", 1 already current"))
+		Expect(read(path)).To(Equal("# notes about this product\napiVersion: " + resource.CurrentAPIVersion + "\nkind: ProductListing\nspec:\n  name: old-product\n---\n" + current))
+	})
+
+	It("should leave files that are already current untouched", func() {
+		path := write("current.yaml", current)
+		before, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = testutils.ExecuteCommand(migrate.Command(), "--backup-declaration-on-overwrite", tempDirPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		after, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(after.ModTime()).To(Equal(before.ModTime()))
+		entries, err := os.ReadDir(tempDirPath)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should report declarations of unknown versions", func() {
+		path := write("unknown.yaml", "apiVersion: productctl.opdev.io/v99\nkind: ProductListing\n")
+		output, err := testutils.ExecuteCommand(migrate.Command(), path)
+		Expect(err).To(MatchError(migrate.ErrMigrationIncomplete))
+		Expect(err).To(MatchError(resource.ErrUnknownAPIVersion))
+		Expect(output).To(ContainSubstring("1 file(s) could not be read"))
+	})
+
+	It("should write declarations read from stdin to stdout", func() {
+		command := migrate.Command()
+		command.SetIn(strings.NewReader(unversioned))
+		output, err := testutils.ExecuteCommand(command, "-")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("apiVersion: " + resource.CurrentAPIVersion + "\nkind: ProductListing\n"))
+	})
+
+	It("should not require an API token", func() {
+		os.Unsetenv("PRODUCTCTL_API_TOKEN")
+		path := write("old.yaml", unversioned)
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "migrate", path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read(path)).To(ContainSubstring("apiVersion: " + resource.CurrentAPIVersion))
+	})
+})
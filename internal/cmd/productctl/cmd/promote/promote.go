@@ -0,0 +1,476 @@
+// Package promote implements the product promote subcommand.
+package promote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrNotOneDeclaration      = errors.New("the file must contain exactly one product listing declaration")
+	ErrSameEnv                = errors.New("the declaration must be promoted to another environment")
+	ErrNotApplied             = errors.New("the declaration has not been applied to the environment it is promoted from")
+	ErrSourceDiffers          = errors.New("the environment the declaration is promoted from differs from the declaration, apply it there first")
+	ErrComponentNames         = errors.New("components must have distinct names to be promoted")
+	ErrComponentRefsPromotion = errors.New("declarations with component_refs cannot be promoted")
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <your-declaration.yaml> --from <env> --to <env>",
+		Short: "Apply a declaration already applied to one environment to another",
+		Long: `Apply a product listing declaration, which has already been applied to one catalog API environment, to another, e.g. from stage to prod.
+
+The IDs of the product listing and its components in each environment are held by the overlay of the declaration named after that environment, e.g. my.product.stage.overlay.yaml for --from stage, as written by apply. If the environment promoted from has no overlay, its IDs are read from the declaration, as apply writes them without overlays. The overlay of each environment is merged into the declaration before it is compared with or applied to that environment, and the IDs assigned in the environment promoted to are recorded in its overlay, which is created if it does not exist. The declaration itself is left as it is. Components are identified by their names, which must be distinct.
+
+Nothing is applied unless the product listing and its components in the environment promoted from hold every value set in the declaration, so that only declarations that have been applied there are promoted. In the environment promoted to, the listing and its components are created if they have no IDs there, updated if they differ from the declaration, and skipped otherwise.
+
+Environments usually require API keys of their own. Use --from-api-token-file and --to-api-token-file to provide a file holding the key for each environment, otherwise your configured API key is used for both.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runE,
+	}
+
+	cmd.Flags().String(cli.FlagIDFrom, "", "The catalog API environment the declaration has been applied to")
+	cmd.Flags().String(cli.FlagIDTo, "", "The catalog API environment the declaration is promoted to")
+	cmd.Flags().String(cli.FlagIDFromAPITokenFile, "", "A file holding the API key used with the environment the declaration is promoted from")
+	cmd.Flags().String(cli.FlagIDToAPITokenFile, "", "A file holding the API key used with the environment the declaration is promoted to")
+	cmd.Flags().Bool(cli.FlagIDCreateBackupOnOverwrite, false, "Create a backup of the overlay of the environment promoted to before overwriting it")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatTable, printer.Usage(true))
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
+	_ = cmd.MarkFlagRequired(cli.FlagIDFrom)
+	_ = cmd.MarkFlagRequired(cli.FlagIDTo)
+
+	return cmd
+}
+
+// Actions taken in the environment promoted to.
+const (
+	actionCreated   = "created"
+	actionUpdated   = "updated"
+	actionUnchanged = "unchanged"
+)
+
+// result is the action taken for an object of the declaration in the
+// environment promoted to.
+type result struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	ID     string `json:"_id"`
+	Action string `json:"action"`
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, columns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	from, _ := cmd.Flags().GetString(cli.FlagIDFrom)
+	to, _ := cmd.Flags().GetString(cli.FlagIDTo)
+	if from == to {
+		return fmt.Errorf("%w: both are %s", ErrSameEnv, from)
+	}
+
+	variables, err := cli.Variables(cmd)
+	if err != nil {
+		return err
+	}
+
+	filename := args[0]
+	readOpts := []resource.ReadOption{resource.WithStrict(cfg.Strict), resource.WithVariables(variables)}
+	declaration, err := readDeclaration(filename, append(slices.Clip(readOpts), resource.WithBaseDir(filepath.Dir(filename)))...)
+	if err != nil {
+		return err
+	}
+
+	fromOverlay, exists, err := resource.ReadOverlayFile(file.OverlayFilename(filename, from), readOpts...)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		fromOverlay = nil
+	}
+
+	toOverlayFilename := file.OverlayFilename(filename, to)
+	toOverlay, _, err := resource.ReadOverlayFile(toOverlayFilename, readOpts...)
+	if err != nil {
+		return err
+	}
+
+	source, err := cli.EnvironmentClient(cmd.Context(), cfg, from, "", cmd.Flag(cli.FlagIDFromAPITokenFile).Value.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", from, err)
+	}
+
+	target, err := cli.EnvironmentClient(cmd.Context(), cfg, to, "", cmd.Flag(cli.FlagIDToAPITokenFile).Value.String())
+	if err != nil {
+		return fmt.Errorf("%s: %w", to, err)
+	}
+
+	L.Debug("promoting declaration", "source", filename, "from", from, "to", to)
+	results, promoteErr := promote(cmd.Context(), source, target, from, to, declaration, fromOverlay, toOverlay)
+
+	// IDs are recorded even if promotion failed part way, so that the objects
+	// that were created are updated rather than created again.
+	if len(results) > 0 {
+		backup, _ := cmd.Flags().GetBool(cli.FlagIDCreateBackupOnOverwrite)
+		overwriter := file.LazyOverwriter{
+			Filename:        toOverlayFilename,
+			DoBackup:        backup,
+			CreateIfMissing: true,
+			OptionalLogger:  L.With("name", "fileIO"),
+		}
+		if _, err := overwriter.Write(toOverlay.Bytes()); err != nil {
+			return errors.Join(promoteErr, err)
+		}
+	}
+
+	if promoteErr != nil {
+		return promoteErr
+	}
+
+	return p.Print(cmd.OutOrStdout(), results)
+}
+
+// readDeclaration reads the only declaration in filename.
+func readDeclaration(filename string, readOpts ...resource.ReadOption) (*resource.ProductListingDeclaration, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stream, err := resource.ReadDeclarationStream(f, readOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	declarations := stream.Declarations()
+	if len(declarations) != 1 {
+		return nil, fmt.Errorf("%s: %w, found %d", filename, ErrNotOneDeclaration, len(declarations))
+	}
+
+	return declarations[0], nil
+}
+
+// environmentIDs are the IDs of a product listing and its components in one
+// environment.
+type environmentIDs struct {
+	listing string
+	// components are the IDs of the listing's components, by name.
+	components map[string]string
+}
+
+// idsOf returns the IDs held by declaration.
+func idsOf(declaration *resource.ProductListingDeclaration) environmentIDs {
+	ids := environmentIDs{listing: declaration.Spec.ID, components: map[string]string{}}
+	for _, c := range declaration.With.Components {
+		if c.ID != "" {
+			ids.components[c.Name] = c.ID
+		}
+	}
+
+	return ids
+}
+
+// promote applies declaration to target, the environment named to, once it
+// has confirmed that source, the environment named from, holds what is
+// declared. The overlay of each environment is merged into declaration for
+// that environment, and holds its IDs. fromOverlay is nil if the environment
+// promoted from has none, in which case its IDs are those of declaration. The
+// IDs assigned in target are recorded in toOverlay. The results of the
+// objects applied to target are returned, including when a later object
+// fails.
+func promote(
+	ctx context.Context,
+	source, target graphql.Client,
+	from, to string,
+	declaration *resource.ProductListingDeclaration,
+	fromOverlay, toOverlay *resource.Overlay,
+) ([]result, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	if len(declaration.With.ComponentRefs) > 0 {
+		return nil, ErrComponentRefsPromotion
+	}
+
+	fromDeclaration := declaration
+	if fromOverlay != nil {
+		var err error
+		if fromDeclaration, err = fromOverlay.Apply(0, declaration); err != nil {
+			return nil, fmt.Errorf("%s: %w", from, err)
+		}
+	}
+
+	toDeclaration, err := toOverlay.Apply(0, declaration)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", to, err)
+	}
+
+	for _, d := range []*resource.ProductListingDeclaration{fromDeclaration, toDeclaration} {
+		if err := checkComponentNames(d); err != nil {
+			return nil, err
+		}
+	}
+
+	fromIDs, toIDs := idsOf(fromDeclaration), idsOf(toDeclaration)
+	if fromIDs.listing == "" {
+		return nil, fmt.Errorf("%w: no ID is recorded for %s", ErrNotApplied, from)
+	}
+
+	// The declaration is compared and applied without the values that tie it
+	// to an environment.
+	declared, err := resource.JSONConvert[resource.ProductListingDeclaration](fromDeclaration)
+	if err != nil {
+		return nil, err
+	}
+	declared.Sanitize()
+
+	L.Debug("comparing declaration with the environment promoted from", "env", from, "_id", fromIDs.listing)
+	existingSource, err := catalogapi.PopulateProduct(ctx, source, fromIDs.listing)
+	if err != nil {
+		return nil, fmt.Errorf("%s: fetching product listing %s: %w", from, fromIDs.listing, err)
+	}
+
+	differences, err := compare(&declared, existingSource, recorded(existingSource, fromIDs))
+	if err != nil {
+		return nil, err
+	}
+	if len(differences) > 0 {
+		return nil, fmt.Errorf("%w: %s differs at %s", ErrSourceDiffers, from, strings.Join(differences, ", "))
+	}
+
+	if declared, err = resource.JSONConvert[resource.ProductListingDeclaration](toDeclaration); err != nil {
+		return nil, err
+	}
+	declared.Sanitize()
+
+	// The IDs known in target are recorded however promotion ends.
+	defer func() {
+		toOverlay.SetServerIDs(0, withIDs(&declared, toIDs))
+	}()
+
+	var existing *resource.ProductListingDeclaration
+	var existingComponents map[string]*resource.Component
+	var attached []string
+	if toIDs.listing != "" {
+		if existing, err = catalogapi.PopulateProduct(ctx, target, toIDs.listing); err != nil {
+			return nil, fmt.Errorf("%s: fetching product listing %s: %w", to, toIDs.listing, err)
+		}
+		existingComponents = recorded(existing, toIDs)
+		attached = existing.Spec.CertProjects
+		existing.Sanitize()
+	}
+
+	var results []result
+	componentIDs := make([]string, 0, len(declared.With.Components))
+	for _, c := range declared.With.Components {
+		r := result{Kind: resource.KindComponent, Name: c.Name, ID: toIDs.components[c.Name], Action: actionUnchanged}
+
+		// Both are sanitized, so they are compared before the ID is set.
+		switch current := existingComponents[c.Name]; {
+		case r.ID == "":
+			r.Action = actionCreated
+		case current == nil:
+			r.Action = actionUpdated
+		default:
+			differences, err := resource.Differences(c, current)
+			if err != nil {
+				return results, err
+			}
+			if len(differences) > 0 {
+				r.Action = actionUpdated
+			}
+		}
+
+		if r.Action != actionUnchanged {
+			L.Debug("applying component", "env", to, "name", c.Name, "action", r.Action)
+			component := *c
+			component.ID = r.ID
+			applied, err := catalogapi.ApplyComponent(ctx, target, &resource.ComponentDeclaration{APIVersion: resource.CurrentAPIVersion, Kind: resource.KindComponent, Spec: component})
+			if err != nil {
+				return results, fmt.Errorf("%s: applying component %q: %w", to, c.Name, err)
+			}
+			r.ID = applied.Spec.ID
+			toIDs.components[c.Name] = r.ID
+		}
+
+		results = append(results, r)
+		componentIDs = append(componentIDs, r.ID)
+	}
+
+	// The components were applied above, and are attached to the listing by
+	// reference, so that applying it does not apply them again.
+	listing := resource.NewProductListing()
+	listing.Spec = declared.Spec
+	for _, id := range componentIDs {
+		listing.With.ComponentRefs = append(listing.With.ComponentRefs, &resource.ComponentReference{ID: id})
+	}
+
+	r := result{Kind: resource.KindProductListing, Name: listing.Spec.Name, ID: toIDs.listing, Action: actionUnchanged}
+	switch {
+	case existing == nil && r.ID == "":
+		r.Action = actionCreated
+	case existing == nil:
+		r.Action = actionUpdated
+	default:
+		differences, err := resource.Differences(declared.Spec, existing.Spec)
+		if err != nil {
+			return results, err
+		}
+		if len(differences) > 0 || !sameIDs(attached, componentIDs) {
+			r.Action = actionUpdated
+		}
+	}
+
+	listing.Spec.ID = r.ID
+	if r.Action != actionUnchanged {
+		L.Debug("applying product listing", "env", to, "name", listing.Spec.Name, "action", r.Action)
+		applied, err := catalogapi.ApplyProduct(ctx, target, &listing)
+		if err != nil {
+			return results, fmt.Errorf("%s: applying product listing %q: %w", to, listing.Spec.Name, err)
+		}
+		r.ID = applied.Spec.ID
+		toIDs.listing = r.ID
+	}
+
+	return append([]result{r}, results...), nil
+}
+
+// checkComponentNames returns an error if the components of declaration do
+// not have distinct names.
+func checkComponentNames(declaration *resource.ProductListingDeclaration) error {
+	names := map[string]bool{}
+	for i, c := range declaration.With.Components {
+		if c.Name == "" || names[c.Name] {
+			return fmt.Errorf("%w: with.components[%d] is named %q", ErrComponentNames, i, c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	return nil
+}
+
+// compare returns the paths at which current, a product listing as it is in
+// an environment, does not hold the values of declared. components are the
+// components of current, by the names of the declared components they are
+// recorded for.
+func compare(declared, current *resource.ProductListingDeclaration, components map[string]*resource.Component) ([]string, error) {
+	current.Sanitize()
+
+	differences, err := resource.Differences(declared.Spec, current.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(differences))
+	for _, d := range differences {
+		paths = append(paths, "spec."+d)
+	}
+
+	for _, c := range declared.With.Components {
+		path := fmt.Sprintf("with.components[name=%s]", c.Name)
+		found, ok := components[c.Name]
+		if !ok {
+			paths = append(paths, path)
+			continue
+		}
+
+		differences, err := resource.Differences(c, found)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range differences {
+			paths = append(paths, path+"."+d)
+		}
+	}
+
+	return paths, nil
+}
+
+// recorded returns the components of listing whose IDs are recorded in ids,
+// by the names they are recorded for.
+func recorded(listing *resource.ProductListingDeclaration, ids environmentIDs) map[string]*resource.Component {
+	byID := map[string]*resource.Component{}
+	for _, c := range listing.With.Components {
+		byID[c.ID] = c
+	}
+
+	components := map[string]*resource.Component{}
+	for name, id := range ids.components {
+		if c, ok := byID[id]; ok {
+			components[name] = c
+		}
+	}
+
+	return components
+}
+
+// withIDs returns declaration holding ids, as it was applied.
+func withIDs(declaration *resource.ProductListingDeclaration, ids environmentIDs) *resource.ProductListingDeclaration {
+	applied := resource.NewProductListing()
+	applied.Spec.ID = ids.listing
+	for _, c := range declaration.With.Components {
+		applied.With.Components = append(applied.With.Components, &resource.Component{Name: c.Name, ID: ids.components[c.Name]})
+	}
+
+	return &applied
+}
+
+// sameIDs returns true if a and b hold the same IDs, in any order.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	seen := map[string]int{}
+	for _, id := range a {
+		seen[id]++
+	}
+	for _, id := range b {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+
+	return true
+}
+
+// columns renders results as a table.
+var columns = &printer.Columns[[]result]{
+	Header: []string{"KIND", "NAME", "ID", "ACTION"},
+	Rows: func(results []result) [][]string {
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			rows = append(rows, []string{r.Kind, r.Name, r.ID, r.Action})
+		}
+
+		return rows
+	},
+}
@@ -0,0 +1,190 @@
+package promote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// fakeEnvironment is a backend holding product listings and components.
+type fakeEnvironment struct {
+	*testutils.FakeClient
+	listings   map[string]map[string]any
+	components map[string]map[string]any
+}
+
+func newFakeEnvironment(name string) *fakeEnvironment {
+	e := &fakeEnvironment{
+		listings:   map[string]map[string]any{},
+		components: map[string]map[string]any{},
+	}
+	e.FakeClient = &testutils.FakeClient{Responses: map[string]testutils.Response{
+		"ProductByID": func(variables map[string]any) (any, error) {
+			listing, ok := e.listings[variables["productID"].(string)]
+			if !ok {
+				return nil, errors.New("no such product listing")
+			}
+			return map[string]any{"get_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"ComponentsForListing": func(variables map[string]any) (any, error) {
+			listing := e.listings[variables["productID"].(string)]
+			var components []map[string]any
+			ids, _ := listing["cert_projects"].([]any)
+			for _, id := range ids {
+				components = append(components, e.components[id.(string)])
+			}
+			return map[string]any{"find_product_listing_certification_projects": map[string]any{"data": components, "total": len(components)}}, nil
+		},
+		"NewComponent": func(variables map[string]any) (any, error) {
+			component := maps.Clone(variables["new"].(map[string]any))
+			component["_id"] = fmt.Sprintf("%s-component-%d", name, len(e.components)+1)
+			e.components[component["_id"].(string)] = component
+			return map[string]any{"create_certification_project": map[string]any{"data": component}}, nil
+		},
+		"ApplyComponent": func(variables map[string]any) (any, error) {
+			component := e.components[variables["componentID"].(string)]
+			maps.Copy(component, variables["updated"].(map[string]any))
+			return map[string]any{"update_certification_project": map[string]any{"data": component}}, nil
+		},
+		"NewProductListing": func(variables map[string]any) (any, error) {
+			listing := maps.Clone(variables["new"].(map[string]any))
+			listing["_id"] = fmt.Sprintf("%s-listing-%d", name, len(e.listings)+1)
+			e.listings[listing["_id"].(string)] = listing
+			return map[string]any{"create_product_listing": map[string]any{"data": listing}}, nil
+		},
+		"ApplyProductListing": func(variables map[string]any) (any, error) {
+			listing := e.listings[variables["id"].(string)]
+			maps.Copy(listing, variables["update"].(map[string]any))
+			return map[string]any{"update_product_listing": map[string]any{"data": listing}}, nil
+		},
+	}}
+
+	return e
+}
+
+var _ = Describe("Promote (internal)", func() {
+	var stage, prod *fakeEnvironment
+	var declaration *resource.ProductListingDeclaration
+
+	BeforeEach(func() {
+		stage = newFakeEnvironment("stage")
+		prod = newFakeEnvironment("prod")
+
+		stage.components["stage-api"] = map[string]any{"_id": "stage-api", "org_id": 1, "name": "api", "type": "Containers", "badges": []any{"certified"}}
+		stage.listings["stage-listing"] = map[string]any{"_id": "stage-listing", "org_id": 1, "name": "My Product", "type": "container stack", "cert_projects": []any{"stage-api"}}
+
+		// As written by apply to stage.
+		d := resource.NewProductListing()
+		d.Spec = resource.ProductListing{ID: "stage-listing", OrgID: 1, Name: "My Product", Type: "container stack", CertProjects: []string{"stage-api"}}
+		d.With.Components = []*resource.Component{{ID: "stage-api", OrgID: 1, Name: "api", Type: resource.ComponentTypeContainer}}
+		declaration = &d
+	})
+
+	It("should create the listing and its components in the target, recording their IDs", func() {
+		overlay := &resource.Overlay{}
+		results, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, overlay)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(Equal([]result{
+			{Kind: "ProductListing", Name: "My Product", ID: "prod-listing-1", Action: actionCreated},
+			{Kind: "Component", Name: "api", ID: "prod-component-1", Action: actionCreated},
+		}))
+
+		Expect(string(overlay.Bytes())).To(Equal("spec:\n  _id: prod-listing-1\nwith:\n  components:\n    - name: api\n      _id: prod-component-1\n"))
+
+		Expect(prod.listings["prod-listing-1"]).To(HaveKeyWithValue("cert_projects", ConsistOf("prod-component-1")))
+		Expect(prod.listings["prod-listing-1"]).ToNot(HaveKey("org_id"))
+		Expect(prod.components["prod-component-1"]).ToNot(HaveKey("org_id"))
+		Expect(stage.Count("ProductByID")).To(Equal(1))
+		Expect(slices.Collect(maps.Keys(stage.Requests))).To(ConsistOf("ProductByID", "ComponentsForListing"))
+	})
+
+	It("should skip unchanged objects, and update those that changed", func() {
+		overlay := &resource.Overlay{}
+		_, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, overlay)
+		Expect(err).ToNot(HaveOccurred())
+
+		results, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, overlay)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(HaveEach(HaveField("Action", actionUnchanged)))
+		Expect(prod.Count("ApplyComponent")).To(BeZero())
+		Expect(prod.Count("ApplyProductListing")).To(BeZero())
+
+		stage.components["stage-api"]["container"] = map[string]any{"registry": "quay.io"}
+		declaration.With.Components[0].Container = &resource.ContainerComponent{Registry: "quay.io"}
+		results, err = promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, overlay)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(results).To(Equal([]result{
+			{Kind: "ProductListing", Name: "My Product", ID: "prod-listing-1", Action: actionUnchanged},
+			{Kind: "Component", Name: "api", ID: "prod-component-1", Action: actionUpdated},
+		}))
+		Expect(prod.components["prod-component-1"]).To(HaveKeyWithValue("container", HaveKeyWithValue("registry", "quay.io")))
+	})
+
+	It("should read IDs and values of each environment from its overlay", func() {
+		stage.components["stage-api"]["container"] = map[string]any{"registry": "stage.example.com"}
+		declaration.Spec.ID = ""
+		declaration.With.Components[0].ID = ""
+
+		stageOverlay, err := resource.ReadOverlay(bytes.NewBufferString(`spec:
+  _id: stage-listing
+with:
+  components:
+  - name: api
+    _id: stage-api
+    container:
+      registry: stage.example.com
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		prodOverlay, err := resource.ReadOverlay(bytes.NewBufferString(`with:
+  components:
+  - name: api
+    container:
+      registry: prod.example.com
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = promote(context.TODO(), stage, prod, "stage", "prod", declaration, stageOverlay, prodOverlay)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prod.components["prod-component-1"]).To(HaveKeyWithValue("container", HaveKeyWithValue("registry", "prod.example.com")))
+
+		written := string(prodOverlay.Bytes())
+		Expect(written).To(ContainSubstring("_id: prod-listing-1"))
+		Expect(written).To(ContainSubstring("_id: prod-component-1"))
+		Expect(written).To(ContainSubstring("registry: prod.example.com"))
+	})
+
+	It("should refuse to promote a declaration the source environment does not hold", func() {
+		declaration.Spec.Name = "My Renamed Product"
+		declaration.With.Components = append(declaration.With.Components, &resource.Component{Name: "ui"})
+
+		_, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, &resource.Overlay{})
+		Expect(err).To(MatchError(ErrSourceDiffers))
+		Expect(err).To(MatchError(ContainSubstring("spec.name, with.components[name=ui]")))
+		Expect(prod.Requests).To(BeEmpty())
+	})
+
+	It("should refuse declarations that have not been applied to the source environment", func() {
+		_, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, &resource.Overlay{}, &resource.Overlay{})
+		Expect(err).To(MatchError(ErrNotApplied))
+
+		declaration.Spec.ID = ""
+		_, err = promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, &resource.Overlay{})
+		Expect(err).To(MatchError(ErrNotApplied))
+	})
+
+	It("should refuse components without distinct names", func() {
+		declaration.With.Components = append(declaration.With.Components, &resource.Component{Name: "api"})
+		_, err := promote(context.TODO(), stage, prod, "stage", "prod", declaration, nil, &resource.Overlay{})
+		Expect(err).To(MatchError(ErrComponentNames))
+	})
+})
@@ -0,0 +1,41 @@
+package promote_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/promote"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("Promote", func() {
+	var declaration string
+
+	BeforeEach(func() {
+		os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+		DeferCleanup(os.Setenv, "PRODUCTCTL_API_TOKEN", "")
+
+		declaration = filepath.Join(GinkgoT().TempDir(), "my.product.yaml")
+		Expect(os.WriteFile(declaration, []byte("kind: ProductListing\nspec:\n  name: mine\n---\nkind: ProductListing\nspec:\n  name: other\n"), 0o644)).To(Succeed())
+	})
+
+	It("should require both environments", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "promote", declaration, "--to", "prod")
+		Expect(err).To(MatchError(ContainSubstring(cli.FlagIDFrom)))
+	})
+
+	It("should refuse to promote a declaration to its own environment", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "promote", declaration, "--from", "stage", "--to", "stage")
+		Expect(err).To(MatchError(promote.ErrSameEnv))
+	})
+
+	It("should refuse files that do not hold exactly one declaration", func() {
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "promote", declaration, "--from", "stage", "--to", "prod")
+		Expect(err).To(MatchError(promote.ErrNotOneDeclaration))
+	})
+})
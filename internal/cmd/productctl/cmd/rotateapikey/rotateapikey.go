@@ -0,0 +1,156 @@
+// Package rotateapikey implements the auth keys rotate subcommand.
+package rotateapikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Khan/genqlient/graphql"
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/catalogapi"
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/file"
+	"github.com/opdev/productctl/internal/logger"
+)
+
+var (
+	ErrRotateRequiresTokenFile = errors.New("rotating API keys requires api-token-file to be configured, and api-token to be unset")
+	ErrVerifyingNewKey         = errors.New("the newly created API key could not be verified")
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate <current-key-id>",
+		Short: "Replace the API key in your api-token-file with a new one",
+		Long: `Replace the API key in your api-token-file with a new one.
+
+A new API key is created and atomically written to your configured api-token-file. The new key is then verified against the API. Once verified, the key identified by <current-key-id> is deleted.
+
+If verification fails, the original content of your api-token-file is restored and the new key is deleted.`,
+		Args: cobra.ExactArgs(1), // The current key ID
+		RunE: runE,
+	}
+
+	cmd.Flags().String(cli.FlagIDDescription, "", "A description for the new API key. Defaults to a generated description including the date")
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	oldKeyID, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("key ID must be an integer: %w", err)
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	if cfg.APITokenFile == "" || cfg.APIToken != "" {
+		return ErrRotateRequiresTokenFile
+	}
+
+	token, err := cfg.Token()
+	if err != nil {
+		return err
+	}
+
+	var endpoint string
+	if cmd.Flags().Changed(cli.FlagIDCustomEndpoint) {
+		endpoint, _ = cmd.Flags().GetString(cli.FlagIDCustomEndpoint)
+		L.Debug("custom endpoint set, using it over env value", "endpoint", endpoint)
+	} else {
+		endpoint, err = cli.ResolveAPIEndpoint(cfg.Env)
+		if err != nil {
+			return err
+		}
+		L.Debug("endpoint resolved", "endpoint", endpoint)
+	}
+
+	description, _ := cmd.Flags().GetString(cli.FlagIDDescription)
+	if description == "" {
+		description = fmt.Sprintf("rotated by productctl on %s", time.Now().UTC().Format(time.DateOnly))
+	}
+
+	return run(cmd.Context(), cfg.OrgID, oldKeyID, description, cfg.APITokenFile, token, endpoint)
+}
+
+func run(
+	ctx context.Context,
+	orgID int,
+	oldKeyID int,
+	description string,
+	tokenFile string,
+	token string,
+	endpoint catalogapi.APIEndpoint,
+) error {
+	L := logger.FromContextOrDiscard(ctx)
+
+	originalTokenFileContent, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return err
+	}
+
+	L.Debug("building graphql client")
+	client := graphql.NewClient(endpoint, catalogapi.TokenAuthenticatedHTTPClient(token, L.With("name", "httpclient")))
+
+	L.Info("creating new api key")
+	newToken, newKey, err := catalogapi.CreateAPIKey(ctx, client, orgID, description)
+	if err != nil {
+		return err
+	}
+
+	L.Info("writing new api key to token file", "id", newKey.GetId(), "file", tokenFile)
+	if err := file.WritePrivateAtomic(tokenFile, []byte(newToken+"\n")); err != nil {
+		return errors.Join(err, rollback(ctx, client, orgID, newKey.GetId(), tokenFile, nil))
+	}
+
+	L.Info("verifying new api key")
+	newClient := graphql.NewClient(endpoint, catalogapi.TokenAuthenticatedHTTPClient(newToken, L.With("name", "httpclient")))
+	if _, err := catalogapi.ListAPIKeys(ctx, newClient, orgID); err != nil {
+		return errors.Join(ErrVerifyingNewKey, err, rollback(ctx, client, orgID, newKey.GetId(), tokenFile, originalTokenFileContent))
+	}
+
+	L.Info("deleting previous api key", "id", oldKeyID)
+	if err := catalogapi.DeleteAPIKey(ctx, newClient, orgID, oldKeyID); err != nil {
+		return fmt.Errorf("new api key %d is in place, but the previous api key %d could not be deleted: %w", newKey.GetId(), oldKeyID, err)
+	}
+
+	L.Info("done")
+	return nil
+}
+
+// rollback deletes the newly created key, and restores the token file to its
+// original content if provided.
+func rollback(
+	ctx context.Context,
+	client graphql.Client,
+	orgID int,
+	newKeyID int,
+	tokenFile string,
+	originalTokenFileContent []byte,
+) error {
+	L := logger.FromContextOrDiscard(ctx)
+	L.Warn("rolling back api key rotation", "id", newKeyID)
+
+	var errs []error
+	if originalTokenFileContent != nil {
+		if err := file.WritePrivateAtomic(tokenFile, originalTokenFileContent); err != nil {
+			errs = append(errs, fmt.Errorf("unable to restore token file %s: %w", tokenFile, err))
+		}
+	}
+
+	if err := catalogapi.DeleteAPIKey(ctx, client, orgID, newKeyID); err != nil {
+		errs = append(errs, fmt.Errorf("unable to delete new api key %d: %w", newKeyID, err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,13 @@
+package rotateapikey_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRotateAPIKey(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RotateAPIKey Suite")
+}
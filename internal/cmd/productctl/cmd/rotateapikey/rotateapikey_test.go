@@ -0,0 +1,59 @@
+package rotateapikey_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/rotateapikey"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+)
+
+var _ = Describe("RotateAPIKey", func() {
+	When("using the auth keys rotate command", func() {
+		When("the API token is configured directly", func() {
+			BeforeEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "foo")
+			})
+
+			AfterEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN", "")
+			})
+
+			It("should refuse to rotate", func() {
+				_, err := testutils.ExecuteCommand(cmd.RootCmd(), "auth", "keys", "rotate", "123", "--org-id", "1", "--custom-endpoint", "http://localhost:9630")
+				Expect(err).To(MatchError(rotateapikey.ErrRotateRequiresTokenFile))
+			})
+		})
+
+		When("the API token file is configured", func() {
+			var tokenFile string
+
+			BeforeEach(func() {
+				tokenFile = filepath.Join(GinkgoT().TempDir(), "token")
+				Expect(os.WriteFile(tokenFile, []byte("foo\n"), 0o600)).To(Succeed())
+				os.Setenv("PRODUCTCTL_API_TOKEN_FILE", tokenFile)
+			})
+
+			AfterEach(func() {
+				os.Setenv("PRODUCTCTL_API_TOKEN_FILE", "")
+			})
+
+			It("should reach the creation phase, then fail without modifying the token file", func() {
+				// Endpoint is spoofed to avoid spamming actual endpoints with requests
+				output, err := testutils.ExecuteCommand(cmd.RootCmd(), "auth", "keys", "rotate", "123", "--org-id", "1", "--custom-endpoint", "http://localhost:9630")
+				// We still expect an error here until business logic mocks have been implemented.
+				Expect(err).To(HaveOccurred())
+				Expect(output).To(ContainSubstring(syscall.ECONNREFUSED.Error()))
+
+				content, err := os.ReadFile(tokenFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("foo\n"))
+			})
+		})
+	})
+})
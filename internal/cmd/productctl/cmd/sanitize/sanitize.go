@@ -3,46 +3,81 @@ package sanitize
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"sigs.k8s.io/yaml"
 
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
 	"github.com/opdev/productctl/internal/resource"
 )
 
 func Command() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "sanitize <your-declaration.yaml>",
+		Use:   "sanitize <your-declaration.yaml|directory> [...]",
 		Short: "Cleans declaration for re-use and emits to stdout",
-		Long:  "Strips data from the product declaration on disk that associates a product with an entry in the backend. Does not impact the backend, or overwrite the input file.",
-		Args:  cobra.ExactArgs(1),
-		RunE:  sanitizeProductCmdRunE,
+		Long: `Strips data from the product declaration on disk that associates a product with an entry in the backend, and every other value managed by the backend, such as the badges awarded to components. Does not impact the backend, or overwrite the input file.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. All sanitized declarations are emitted together, as a single multi-document YAML stream unless --output selects another format.`,
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: sanitizeProductCmdRunE,
 	}
 
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
 	return cmd
 }
 
 func sanitizeProductCmdRunE(cmd *cobra.Command, args []string) error {
-	f, err := os.Open(args[0])
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, printer.DeclarationColumns)
 	if err != nil {
 		return err
 	}
 
-	r, err := resource.ReadProductListing(f)
+	cfg, err := cli.Config()
 	if err != nil {
 		return err
 	}
 
-	r.Sanitize()
+	recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
 
-	b, err := yaml.Marshal(r)
-	if err != nil {
-		return err
+	files := []string{}
+	for _, arg := range args {
+		found, err := resource.DeclarationFiles(arg, recursive, resource.KindProductListing)
+		if err != nil {
+			return err
+		}
+		files = append(files, found...)
 	}
-	_, err = fmt.Fprint(cmd.OutOrStdout(), string(b))
-	if err != nil {
-		return err
+
+	sanitized := []*resource.ProductListingDeclaration{}
+	for _, filename := range files {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+
+		stream, err := resource.ReadDeclarationStream(f, resource.WithStrict(cfg.Strict), resource.WithBaseDir(filepath.Dir(filename)), resource.WithWarningHandler(func(warning string) {
+			L.Warn(warning, "source", filename)
+		}))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+
+		for _, r := range stream.Declarations() {
+			r.Sanitize()
+			sanitized = append(sanitized, r)
+		}
 	}
 
-	return nil
+	return p.Print(cmd.OutOrStdout(), sanitized...)
 }
@@ -4,12 +4,16 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/sanitize"
 	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
 )
 
 const testProductFixture = "testdata/fixture.test.product.yaml"
@@ -65,5 +69,75 @@ var _ = Describe("Sanitize", func() {
 			Expect(output).ToNot(ContainSubstring("org_id"))
 			Expect(output).ToNot(ContainSubstring("_id"))
 		})
+
+		When("the file contains several declarations", func() {
+			BeforeEach(func() {
+				fixture, err := os.ReadFile(testProductFixture)
+				Expect(err).ToNot(HaveOccurred())
+				multi := string(fixture) + "\n---\n" + string(fixture)
+				Expect(os.WriteFile(tempProduct, []byte(multi), 0o644)).To(Succeed())
+			})
+
+			It("should emit every declaration sanitized, as a multi-document stream", func() {
+				output, err := testutils.ExecuteCommand(sanitize.Command(), tempProduct)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.Count(output, "kind: ProductListing")).To(Equal(2))
+				Expect(strings.Count(output, "---\n")).To(Equal(1))
+				Expect(output).ToNot(ContainSubstring("_id"))
+			})
+		})
+
+		It("should emit the requested output format", func() {
+			output, err := testutils.ExecuteCommand(sanitize.Command(), tempProduct, "-o", "jsonpath={.spec.name}")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal("test-product\n"))
+		})
+
+		When("the declaration contains unknown fields", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(tempProduct, []byte("kind: ProductListing\nspec:\n  name: test-product\n  nmae: typo\n"), 0o644)).To(Succeed())
+			})
+
+			It("should ignore them by default", func() {
+				_, err := testutils.ExecuteCommand(sanitize.Command(), tempProduct)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should report them when strict, without requiring an API token", func() {
+				_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "sanitize", "--strict", tempProduct)
+				Expect(err).To(MatchError(resource.ErrUnknownFields))
+				Expect(err.Error()).To(ContainSubstring("line 4, column 3: spec.nmae"))
+			})
+
+			It("should report them when strict is configured", func() {
+				os.Setenv("PRODUCTCTL_STRICT", "true")
+				defer os.Unsetenv("PRODUCTCTL_STRICT")
+
+				_, err := testutils.ExecuteCommand(sanitize.Command(), tempProduct)
+				Expect(err).To(MatchError(resource.ErrUnknownFields))
+			})
+		})
+
+		When("a directory is provided", func() {
+			BeforeEach(func() {
+				nested := filepath.Join(tempDirPath, "nested")
+				Expect(os.Mkdir(nested, 0o755)).To(Succeed())
+				fixture, err := os.ReadFile(testProductFixture)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(os.WriteFile(filepath.Join(nested, "other.product.yaml"), fixture, 0o644)).To(Succeed())
+			})
+
+			It("should only sanitize the top-level declarations by default", func() {
+				output, err := testutils.ExecuteCommand(sanitize.Command(), tempDirPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.Count(output, "kind: ProductListing")).To(Equal(1))
+			})
+
+			It("should sanitize nested declarations when recursive", func() {
+				output, err := testutils.ExecuteCommand(sanitize.Command(), "-R", tempDirPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(strings.Count(output, "kind: ProductListing")).To(Equal(2))
+			})
+		})
 	})
 })
@@ -0,0 +1,84 @@
+// Package sanitizecomponent implements the component sanitize subcommand.
+package sanitizecomponent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sanitize <your-component.yaml|directory> [...]",
+		Short: "Cleans component declarations for re-use and emits to stdout",
+		Long: `Strips data from the component declarations on disk that associates a component with an entry in the backend. Does not impact the backend, or overwrite the input file.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. All sanitized declarations are emitted together, as a single multi-document YAML stream unless --output selects another format.`,
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: sanitizeComponentRunE,
+	}
+
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().StringP(cli.FlagIDOutput, "o", printer.FormatYAML, printer.Usage(true))
+
+	return cmd
+}
+
+func sanitizeComponentRunE(cmd *cobra.Command, args []string) error {
+	L := logger.FromContextOrDiscard(cmd.Context())
+
+	output, _ := cmd.Flags().GetString(cli.FlagIDOutput)
+	p, err := printer.New(output, printer.ComponentColumns)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
+
+	files := []string{}
+	for _, arg := range args {
+		found, err := resource.DeclarationFiles(arg, recursive, resource.KindComponent)
+		if err != nil {
+			return err
+		}
+		files = append(files, found...)
+	}
+
+	sanitized := []*resource.ComponentDeclaration{}
+	for _, filename := range files {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+
+		stream, err := resource.ReadComponentStream(f, resource.WithStrict(cfg.Strict), resource.WithBaseDir(filepath.Dir(filename)), resource.WithWarningHandler(func(warning string) {
+			L.Warn(warning, "source", filename)
+		}))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+
+		for _, c := range stream.Declarations() {
+			c.Sanitize()
+			sanitized = append(sanitized, c)
+		}
+	}
+
+	return p.Print(cmd.OutOrStdout(), sanitized...)
+}
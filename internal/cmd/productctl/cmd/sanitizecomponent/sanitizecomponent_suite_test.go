@@ -0,0 +1,13 @@
+package sanitizecomponent_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSanitizeComponent(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SanitizeComponent Suite")
+}
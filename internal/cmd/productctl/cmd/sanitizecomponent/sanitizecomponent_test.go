@@ -0,0 +1,54 @@
+package sanitizecomponent_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/sanitizecomponent"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("SanitizeComponent", func() {
+	var file string
+
+	BeforeEach(func() {
+		file = filepath.Join(GinkgoT().TempDir(), "base.component.yaml")
+		content := "kind: Component\nspec:\n  _id: component-id\n  org_id: 1\n  name: base-image\n  type: Containers\n  container:\n    isv_pid: pid\n    registry: quay.io\n"
+		Expect(os.WriteFile(file, []byte(content), 0o644)).To(Succeed())
+	})
+
+	It("should throw an error if the file is not found", func() {
+		_, err := testutils.ExecuteCommand(sanitizecomponent.Command(), "foofile")
+		Expect(err).To(MatchError(fs.ErrNotExist))
+	})
+
+	It("should no longer contain data pertinent to the original component", func() {
+		output, err := testutils.ExecuteCommand(sanitizecomponent.Command(), file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("kind: Component"))
+		Expect(output).To(ContainSubstring("registry: quay.io"))
+		Expect(output).ToNot(ContainSubstring("_id"))
+		Expect(output).ToNot(ContainSubstring("org_id"))
+		Expect(output).ToNot(ContainSubstring("isv_pid"))
+	})
+
+	It("should not require an API token", func() {
+		output, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "sanitize", file)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("name: base-image"))
+	})
+
+	It("should report unknown fields when strict", func() {
+		Expect(os.WriteFile(file, []byte("kind: Component\nspec:\n  name: base-image\n  nmae: typo\n"), 0o644)).To(Succeed())
+
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "component", "sanitize", "--strict", file)
+		Expect(err).To(MatchError(resource.ErrUnknownFields))
+		Expect(err.Error()).To(ContainSubstring("spec.nmae"))
+	})
+})
@@ -0,0 +1,76 @@
+package testutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Khan/genqlient/graphql"
+)
+
+// ErrUnexpectedOperation is returned by a FakeClient for operations it has no
+// response for.
+var ErrUnexpectedOperation = errors.New("unexpected operation")
+
+// Response returns the data answering a request made with variables. The data
+// is encoded as JSON, e.g. from a map[string]any or a json.RawMessage, and
+// decoded as the backend's response would be.
+type Response func(variables map[string]any) (any, error)
+
+// JSON returns a Response that always answers with body, a JSON document.
+func JSON(body string) Response {
+	return func(map[string]any) (any, error) {
+		return json.RawMessage(body), nil
+	}
+}
+
+// FakeClient is a graphql.Client that answers each operation with the
+// Response held for its name in Responses, and records the variables of each
+// request made. It should only be used in tests.
+type FakeClient struct {
+	// Responses answer the operations, by name. Other operations fail with
+	// ErrUnexpectedOperation.
+	Responses map[string]Response
+	// Requests holds the variables of the requests made, by operation name,
+	// in the order they were made.
+	Requests map[string][]map[string]any
+}
+
+// Count returns the number of requests made for operation.
+func (c *FakeClient) Count(operation string) int {
+	return len(c.Requests[operation])
+}
+
+func (c *FakeClient) MakeRequest(_ context.Context, req *graphql.Request, resp *graphql.Response) error {
+	b, err := json.Marshal(req.Variables)
+	if err != nil {
+		return err
+	}
+
+	var variables map[string]any
+	if err := json.Unmarshal(b, &variables); err != nil {
+		return err
+	}
+
+	if c.Requests == nil {
+		c.Requests = map[string][]map[string]any{}
+	}
+	c.Requests[req.OpName] = append(c.Requests[req.OpName], variables)
+
+	respond, ok := c.Responses[req.OpName]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnexpectedOperation, req.OpName)
+	}
+
+	data, err := respond(variables)
+	if err != nil {
+		return err
+	}
+
+	if b, err = json.Marshal(data); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, resp.Data)
+}
@@ -0,0 +1,174 @@
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/opdev/productctl/internal/cli"
+	"github.com/opdev/productctl/internal/logger"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var (
+	ErrValidationFailed     = errors.New("some declarations are invalid")
+	ErrOverlayRequiresFiles = errors.New("overlays cannot be applied to declarations read from stdin")
+)
+
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <your-declaration.yaml|directory|-> [...]",
+		Short: "Validates declarations against the product listing schema without contacting the backend",
+		Long: `Checks declarations against the constraints of the product listing schema, such as allowed values, required lengths, and the number of items in lists, without contacting the backend. Every violation is reported along with its path in the declaration.
+
+A file may contain several declarations as a multi-document YAML stream. Directories are read for .yaml and .yml files, descending into subdirectories with --recursive. Use "-" to read declarations from stdin.
+
+The overlay of each file for the active environment, or that chosen with --overlay, is merged into its declarations before they are validated, as it is by apply. Use --overlay="" to validate the declarations as they are.
+
+The same validation runs before any changes are made by apply.`,
+		Args: cobra.MinimumNArgs(1),
+		Annotations: map[string]string{
+			cli.AnnotationOffline: "true",
+		},
+		RunE: runE,
+	}
+
+	cmd.Flags().BoolP(cli.FlagIDRecursive, "R", false, "Read declarations from subdirectories of any directory provided")
+	cmd.Flags().String(cli.FlagIDOverlay, "", "The overlay merged into each declaration before it is validated. Defaults to the active environment, if its overlay exists")
+	cmd.Flags().StringArray(cli.FlagIDValues, nil, cli.ValuesUsage)
+	cmd.Flags().Bool(cli.FlagIDInterpolate, false, cli.InterpolateUsage)
+
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	cfg, err := cli.Config()
+	if err != nil {
+		return err
+	}
+
+	variables, err := cli.Variables(cmd)
+	if err != nil {
+		return err
+	}
+
+	readOpts := []resource.ReadOption{resource.WithStrict(cfg.Strict), resource.WithVariables(variables)}
+	overlay := cli.SelectedOverlay(cmd, cfg)
+
+	if len(args) == 1 && args[0] == "-" {
+		if overlay.Explicit && overlay.Name != "" {
+			return ErrOverlayRequiresFiles
+		}
+
+		o, err := validateStream(cmd.Context(), "stdin", cmd.InOrStdin(), nil, readOpts...)
+		o.print(cmd.OutOrStdout())
+		return err
+	}
+
+	recursive, _ := cmd.Flags().GetBool(cli.FlagIDRecursive)
+
+	total := outcome{}
+	var errs []error
+	for _, arg := range args {
+		files, err := resource.DeclarationFiles(arg, recursive, resource.KindProductListing)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, filename := range files {
+			o, err := validateFile(cmd.Context(), filename, overlay, readOpts...)
+			total.add(o)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	total.print(cmd.OutOrStdout())
+
+	if len(errs) > 0 {
+		return errors.Join(append([]error{ErrValidationFailed}, errs...)...)
+	}
+
+	return nil
+}
+
+func validateFile(ctx context.Context, filename string, overlay cli.OverlaySelection, readOpts ...resource.ReadOption) (outcome, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return outcome{}, err
+	}
+	defer f.Close()
+
+	readOpts = append(slices.Clip(readOpts), resource.WithBaseDir(filepath.Dir(filename)))
+	ov, err := overlay.Read(filename, readOpts...)
+	if err != nil {
+		return outcome{unreadable: 1}, err
+	}
+
+	return validateStream(ctx, filename, f, ov, readOpts...)
+}
+
+// validateStream validates each declaration read from in, with ov applied if
+// it is not nil, returning an error describing every violation found.
+func validateStream(ctx context.Context, source string, in io.Reader, ov *resource.Overlay, readOpts ...resource.ReadOption) (outcome, error) {
+	L := logger.FromContextOrDiscard(ctx)
+
+	stream, err := resource.ReadDeclarationStream(in, append(slices.Clip(readOpts), resource.WithWarningHandler(func(warning string) {
+		L.Warn(warning, "source", source)
+	}))...)
+	if err != nil {
+		return outcome{unreadable: 1}, fmt.Errorf("%s: %w", source, err)
+	}
+
+	o := outcome{}
+	var errs []error
+	for i, declaration := range stream.Declarations() {
+		if ov != nil {
+			declaration, err = ov.Apply(i, declaration)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: declaration %d: %w", source, i+1, err))
+				o.invalid++
+				continue
+			}
+		}
+
+		if err := declaration.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: declaration %d (%s): %w", source, i+1, declaration.Spec.Name, err))
+			o.invalid++
+			continue
+		}
+		o.valid++
+	}
+
+	return o, errors.Join(errs...)
+}
+
+// outcome counts the declarations that were validated.
+type outcome struct {
+	valid      int
+	invalid    int
+	unreadable int
+}
+
+func (o *outcome) add(other outcome) {
+	o.valid += other.valid
+	o.invalid += other.invalid
+	o.unreadable += other.unreadable
+}
+
+// print writes a summary of o to w.
+func (o outcome) print(w io.Writer) {
+	fmt.Fprintf(w, "%d declaration(s) valid, %d invalid", o.valid, o.invalid)
+	if o.unreadable > 0 {
+		fmt.Fprintf(w, ", %d file(s) could not be read", o.unreadable)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,139 @@
+package validate_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/testutils"
+	"github.com/opdev/productctl/internal/cmd/productctl/cmd/validate"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+const (
+	validDeclaration = `kind: ProductListing
+spec:
+  name: valid-product
+  type: container stack
+  descriptions:
+    short: A short description that is long enough to satisfy the schema.
+`
+	invalidDeclaration = `kind: ProductListing
+spec:
+  name: invalid-product
+  type: not a type
+  descriptions:
+    short: Too short
+`
+)
+
+var _ = Describe("Validate", func() {
+	var tempDirPath string
+
+	BeforeEach(func() {
+		var err error
+		tempDirPath, err = os.MkdirTemp("", "productctl-unit-test-*")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDirPath)).To(Succeed())
+	})
+
+	write := func(name, contents string) string {
+		path := filepath.Join(tempDirPath, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	When("the file is not found", func() {
+		It("should throw an error", func() {
+			_, err := testutils.ExecuteCommand(validate.Command(), "foofile")
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+	})
+
+	It("should succeed when every declaration is valid", func() {
+		path := write("valid.yaml", validDeclaration+"---\n"+validDeclaration)
+		output, err := testutils.ExecuteCommand(validate.Command(), path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("2 declaration(s) valid, 0 invalid"))
+	})
+
+	It("should report every violation with its path", func() {
+		path := write("invalid.yaml", validDeclaration+"---\n"+invalidDeclaration)
+		output, err := testutils.ExecuteCommand(validate.Command(), path)
+		Expect(err).To(MatchError(validate.ErrValidationFailed))
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring(path + ": declaration 2 (invalid-product)"))
+		Expect(err.Error()).To(ContainSubstring("spec.type: "))
+		Expect(err.Error()).To(ContainSubstring("spec.descriptions.short: must be at least 50 characters long"))
+		Expect(output).To(ContainSubstring("1 declaration(s) valid, 1 invalid"))
+	})
+
+	It("should validate every declaration in a directory", func() {
+		write("a.yaml", validDeclaration)
+		write("b.yaml", invalidDeclaration)
+		output, err := testutils.ExecuteCommand(validate.Command(), tempDirPath)
+		Expect(err).To(MatchError(validate.ErrValidationFailed))
+		Expect(output).To(ContainSubstring("1 declaration(s) valid, 1 invalid"))
+	})
+
+	It("should skip the component declarations and values files alongside listings in a directory", func() {
+		write("listing.product.yaml", validDeclaration+"with:\n  component_refs:\n  - file: base.component.yaml\n")
+		write("base.component.yaml", "kind: Component\nspec:\n  name: base-image\n  type: Containers\n")
+		write("unreferenced.component.yaml", "kind: Component\nspec:\n  name: other-image\n  type: Containers\n")
+		write("values.yaml", "NAME: valid-product\n")
+
+		output, err := testutils.ExecuteCommand(validate.Command(), tempDirPath, "-R")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(ContainSubstring("1 declaration(s) valid, 0 invalid"))
+	})
+
+	When("the declaration has an overlay", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = write("listing.product.yaml", validDeclaration)
+			write("listing.product.stage.overlay.yaml", "spec:\n  type: not a type\n")
+		})
+
+		It("should validate the declaration with the overlay applied", func() {
+			output, err := testutils.ExecuteCommand(validate.Command(), path, "--overlay", "stage")
+			Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+			Expect(err.Error()).To(ContainSubstring("spec.type: "))
+			Expect(output).To(ContainSubstring("0 declaration(s) valid, 1 invalid"))
+		})
+
+		It("should not read overlays as declarations", func() {
+			output, err := testutils.ExecuteCommand(validate.Command(), tempDirPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(ContainSubstring("1 declaration(s) valid, 0 invalid"))
+		})
+
+		It("should refuse overlays for declarations read from stdin", func() {
+			_, err := testutils.ExecuteCommand(validate.Command(), "-", "--overlay", "stage")
+			Expect(err).To(MatchError(validate.ErrOverlayRequiresFiles))
+		})
+	})
+
+	It("should validate declarations read from stdin", func() {
+		command := validate.Command()
+		command.SetIn(strings.NewReader(invalidDeclaration))
+		output, err := testutils.ExecuteCommand(command, "-")
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(output).To(ContainSubstring("0 declaration(s) valid, 1 invalid"))
+	})
+
+	It("should not require an API token", func() {
+		os.Unsetenv("PRODUCTCTL_API_TOKEN")
+		path := write("valid.yaml", validDeclaration)
+		_, err := testutils.ExecuteCommand(cmd.RootCmd(), "product", "validate", path)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
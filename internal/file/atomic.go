@@ -0,0 +1,73 @@
+package file
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WriteAtomic writes data to filename such that readers will either see the
+// original contents or the new contents, but never a partial write. This is
+// done by writing to a temporary file in the same directory and renaming it
+// over filename. If filename already exists, its permissions are preserved.
+// Otherwise, the file is created with perm.
+func WriteAtomic(filename string, data []byte, perm fs.FileMode) error {
+	if info, err := os.Stat(filename); err == nil {
+		perm = info.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return writeAtomic(filename, data, perm)
+}
+
+// WritePrivateAtomic writes data to filename atomically, as WriteAtomic does,
+// but always leaves filename readable and writable by its owner only, even if
+// it previously existed with broader permissions. It is meant for files
+// holding secrets such as API tokens.
+func WritePrivateAtomic(filename string, data []byte) error {
+	return writeAtomic(filename, data, 0o600)
+}
+
+// writeAtomic writes data to filename atomically, with perm.
+func writeAtomic(filename string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	// The temporary file is removed unless it has been successfully renamed.
+	tmpName := tmp.Name()
+	renamed := false
+	defer func() {
+		if !renamed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpName, filename); err != nil {
+		return err
+	}
+
+	renamed = true
+	return nil
+}
@@ -0,0 +1,92 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Atomic", func() {
+	When("writing a file atomically", func() {
+		var workingDirectory string
+
+		BeforeEach(func() {
+			workingDirectory = GinkgoT().TempDir()
+		})
+
+		When("the file does not exist", func() {
+			It("should create it with the provided permissions", func() {
+				target := filepath.Join(workingDirectory, "token")
+				err := WriteAtomic(target, []byte("newdata"), 0o600)
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := os.ReadFile(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("newdata"))
+
+				info, err := os.Stat(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Mode().Perm()).To(BeEquivalentTo(0o600))
+			})
+		})
+
+		When("the file already exists", func() {
+			var target string
+
+			BeforeEach(func() {
+				target = filepath.Join(workingDirectory, "token")
+				err := os.WriteFile(target, []byte("olddata"), 0o640)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should replace the content and preserve the original permissions", func() {
+				err := WriteAtomic(target, []byte("newdata"), 0o600)
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := os.ReadFile(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("newdata"))
+
+				info, err := os.Stat(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Mode().Perm()).To(BeEquivalentTo(0o640))
+			})
+
+			It("should not leave temporary files behind", func() {
+				err := WriteAtomic(target, []byte("newdata"), 0o600)
+				Expect(err).ToNot(HaveOccurred())
+
+				entries, err := os.ReadDir(workingDirectory)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+			})
+		})
+
+		When("writing a private file", func() {
+			It("should restrict the permissions of an existing file", func() {
+				target := filepath.Join(workingDirectory, "token")
+				Expect(os.WriteFile(target, []byte("olddata"), 0o644)).To(Succeed())
+
+				err := WritePrivateAtomic(target, []byte("newdata"))
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := os.ReadFile(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("newdata"))
+
+				info, err := os.Stat(target)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Mode().Perm()).To(BeEquivalentTo(0o600))
+			})
+		})
+
+		When("the target directory does not exist", func() {
+			It("should return an error", func() {
+				err := WriteAtomic(filepath.Join(workingDirectory, "missing", "token"), []byte("newdata"), 0o600)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -23,9 +25,17 @@ var ErrBackupError = errors.New("error creating backup")
 // Otherwise, the os.OpenFile call will truncate a given file's contents when
 // called. For this reason, all other open operations to Filename should use
 // read-only operations.
+//
+// A LazyOverwriter may be written to more than once, in which case each write
+// replaces the file's contents. At most one backup is created, before the
+// first write, so that it always holds the original contents.
 type LazyOverwriter struct {
 	Filename string
 	DoBackup bool
+	// CreateIfMissing creates Filename on write if it does not exist, in
+	// which case there is nothing to back up. Otherwise, writing to a file
+	// that does not exist fails.
+	CreateIfMissing bool
 	// OptionalLogger is a logger that will emit information about file
 	// writing operations. If not set, logs will be discarded.
 	OptionalLogger *slog.Logger
@@ -33,6 +43,8 @@ type LazyOverwriter struct {
 	// BackupFilenameGenFn is a configurable string generator, taking an input
 	// string and returning a modified representation.
 	BackupFilenameGenFn BackupNameGenerator
+
+	backedUp bool
 }
 
 func (w *LazyOverwriter) logger() *slog.Logger {
@@ -78,17 +90,27 @@ func (w *LazyOverwriter) CreateBackup() error {
 }
 
 func (w *LazyOverwriter) Write(p []byte) (int, error) {
-	if w.DoBackup {
+	flag := os.O_RDWR | os.O_TRUNC
+	if w.CreateIfMissing {
+		if _, err := os.Stat(w.Filename); errors.Is(err, os.ErrNotExist) {
+			w.logger().Debug("creating file", "filename", w.Filename)
+			flag |= os.O_CREATE
+			w.backedUp = true
+		}
+	}
+
+	if w.DoBackup && !w.backedUp {
 		w.logger().Debug("backup before overwriting was requested")
 		err := w.CreateBackup()
 		if err != nil {
 			return 0, err
 		}
+		w.backedUp = true
 		w.logger().Debug("backup completed successfully")
 	}
 
 	w.logger().Debug("overwriting original file")
-	f, err := os.OpenFile(w.Filename, os.O_RDWR|os.O_TRUNC, 0o400)
+	f, err := os.OpenFile(w.Filename, flag, 0o644)
 	if err != nil {
 		return 0, err
 	}
@@ -110,3 +132,55 @@ var _ BackupNameGenerator = prependWithSecondsSinceEpoch
 // BackupNameGenerator describes the function to accept an originalBaseName and
 // produce a newBaseName.
 type BackupNameGenerator = func(originalBaseName string) (newBaseName string)
+
+// Overwriters provides a LazyOverwriter for each of several files, all
+// configured alike. The same LazyOverwriter is provided for each file every
+// time, so that a file written more than once is backed up at most once.
+type Overwriters struct {
+	DoBackup bool
+	// OptionalLogger is a logger that will emit information about file
+	// writing operations. If not set, logs will be discarded.
+	OptionalLogger *slog.Logger
+
+	writers map[string]*LazyOverwriter
+}
+
+// For returns the LazyOverwriter of filename.
+func (o *Overwriters) For(filename string) *LazyOverwriter {
+	if o.writers == nil {
+		o.writers = map[string]*LazyOverwriter{}
+	}
+
+	w, ok := o.writers[filename]
+	if !ok {
+		w = &LazyOverwriter{Filename: filename, DoBackup: o.DoBackup, OptionalLogger: o.OptionalLogger}
+		o.writers[filename] = w
+	}
+
+	return w
+}
+
+// WriteAll writes each of contents to the file it is keyed by, in order of
+// filename. Nothing is written if o is nil.
+func (o *Overwriters) WriteAll(contents map[string][]byte) error {
+	if o == nil {
+		return nil
+	}
+
+	for _, filename := range slices.Sorted(maps.Keys(contents)) {
+		o.logger().Info("updating file", "filename", filename)
+		if _, err := o.For(filename).Write(contents[filename]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Overwriters) logger() *slog.Logger {
+	if o.OptionalLogger == nil {
+		return logger.DiscardingLogger()
+	}
+
+	return o.OptionalLogger
+}
@@ -31,6 +31,34 @@ var _ = Describe("File", func() {
 			os.RemoveAll(workingDirectory)
 		})
 
+		When("the file does not exist", func() {
+			BeforeEach(func() {
+				Expect(os.Remove(targetFileName)).To(Succeed())
+			})
+
+			It("should fail to write", func() {
+				lw := &LazyOverwriter{Filename: targetFileName}
+				_, err := lw.Write([]byte("newdata"))
+				Expect(err).To(MatchError(os.ErrNotExist))
+			})
+
+			It("should create the file without a backup if requested", func() {
+				lw := &LazyOverwriter{Filename: targetFileName, DoBackup: true, CreateIfMissing: true}
+				_, err := lw.Write([]byte("newdata"))
+				Expect(err).ToNot(HaveOccurred())
+				_, err = lw.Write([]byte("newerdata"))
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := os.ReadFile(targetFileName)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(content)).To(Equal("newerdata"))
+
+				entries, err := os.ReadDir(workingDirectory)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+			})
+		})
+
 		When("the file contains data", func() {
 			var originalFileContent string
 
@@ -91,8 +119,64 @@ var _ = Describe("File", func() {
 						Expect(err).ToNot(HaveOccurred())
 						Expect(string(content)).To(Equal(originalFileContent))
 					})
+
+					It("should keep the original content in the backup across multiple writes", func() {
+						newNameFn := func(s string) string {
+							return fmt.Sprintf("GENBACKUP-%s", s)
+						}
+
+						lw := &LazyOverwriter{
+							Filename:            targetFileName,
+							DoBackup:            true,
+							BackupFilenameGenFn: newNameFn,
+						}
+
+						_, err := lw.Write([]byte("newdata"))
+						Expect(err).ToNot(HaveOccurred())
+						_, err = lw.Write([]byte("newerdata"))
+						Expect(err).ToNot(HaveOccurred())
+
+						content, err := os.ReadFile(targetFileName)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(content)).To(Equal("newerdata"))
+
+						content, err = os.ReadFile(filepath.Join(workingDirectory, newNameFn(filepath.Base(targetFileName))))
+						Expect(err).ToNot(HaveOccurred())
+						Expect(string(content)).To(Equal(originalFileContent))
+					})
 				})
 			})
 		})
 	})
+
+	When("using Overwriters", func() {
+		It("should write each file, backing each up once", func() {
+			dir := GinkgoT().TempDir()
+			first := filepath.Join(dir, "first.html")
+			second := filepath.Join(dir, "second.html")
+			Expect(os.WriteFile(first, []byte("first"), 0o644)).To(Succeed())
+			Expect(os.WriteFile(second, []byte("second"), 0o644)).To(Succeed())
+
+			o := &Overwriters{DoBackup: true}
+			Expect(o.WriteAll(map[string][]byte{first: []byte("new first"), second: []byte("new second")})).To(Succeed())
+			Expect(o.WriteAll(map[string][]byte{first: []byte("newer first")})).To(Succeed())
+			Expect(o.For(first)).To(BeIdenticalTo(o.For(first)))
+
+			content, err := os.ReadFile(first)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("newer first"))
+
+			backups, err := filepath.Glob(filepath.Join(dir, "*.first.html"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(backups).To(HaveLen(1))
+			content, err = os.ReadFile(backups[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("first"))
+		})
+
+		It("should write nothing if nil", func() {
+			var o *Overwriters
+			Expect(o.WriteAll(map[string][]byte{"missing/file": []byte("content")})).To(Succeed())
+		})
+	})
 })
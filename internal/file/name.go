@@ -0,0 +1,52 @@
+package file
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SafeName returns a lowercase representation of s that is safe to use as a
+// filename, with runs of characters other than letters and digits replaced by
+// a single hyphen. The same input always produces the same output. An empty
+// string is returned if s contains no letters or digits.
+func SafeName(s string) string {
+	var b strings.Builder
+	pendingSeparator := false
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingSeparator && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			b.WriteRune(r)
+			pendingSeparator = false
+			continue
+		}
+
+		pendingSeparator = true
+	}
+
+	return b.String()
+}
+
+// DeclarationExtension is appended to declaration filenames. It matches the
+// patterns recommended for editor integration.
+const DeclarationExtension = ".product.yaml"
+
+// DeclarationFilenames maps each key of namesByID to a declaration filename
+// derived from its name and ID, e.g. "my-product-<id>.product.yaml", or from
+// its ID alone if its name has no letters or digits. Every filename is unique,
+// and remains the same across runs for as long as the name does, whatever
+// other names are mapped alongside it.
+func DeclarationFilenames(namesByID map[string]string) map[string]string {
+	filenames := make(map[string]string, len(namesByID))
+	for id, name := range namesByID {
+		base := SafeName(id)
+		if safe := SafeName(name); safe != "" {
+			base = safe + "-" + base
+		}
+
+		filenames[id] = base + DeclarationExtension
+	}
+
+	return filenames
+}
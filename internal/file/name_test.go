@@ -0,0 +1,53 @@
+package file
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Name", func() {
+	DescribeTable("when producing safe filenames",
+		func(in, expected string) {
+			Expect(SafeName(in)).To(Equal(expected))
+		},
+		Entry("a simple name", "product", "product"),
+		Entry("a name with spaces and capitals", "My New Product", "my-new-product"),
+		Entry("a name with runs of special characters", "Backup & Recovery / Tools", "backup-recovery-tools"),
+		Entry("a name with leading and trailing special characters", "  (Beta) Product!  ", "beta-product"),
+		Entry("a name with path separators", "../../etc/passwd", "etc-passwd"),
+		Entry("a name with non-ascii characters", "Prodüct", "prod-ct"),
+		Entry("a name without letters or digits", "!!!", ""),
+	)
+
+	When("deriving declaration filenames", func() {
+		var namesByID map[string]string
+
+		BeforeEach(func() {
+			namesByID = map[string]string{
+				"aaa111": "My Product",
+				"bbb222": "Duplicate",
+				"ccc333": "duplicate!",
+				"ddd444": "",
+			}
+		})
+
+		It("should derive filenames from names and IDs", func() {
+			Expect(DeclarationFilenames(namesByID)).To(HaveKeyWithValue("aaa111", "my-product-aaa111.product.yaml"))
+		})
+
+		It("should keep filenames when names start to collide", func() {
+			before := DeclarationFilenames(map[string]string{"bbb222": "Duplicate"})
+			Expect(DeclarationFilenames(namesByID)).To(HaveKeyWithValue("bbb222", before["bbb222"]))
+		})
+
+		It("should derive unique filenames when names collide", func() {
+			filenames := DeclarationFilenames(namesByID)
+			Expect(filenames).To(HaveKeyWithValue("bbb222", "duplicate-bbb222.product.yaml"))
+			Expect(filenames).To(HaveKeyWithValue("ccc333", "duplicate-ccc333.product.yaml"))
+		})
+
+		It("should fall back to IDs when names are empty", func() {
+			Expect(DeclarationFilenames(namesByID)).To(HaveKeyWithValue("ddd444", "ddd444.product.yaml"))
+		})
+	})
+})
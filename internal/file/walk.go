@@ -0,0 +1,116 @@
+package file
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// backupFilenamePattern matches filenames produced by the default
+// BackupNameGenerator.
+var backupFilenamePattern = regexp.MustCompile(`^[0-9]{10,}\.`)
+
+// IsBackup returns true if path looks like a backup created by a
+// LazyOverwriter using the default BackupNameGenerator, and the file it is a
+// backup of exists alongside it.
+func IsBackup(path string) bool {
+	base := filepath.Base(path)
+	prefix := backupFilenamePattern.FindString(base)
+	if prefix == "" {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(path), strings.TrimPrefix(base, prefix)))
+	return err == nil
+}
+
+// overlaySuffix precedes the extension of overlay files.
+const overlaySuffix = ".overlay"
+
+// OverlayFilename returns the name of the overlay called name for the
+// declaration file at path. For example, the "stage" overlay of
+// my.product.yaml is my.product.stage.overlay.yaml, alongside it.
+func OverlayFilename(path, name string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + name + overlaySuffix + ext
+}
+
+// IsOverlay returns true if path looks like an overlay named by
+// OverlayFilename, and the declaration file it overlays exists alongside it.
+func IsOverlay(path string) bool {
+	ext := filepath.Ext(path)
+	stem, found := strings.CutSuffix(strings.TrimSuffix(path, ext), overlaySuffix)
+	if !found {
+		return false
+	}
+
+	dot := strings.LastIndex(stem, ".")
+	if dot < len(filepath.Dir(path)) {
+		return false
+	}
+
+	_, err := os.Stat(stem[:dot] + ext)
+	return err == nil
+}
+
+// DeclarationFiles returns the declaration files found at path. If path is a
+// file, it is returned as-is. If path is a directory, the YAML files within it
+// are returned in lexical order, descending into subdirectories if recursive
+// is set. Hidden files and directories, backups created on overwrite, and
+// overlays are skipped.
+func DeclarationFiles(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := []string{}
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p == path {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() {
+			if !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isYAMLFile(p) || IsBackup(p) || IsOverlay(p) {
+			return nil
+		}
+
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(files)
+	return files, nil
+}
+
+func isYAMLFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,95 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Walk", func() {
+	When("finding declaration files", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "productctl-walk-test-*")
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, name := range []string{
+				"b.product.yaml",
+				"a.product.yml",
+				"notes.txt",
+				".hidden.yaml",
+				"1700000000.a.product.yml",
+				"1700000000.orphan.product.yaml",
+				"b.product.stage.overlay.yaml",
+				"orphan.stage.overlay.yaml",
+				filepath.Join("nested", "c.product.yaml"),
+				filepath.Join(".git", "d.yaml"),
+			} {
+				path := filepath.Join(dir, name)
+				Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+				Expect(os.WriteFile(path, []byte("kind: ProductListing\n"), 0o644)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should return a file as-is", func() {
+			path := filepath.Join(dir, "notes.txt")
+			files, err := DeclarationFiles(path, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal([]string{path}))
+		})
+
+		It("should return only the top-level YAML files of a directory, in order", func() {
+			files, err := DeclarationFiles(dir, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal([]string{
+				filepath.Join(dir, "1700000000.orphan.product.yaml"),
+				filepath.Join(dir, "a.product.yml"),
+				filepath.Join(dir, "b.product.yaml"),
+				filepath.Join(dir, "orphan.stage.overlay.yaml"),
+			}))
+		})
+
+		It("should descend into visible subdirectories when recursive", func() {
+			files, err := DeclarationFiles(dir, true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(files).To(Equal([]string{
+				filepath.Join(dir, "1700000000.orphan.product.yaml"),
+				filepath.Join(dir, "a.product.yml"),
+				filepath.Join(dir, "b.product.yaml"),
+				filepath.Join(dir, "nested", "c.product.yaml"),
+				filepath.Join(dir, "orphan.stage.overlay.yaml"),
+			}))
+		})
+
+		It("should fail if the path does not exist", func() {
+			_, err := DeclarationFiles(filepath.Join(dir, "missing"), false)
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	When("naming overlays", func() {
+		It("should name overlays after the declaration file", func() {
+			Expect(OverlayFilename(filepath.Join("listings", "my.product.yaml"), "stage")).To(Equal(filepath.Join("listings", "my.product.stage.overlay.yaml")))
+			Expect(OverlayFilename("my.product.yml", "prod")).To(Equal("my.product.prod.overlay.yml"))
+		})
+
+		It("should only identify overlays of existing declaration files", func() {
+			dir := GinkgoT().TempDir()
+			declaration := filepath.Join(dir, "my.product.yaml")
+			Expect(os.WriteFile(declaration, []byte("kind: ProductListing\n"), 0o644)).To(Succeed())
+
+			Expect(IsOverlay(OverlayFilename(declaration, "stage"))).To(BeTrue())
+			Expect(IsOverlay(declaration)).To(BeFalse())
+			Expect(IsOverlay(filepath.Join(dir, "other.stage.overlay.yaml"))).To(BeFalse())
+			Expect(IsOverlay(filepath.Join(dir, "overlay.yaml"))).To(BeFalse())
+		})
+	})
+})
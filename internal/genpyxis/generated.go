@@ -10,6 +10,78 @@ import (
 	"github.com/Khan/genqlient/graphql"
 )
 
+// APIKeySupportedFields includes the GraphQL fields of ApiKey requested by the fragment APIKeySupportedFields.
+// The GraphQL type's documentation follows.
+//
+// API key stored in Loki.
+type APIKeySupportedFields struct {
+	Id          int    `json:"id"`
+	Description string `json:"description"`
+	// Red Hat Org ID / account_id from Red Hat SSO. Also corresponds to company_org_id in Red Hat Connect.
+	Org_id     int        `json:"org_id"`
+	Created    *time.Time `json:"created"`
+	Last_used  *time.Time `json:"last_used"`
+	Created_by string     `json:"created_by"`
+	// Red Hat username from Red Hat SSO of user who requested the API key creation.
+	Created_on_behalf_of string `json:"created_on_behalf_of"`
+}
+
+// GetId returns APIKeySupportedFields.Id, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetId() int { return v.Id }
+
+// GetDescription returns APIKeySupportedFields.Description, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetDescription() string { return v.Description }
+
+// GetOrg_id returns APIKeySupportedFields.Org_id, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetOrg_id() int { return v.Org_id }
+
+// GetCreated returns APIKeySupportedFields.Created, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetCreated() *time.Time { return v.Created }
+
+// GetLast_used returns APIKeySupportedFields.Last_used, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetLast_used() *time.Time { return v.Last_used }
+
+// GetCreated_by returns APIKeySupportedFields.Created_by, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetCreated_by() string { return v.Created_by }
+
+// GetCreated_on_behalf_of returns APIKeySupportedFields.Created_on_behalf_of, and is useful for accessing the field via an interface.
+func (v *APIKeySupportedFields) GetCreated_on_behalf_of() string { return v.Created_on_behalf_of }
+
+// APIKeysGet_keyApiKeyListResponse includes the requested fields of the GraphQL type ApiKeyListResponse.
+type APIKeysGet_keyApiKeyListResponse struct {
+	Data  []*APIKeySupportedFields               `json:"data"`
+	Error *APIKeysGet_keyApiKeyListResponseError `json:"error"`
+}
+
+// GetData returns APIKeysGet_keyApiKeyListResponse.Data, and is useful for accessing the field via an interface.
+func (v *APIKeysGet_keyApiKeyListResponse) GetData() []*APIKeySupportedFields { return v.Data }
+
+// GetError returns APIKeysGet_keyApiKeyListResponse.Error, and is useful for accessing the field via an interface.
+func (v *APIKeysGet_keyApiKeyListResponse) GetError() *APIKeysGet_keyApiKeyListResponseError {
+	return v.Error
+}
+
+// APIKeysGet_keyApiKeyListResponseError includes the requested fields of the GraphQL type ResponseError.
+type APIKeysGet_keyApiKeyListResponseError struct {
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// GetStatus returns APIKeysGet_keyApiKeyListResponseError.Status, and is useful for accessing the field via an interface.
+func (v *APIKeysGet_keyApiKeyListResponseError) GetStatus() int { return v.Status }
+
+// GetDetail returns APIKeysGet_keyApiKeyListResponseError.Detail, and is useful for accessing the field via an interface.
+func (v *APIKeysGet_keyApiKeyListResponseError) GetDetail() string { return v.Detail }
+
+// APIKeysResponse is returned by APIKeys on success.
+type APIKeysResponse struct {
+	// Get a list of API keys associated with the given ORG ID.
+	Get_key *APIKeysGet_keyApiKeyListResponse `json:"get_key"`
+}
+
+// GetGet_key returns APIKeysResponse.Get_key, and is useful for accessing the field via an interface.
+func (v *APIKeysResponse) GetGet_key() *APIKeysGet_keyApiKeyListResponse { return v.Get_key }
+
 // ApplyComponentResponse is returned by ApplyComponent on success.
 type ApplyComponentResponse struct {
 	// Partially update a certification project.
@@ -593,6 +665,49 @@ func (v *CertificationProjectInput) GetCreated_on_behalf_of() string { return v.
 // GetUpdated_on_behalf_of returns CertificationProjectInput.Updated_on_behalf_of, and is useful for accessing the field via an interface.
 func (v *CertificationProjectInput) GetUpdated_on_behalf_of() string { return v.Updated_on_behalf_of }
 
+// ComponentByIDGet_certification_projectCertificationProjectResponse includes the requested fields of the GraphQL type CertificationProjectResponse.
+type ComponentByIDGet_certification_projectCertificationProjectResponse struct {
+	Data  *ComponentSupportedFields                                                `json:"data"`
+	Error *ComponentByIDGet_certification_projectCertificationProjectResponseError `json:"error"`
+}
+
+// GetData returns ComponentByIDGet_certification_projectCertificationProjectResponse.Data, and is useful for accessing the field via an interface.
+func (v *ComponentByIDGet_certification_projectCertificationProjectResponse) GetData() *ComponentSupportedFields {
+	return v.Data
+}
+
+// GetError returns ComponentByIDGet_certification_projectCertificationProjectResponse.Error, and is useful for accessing the field via an interface.
+func (v *ComponentByIDGet_certification_projectCertificationProjectResponse) GetError() *ComponentByIDGet_certification_projectCertificationProjectResponseError {
+	return v.Error
+}
+
+// ComponentByIDGet_certification_projectCertificationProjectResponseError includes the requested fields of the GraphQL type ResponseError.
+type ComponentByIDGet_certification_projectCertificationProjectResponseError struct {
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// GetStatus returns ComponentByIDGet_certification_projectCertificationProjectResponseError.Status, and is useful for accessing the field via an interface.
+func (v *ComponentByIDGet_certification_projectCertificationProjectResponseError) GetStatus() int {
+	return v.Status
+}
+
+// GetDetail returns ComponentByIDGet_certification_projectCertificationProjectResponseError.Detail, and is useful for accessing the field via an interface.
+func (v *ComponentByIDGet_certification_projectCertificationProjectResponseError) GetDetail() string {
+	return v.Detail
+}
+
+// ComponentByIDResponse is returned by ComponentByID on success.
+type ComponentByIDResponse struct {
+	// Get certification project using its ID.
+	Get_certification_project *ComponentByIDGet_certification_projectCertificationProjectResponse `json:"get_certification_project"`
+}
+
+// GetGet_certification_project returns ComponentByIDResponse.Get_certification_project, and is useful for accessing the field via an interface.
+func (v *ComponentByIDResponse) GetGet_certification_project() *ComponentByIDGet_certification_projectCertificationProjectResponse {
+	return v.Get_certification_project
+}
+
 // ComponentSupportedFields includes the GraphQL fields of CertificationProject requested by the fragment ComponentSupportedFields.
 // The GraphQL type's documentation follows.
 //
@@ -610,6 +725,8 @@ type ComponentSupportedFields struct {
 	Project_status string `json:"project_status"`
 	// Certification Status.
 	Certification_status string `json:"certification_status"`
+	// Unique identifier for the product listing.
+	Product_listings []string `json:"product_listings"`
 	// The date when the entry was created. Value is created automatically on creation.
 	Creation_date *time.Time `json:"creation_date"`
 	// The date when the entry was last updated.
@@ -618,7 +735,12 @@ type ComponentSupportedFields struct {
 	Helm_chart *ComponentSupportedFieldsHelm_chartCertProjectHelmChart `json:"helm_chart"`
 	Container  *ComponentSupportedFieldsContainerCertProjectContainer  `json:"container"`
 	// Contacts for certification project.
-	Contacts []*ComponentSupportedFieldsContactsCertProjectContacts `json:"contacts"`
+	Contacts      []*ComponentSupportedFieldsContactsCertProjectContacts        `json:"contacts"`
+	Non_container *ComponentSupportedFieldsNon_containerCertProjectNonContainer `json:"non_container"`
+	// Configuration specific to OpenStack projects.
+	Openstack          *ComponentSupportedFieldsOpenstackCertProjectOpenStack                  `json:"openstack"`
+	Redhat             *ComponentSupportedFieldsRedhatCertProjectRedhat                        `json:"redhat"`
+	Self_certification *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification `json:"self_certification"`
 }
 
 // GetId returns ComponentSupportedFields.Id, and is useful for accessing the field via an interface.
@@ -639,6 +761,9 @@ func (v *ComponentSupportedFields) GetProject_status() string { return v.Project
 // GetCertification_status returns ComponentSupportedFields.Certification_status, and is useful for accessing the field via an interface.
 func (v *ComponentSupportedFields) GetCertification_status() string { return v.Certification_status }
 
+// GetProduct_listings returns ComponentSupportedFields.Product_listings, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFields) GetProduct_listings() []string { return v.Product_listings }
+
 // GetCreation_date returns ComponentSupportedFields.Creation_date, and is useful for accessing the field via an interface.
 func (v *ComponentSupportedFields) GetCreation_date() *time.Time { return v.Creation_date }
 
@@ -660,6 +785,26 @@ func (v *ComponentSupportedFields) GetContacts() []*ComponentSupportedFieldsCont
 	return v.Contacts
 }
 
+// GetNon_container returns ComponentSupportedFields.Non_container, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFields) GetNon_container() *ComponentSupportedFieldsNon_containerCertProjectNonContainer {
+	return v.Non_container
+}
+
+// GetOpenstack returns ComponentSupportedFields.Openstack, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFields) GetOpenstack() *ComponentSupportedFieldsOpenstackCertProjectOpenStack {
+	return v.Openstack
+}
+
+// GetRedhat returns ComponentSupportedFields.Redhat, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFields) GetRedhat() *ComponentSupportedFieldsRedhatCertProjectRedhat {
+	return v.Redhat
+}
+
+// GetSelf_certification returns ComponentSupportedFields.Self_certification, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFields) GetSelf_certification() *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification {
+	return v.Self_certification
+}
+
 // ComponentSupportedFieldsContactsCertProjectContacts includes the requested fields of the GraphQL type CertProjectContacts.
 // The GraphQL type's documentation follows.
 //
@@ -798,6 +943,19 @@ type ComponentSupportedFieldsHelm_chartCertProjectHelmChart struct {
 	Distribution_method string `json:"distribution_method"`
 	// The application categories (types).
 	Application_categories []string `json:"application_categories"`
+	// Instructions for users to access an externally distributed Helm Chart.
+	Distribution_instructions string `json:"distribution_instructions"`
+	// The namespace is often the organization or user name that owns the chart.
+	// This value is only editable when the project is not published.
+	//
+	// The namespace is required for published projects.
+	Namespace string `json:"namespace"`
+	// OCP versions for this Helm Chart.
+	Ocp_versions []string `json:"ocp_versions"`
+	// Base64 encoded PGP public key. Used to sign result submissions.
+	Public_pgp_key string `json:"public_pgp_key"`
+	// URL to the user submitted github pull request for this project.
+	Github_pull_request string `json:"github_pull_request"`
 }
 
 // GetChart_name returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Chart_name, and is useful for accessing the field via an interface.
@@ -835,6 +993,145 @@ func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetApplication_
 	return v.Application_categories
 }
 
+// GetDistribution_instructions returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Distribution_instructions, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetDistribution_instructions() string {
+	return v.Distribution_instructions
+}
+
+// GetNamespace returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Namespace, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetNamespace() string {
+	return v.Namespace
+}
+
+// GetOcp_versions returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Ocp_versions, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetOcp_versions() []string {
+	return v.Ocp_versions
+}
+
+// GetPublic_pgp_key returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Public_pgp_key, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetPublic_pgp_key() string {
+	return v.Public_pgp_key
+}
+
+// GetGithub_pull_request returns ComponentSupportedFieldsHelm_chartCertProjectHelmChart.Github_pull_request, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsHelm_chartCertProjectHelmChart) GetGithub_pull_request() string {
+	return v.Github_pull_request
+}
+
+// ComponentSupportedFieldsNon_containerCertProjectNonContainer includes the requested fields of the GraphQL type CertProjectNonContainer.
+// The GraphQL type's documentation follows.
+//
+// Non-container project info.
+type ComponentSupportedFieldsNon_containerCertProjectNonContainer struct {
+	Source_system_id string `json:"source_system_id"`
+}
+
+// GetSource_system_id returns ComponentSupportedFieldsNon_containerCertProjectNonContainer.Source_system_id, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsNon_containerCertProjectNonContainer) GetSource_system_id() string {
+	return v.Source_system_id
+}
+
+// ComponentSupportedFieldsOpenstackCertProjectOpenStack includes the requested fields of the GraphQL type CertProjectOpenStack.
+// The GraphQL type's documentation follows.
+//
+// OpenStack related information.
+type ComponentSupportedFieldsOpenstackCertProjectOpenStack struct {
+	// OpenStack service type for test suite selection
+	Service string `json:"service"`
+}
+
+// GetService returns ComponentSupportedFieldsOpenstackCertProjectOpenStack.Service, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsOpenstackCertProjectOpenStack) GetService() string { return v.Service }
+
+// ComponentSupportedFieldsRedhatCertProjectRedhat includes the requested fields of the GraphQL type CertProjectRedhat.
+// The GraphQL type's documentation follows.
+//
+// Red Hat projects related information.
+type ComponentSupportedFieldsRedhatCertProjectRedhat struct {
+	// Red Hat Product ID.
+	Product_id int `json:"product_id"`
+	// Red Hat product name.
+	Product_name string `json:"product_name"`
+	// Red Hat Product Version.
+	Product_version string `json:"product_version"`
+	// Red Hat Product Version.
+	Product_version_id int `json:"product_version_id"`
+}
+
+// GetProduct_id returns ComponentSupportedFieldsRedhatCertProjectRedhat.Product_id, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsRedhatCertProjectRedhat) GetProduct_id() int { return v.Product_id }
+
+// GetProduct_name returns ComponentSupportedFieldsRedhatCertProjectRedhat.Product_name, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsRedhatCertProjectRedhat) GetProduct_name() string {
+	return v.Product_name
+}
+
+// GetProduct_version returns ComponentSupportedFieldsRedhatCertProjectRedhat.Product_version, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsRedhatCertProjectRedhat) GetProduct_version() string {
+	return v.Product_version
+}
+
+// GetProduct_version_id returns ComponentSupportedFieldsRedhatCertProjectRedhat.Product_version_id, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsRedhatCertProjectRedhat) GetProduct_version_id() int {
+	return v.Product_version_id
+}
+
+// ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification includes the requested fields of the GraphQL type CertProjectSelfCertification.
+// The GraphQL type's documentation follows.
+//
+// Red Hat projects related information.
+type ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification struct {
+	// Application Profiler.
+	App_profiler bool `json:"app_profiler"`
+	// Application Runs on App Type.
+	App_runs_on_app_type bool `json:"app_runs_on_app_type"`
+	// Whether the Self Certification Evidence URL requires a customer login.
+	Auth_login bool `json:"auth_login"`
+	// Self Certification Evidence URL.
+	Certification_url string `json:"certification_url"`
+	// Can Commercially Support on App Type.
+	Comm_support_on_app_type bool `json:"comm_support_on_app_type"`
+	// Self Certification Requested.
+	Requested bool `json:"requested"`
+	// TsaNET Member.
+	Tsanet_member bool `json:"tsanet_member"`
+}
+
+// GetApp_profiler returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.App_profiler, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetApp_profiler() bool {
+	return v.App_profiler
+}
+
+// GetApp_runs_on_app_type returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.App_runs_on_app_type, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetApp_runs_on_app_type() bool {
+	return v.App_runs_on_app_type
+}
+
+// GetAuth_login returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.Auth_login, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetAuth_login() bool {
+	return v.Auth_login
+}
+
+// GetCertification_url returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.Certification_url, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetCertification_url() string {
+	return v.Certification_url
+}
+
+// GetComm_support_on_app_type returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.Comm_support_on_app_type, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetComm_support_on_app_type() bool {
+	return v.Comm_support_on_app_type
+}
+
+// GetRequested returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.Requested, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetRequested() bool {
+	return v.Requested
+}
+
+// GetTsanet_member returns ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification.Tsanet_member, and is useful for accessing the field via an interface.
+func (v *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification) GetTsanet_member() bool {
+	return v.Tsanet_member
+}
+
 // ComponentsForListingFind_product_listing_certification_projectsCertificationProjectPaginatedResponse includes the requested fields of the GraphQL type CertificationProjectPaginatedResponse.
 type ComponentsForListingFind_product_listing_certification_projectsCertificationProjectPaginatedResponse struct {
 	Data      []*ComponentSupportedFields                                                                                `json:"data"`
@@ -907,6 +1204,43 @@ func (v *ContactsItemsInput) GetEmail_address() string { return v.Email_address
 // GetType returns ContactsItemsInput.Type, and is useful for accessing the field via an interface.
 func (v *ContactsItemsInput) GetType() string { return v.Type }
 
+// DeleteAPIKeyDelete_api_keyApiKeyResponse includes the requested fields of the GraphQL type ApiKeyResponse.
+type DeleteAPIKeyDelete_api_keyApiKeyResponse struct {
+	Data  *APIKeySupportedFields                         `json:"data"`
+	Error *DeleteAPIKeyDelete_api_keyApiKeyResponseError `json:"error"`
+}
+
+// GetData returns DeleteAPIKeyDelete_api_keyApiKeyResponse.Data, and is useful for accessing the field via an interface.
+func (v *DeleteAPIKeyDelete_api_keyApiKeyResponse) GetData() *APIKeySupportedFields { return v.Data }
+
+// GetError returns DeleteAPIKeyDelete_api_keyApiKeyResponse.Error, and is useful for accessing the field via an interface.
+func (v *DeleteAPIKeyDelete_api_keyApiKeyResponse) GetError() *DeleteAPIKeyDelete_api_keyApiKeyResponseError {
+	return v.Error
+}
+
+// DeleteAPIKeyDelete_api_keyApiKeyResponseError includes the requested fields of the GraphQL type ResponseError.
+type DeleteAPIKeyDelete_api_keyApiKeyResponseError struct {
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// GetStatus returns DeleteAPIKeyDelete_api_keyApiKeyResponseError.Status, and is useful for accessing the field via an interface.
+func (v *DeleteAPIKeyDelete_api_keyApiKeyResponseError) GetStatus() int { return v.Status }
+
+// GetDetail returns DeleteAPIKeyDelete_api_keyApiKeyResponseError.Detail, and is useful for accessing the field via an interface.
+func (v *DeleteAPIKeyDelete_api_keyApiKeyResponseError) GetDetail() string { return v.Detail }
+
+// DeleteAPIKeyResponse is returned by DeleteAPIKey on success.
+type DeleteAPIKeyResponse struct {
+	// Delete API key.
+	Delete_api_key *DeleteAPIKeyDelete_api_keyApiKeyResponse `json:"delete_api_key"`
+}
+
+// GetDelete_api_key returns DeleteAPIKeyResponse.Delete_api_key, and is useful for accessing the field via an interface.
+func (v *DeleteAPIKeyResponse) GetDelete_api_key() *DeleteAPIKeyDelete_api_keyApiKeyResponse {
+	return v.Delete_api_key
+}
+
 // DeleteProductResponse is returned by DeleteProduct on success.
 type DeleteProductResponse struct {
 	// Update product listing.
@@ -1172,6 +1506,11 @@ func (v *MutateComponentCommonResponseDataCertificationProject) GetCertification
 	return v.ComponentSupportedFields.Certification_status
 }
 
+// GetProduct_listings returns MutateComponentCommonResponseDataCertificationProject.Product_listings, and is useful for accessing the field via an interface.
+func (v *MutateComponentCommonResponseDataCertificationProject) GetProduct_listings() []string {
+	return v.ComponentSupportedFields.Product_listings
+}
+
 // GetCreation_date returns MutateComponentCommonResponseDataCertificationProject.Creation_date, and is useful for accessing the field via an interface.
 func (v *MutateComponentCommonResponseDataCertificationProject) GetCreation_date() *time.Time {
 	return v.ComponentSupportedFields.Creation_date
@@ -1197,6 +1536,26 @@ func (v *MutateComponentCommonResponseDataCertificationProject) GetContacts() []
 	return v.ComponentSupportedFields.Contacts
 }
 
+// GetNon_container returns MutateComponentCommonResponseDataCertificationProject.Non_container, and is useful for accessing the field via an interface.
+func (v *MutateComponentCommonResponseDataCertificationProject) GetNon_container() *ComponentSupportedFieldsNon_containerCertProjectNonContainer {
+	return v.ComponentSupportedFields.Non_container
+}
+
+// GetOpenstack returns MutateComponentCommonResponseDataCertificationProject.Openstack, and is useful for accessing the field via an interface.
+func (v *MutateComponentCommonResponseDataCertificationProject) GetOpenstack() *ComponentSupportedFieldsOpenstackCertProjectOpenStack {
+	return v.ComponentSupportedFields.Openstack
+}
+
+// GetRedhat returns MutateComponentCommonResponseDataCertificationProject.Redhat, and is useful for accessing the field via an interface.
+func (v *MutateComponentCommonResponseDataCertificationProject) GetRedhat() *ComponentSupportedFieldsRedhatCertProjectRedhat {
+	return v.ComponentSupportedFields.Redhat
+}
+
+// GetSelf_certification returns MutateComponentCommonResponseDataCertificationProject.Self_certification, and is useful for accessing the field via an interface.
+func (v *MutateComponentCommonResponseDataCertificationProject) GetSelf_certification() *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification {
+	return v.ComponentSupportedFields.Self_certification
+}
+
 func (v *MutateComponentCommonResponseDataCertificationProject) UnmarshalJSON(b []byte) error {
 
 	if string(b) == "null" {
@@ -1235,6 +1594,8 @@ type __premarshalMutateComponentCommonResponseDataCertificationProject struct {
 
 	Certification_status string `json:"certification_status"`
 
+	Product_listings []string `json:"product_listings"`
+
 	Creation_date *time.Time `json:"creation_date"`
 
 	Last_update_date *time.Time `json:"last_update_date"`
@@ -1244,6 +1605,14 @@ type __premarshalMutateComponentCommonResponseDataCertificationProject struct {
 	Container *ComponentSupportedFieldsContainerCertProjectContainer `json:"container"`
 
 	Contacts []*ComponentSupportedFieldsContactsCertProjectContacts `json:"contacts"`
+
+	Non_container *ComponentSupportedFieldsNon_containerCertProjectNonContainer `json:"non_container"`
+
+	Openstack *ComponentSupportedFieldsOpenstackCertProjectOpenStack `json:"openstack"`
+
+	Redhat *ComponentSupportedFieldsRedhatCertProjectRedhat `json:"redhat"`
+
+	Self_certification *ComponentSupportedFieldsSelf_certificationCertProjectSelfCertification `json:"self_certification"`
 }
 
 func (v *MutateComponentCommonResponseDataCertificationProject) MarshalJSON() ([]byte, error) {
@@ -1263,11 +1632,16 @@ func (v *MutateComponentCommonResponseDataCertificationProject) __premarshalJSON
 	retval.Type = v.ComponentSupportedFields.Type
 	retval.Project_status = v.ComponentSupportedFields.Project_status
 	retval.Certification_status = v.ComponentSupportedFields.Certification_status
+	retval.Product_listings = v.ComponentSupportedFields.Product_listings
 	retval.Creation_date = v.ComponentSupportedFields.Creation_date
 	retval.Last_update_date = v.ComponentSupportedFields.Last_update_date
 	retval.Helm_chart = v.ComponentSupportedFields.Helm_chart
 	retval.Container = v.ComponentSupportedFields.Container
 	retval.Contacts = v.ComponentSupportedFields.Contacts
+	retval.Non_container = v.ComponentSupportedFields.Non_container
+	retval.Openstack = v.ComponentSupportedFields.Openstack
+	retval.Redhat = v.ComponentSupportedFields.Redhat
+	retval.Self_certification = v.ComponentSupportedFields.Self_certification
 	return &retval, nil
 }
 
@@ -1632,6 +2006,63 @@ func (v *MyProjectsResponse) GetFind_vendor_certification_projects_by_org_id() *
 	return v.Find_vendor_certification_projects_by_org_id
 }
 
+// NewAPIKeyCreate_api_keyApiKeyRespResponse includes the requested fields of the GraphQL type ApiKeyRespResponse.
+type NewAPIKeyCreate_api_keyApiKeyRespResponse struct {
+	Data  *NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp `json:"data"`
+	Error *NewAPIKeyCreate_api_keyApiKeyRespResponseError          `json:"error"`
+}
+
+// GetData returns NewAPIKeyCreate_api_keyApiKeyRespResponse.Data, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponse) GetData() *NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp {
+	return v.Data
+}
+
+// GetError returns NewAPIKeyCreate_api_keyApiKeyRespResponse.Error, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponse) GetError() *NewAPIKeyCreate_api_keyApiKeyRespResponseError {
+	return v.Error
+}
+
+// NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp includes the requested fields of the GraphQL type ApiKeyResp.
+type NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp struct {
+	// Generated API key.
+	Api_key string `json:"api_key"`
+	// API key data.
+	Key_data *APIKeySupportedFields `json:"key_data"`
+}
+
+// GetApi_key returns NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp.Api_key, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp) GetApi_key() string {
+	return v.Api_key
+}
+
+// GetKey_data returns NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp.Key_data, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponseDataApiKeyResp) GetKey_data() *APIKeySupportedFields {
+	return v.Key_data
+}
+
+// NewAPIKeyCreate_api_keyApiKeyRespResponseError includes the requested fields of the GraphQL type ResponseError.
+type NewAPIKeyCreate_api_keyApiKeyRespResponseError struct {
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// GetStatus returns NewAPIKeyCreate_api_keyApiKeyRespResponseError.Status, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponseError) GetStatus() int { return v.Status }
+
+// GetDetail returns NewAPIKeyCreate_api_keyApiKeyRespResponseError.Detail, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyCreate_api_keyApiKeyRespResponseError) GetDetail() string { return v.Detail }
+
+// NewAPIKeyResponse is returned by NewAPIKey on success.
+type NewAPIKeyResponse struct {
+	// Create an API key.
+	Create_api_key *NewAPIKeyCreate_api_keyApiKeyRespResponse `json:"create_api_key"`
+}
+
+// GetCreate_api_key returns NewAPIKeyResponse.Create_api_key, and is useful for accessing the field via an interface.
+func (v *NewAPIKeyResponse) GetCreate_api_key() *NewAPIKeyCreate_api_keyApiKeyRespResponse {
+	return v.Create_api_key
+}
+
 // NewComponentResponse is returned by NewComponent on success.
 type NewComponentResponse struct {
 	// Create a certification project.
@@ -2431,6 +2862,14 @@ func (v *ValidationItemsInput) GetRedhat_products() []*RedHatProductItemsInput {
 	return v.Redhat_products
 }
 
+// __APIKeysInput is used internally by genqlient
+type __APIKeysInput struct {
+	OrgID int `json:"orgID"`
+}
+
+// GetOrgID returns __APIKeysInput.OrgID, and is useful for accessing the field via an interface.
+func (v *__APIKeysInput) GetOrgID() int { return v.OrgID }
+
 // __ApplyComponentInput is used internally by genqlient
 type __ApplyComponentInput struct {
 	ComponentID string                     `json:"componentID"`
@@ -2463,6 +2902,14 @@ type __ArchiveComponentInput struct {
 // GetId returns __ArchiveComponentInput.Id, and is useful for accessing the field via an interface.
 func (v *__ArchiveComponentInput) GetId() string { return v.Id }
 
+// __ComponentByIDInput is used internally by genqlient
+type __ComponentByIDInput struct {
+	ComponentID string `json:"componentID"`
+}
+
+// GetComponentID returns __ComponentByIDInput.ComponentID, and is useful for accessing the field via an interface.
+func (v *__ComponentByIDInput) GetComponentID() string { return v.ComponentID }
+
 // __ComponentsForListingInput is used internally by genqlient
 type __ComponentsForListingInput struct {
 	ProductID string `json:"productID"`
@@ -2479,6 +2926,18 @@ func (v *__ComponentsForListingInput) GetPage() int { return v.Page }
 // GetPageSize returns __ComponentsForListingInput.PageSize, and is useful for accessing the field via an interface.
 func (v *__ComponentsForListingInput) GetPageSize() int { return v.PageSize }
 
+// __DeleteAPIKeyInput is used internally by genqlient
+type __DeleteAPIKeyInput struct {
+	OrgID int `json:"orgID"`
+	KeyID int `json:"keyID"`
+}
+
+// GetOrgID returns __DeleteAPIKeyInput.OrgID, and is useful for accessing the field via an interface.
+func (v *__DeleteAPIKeyInput) GetOrgID() int { return v.OrgID }
+
+// GetKeyID returns __DeleteAPIKeyInput.KeyID, and is useful for accessing the field via an interface.
+func (v *__DeleteAPIKeyInput) GetKeyID() int { return v.KeyID }
+
 // __DeleteProductInput is used internally by genqlient
 type __DeleteProductInput struct {
 	Id string `json:"id"`
@@ -2519,6 +2978,18 @@ func (v *__MyProjectsInput) GetPage() int { return v.Page }
 // GetPageSize returns __MyProjectsInput.PageSize, and is useful for accessing the field via an interface.
 func (v *__MyProjectsInput) GetPageSize() int { return v.PageSize }
 
+// __NewAPIKeyInput is used internally by genqlient
+type __NewAPIKeyInput struct {
+	OrgID       int    `json:"orgID"`
+	Description string `json:"description"`
+}
+
+// GetOrgID returns __NewAPIKeyInput.OrgID, and is useful for accessing the field via an interface.
+func (v *__NewAPIKeyInput) GetOrgID() int { return v.OrgID }
+
+// GetDescription returns __NewAPIKeyInput.Description, and is useful for accessing the field via an interface.
+func (v *__NewAPIKeyInput) GetDescription() string { return v.Description }
+
 // __NewComponentInput is used internally by genqlient
 type __NewComponentInput struct {
 	New *CertificationProjectInput `json:"new,omitempty"`
@@ -2555,6 +3026,55 @@ func (v *__SetComponentsForProductInput) GetProductID() string { return v.Produc
 // GetComponentIDs returns __SetComponentsForProductInput.ComponentIDs, and is useful for accessing the field via an interface.
 func (v *__SetComponentsForProductInput) GetComponentIDs() []string { return v.ComponentIDs }
 
+// The query executed by APIKeys.
+const APIKeys_Operation = `
+query APIKeys ($orgID: Int) {
+	get_key(org_id: $orgID) {
+		data {
+			... APIKeySupportedFields
+		}
+		error {
+			status
+			detail
+		}
+	}
+}
+fragment APIKeySupportedFields on ApiKey {
+	id
+	description
+	org_id
+	created
+	last_used
+	created_by
+	created_on_behalf_of
+}
+`
+
+func APIKeys(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	orgID int,
+) (data_ *APIKeysResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "APIKeys",
+		Query:  APIKeys_Operation,
+		Variables: &__APIKeysInput{
+			OrgID: orgID,
+		},
+	}
+
+	data_ = &APIKeysResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The mutation executed by ApplyComponent.
 const ApplyComponent_Operation = `
 mutation ApplyComponent ($componentID: ObjectIDFilterScalar, $updated: CertificationProjectInput) {
@@ -2578,6 +3098,7 @@ fragment ComponentSupportedFields on CertificationProject {
 	type
 	project_status
 	certification_status
+	product_listings
 	creation_date
 	last_update_date
 	helm_chart {
@@ -2588,6 +3109,11 @@ fragment ComponentSupportedFields on CertificationProject {
 		github_usernames
 		distribution_method
 		application_categories
+		distribution_instructions
+		namespace
+		ocp_versions
+		public_pgp_key
+		github_pull_request
 	}
 	container {
 		isv_pid
@@ -2607,6 +3133,27 @@ fragment ComponentSupportedFields on CertificationProject {
 		email_address
 		type
 	}
+	non_container {
+		source_system_id
+	}
+	openstack {
+		service
+	}
+	redhat {
+		product_id
+		product_name
+		product_version
+		product_version_id
+	}
+	self_certification {
+		app_profiler
+		app_runs_on_app_type
+		auth_login
+		certification_url
+		comm_support_on_app_type
+		requested
+		tsanet_member
+	}
 }
 `
 
@@ -2775,6 +3322,110 @@ func ArchiveComponent(
 	return data_, err_
 }
 
+// The query executed by ComponentByID.
+const ComponentByID_Operation = `
+query ComponentByID ($componentID: ObjectIDFilterScalar) {
+	get_certification_project(id: $componentID) {
+		data {
+			... ComponentSupportedFields
+		}
+		error {
+			status
+			detail
+		}
+	}
+}
+fragment ComponentSupportedFields on CertificationProject {
+	_id
+	name
+	org_id
+	type
+	project_status
+	certification_status
+	product_listings
+	creation_date
+	last_update_date
+	helm_chart {
+		chart_name
+		repository
+		short_description
+		long_description
+		github_usernames
+		distribution_method
+		application_categories
+		distribution_instructions
+		namespace
+		ocp_versions
+		public_pgp_key
+		github_pull_request
+	}
+	container {
+		isv_pid
+		type
+		short_description
+		registry
+		repository
+		repository_name
+		repository_description
+		distribution_method
+		hosted_registry
+		os_content_type
+		application_categories
+		build_categories
+	}
+	contacts {
+		email_address
+		type
+	}
+	non_container {
+		source_system_id
+	}
+	openstack {
+		service
+	}
+	redhat {
+		product_id
+		product_name
+		product_version
+		product_version_id
+	}
+	self_certification {
+		app_profiler
+		app_runs_on_app_type
+		auth_login
+		certification_url
+		comm_support_on_app_type
+		requested
+		tsanet_member
+	}
+}
+`
+
+func ComponentByID(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	componentID string,
+) (data_ *ComponentByIDResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "ComponentByID",
+		Query:  ComponentByID_Operation,
+		Variables: &__ComponentByIDInput{
+			ComponentID: componentID,
+		},
+	}
+
+	data_ = &ComponentByIDResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The query executed by ComponentsForListing.
 const ComponentsForListing_Operation = `
 query ComponentsForListing ($productID: ObjectIDFilterScalar, $page: Int!, $pageSize: Int!) {
@@ -2798,6 +3449,7 @@ fragment ComponentSupportedFields on CertificationProject {
 	type
 	project_status
 	certification_status
+	product_listings
 	creation_date
 	last_update_date
 	helm_chart {
@@ -2808,6 +3460,11 @@ fragment ComponentSupportedFields on CertificationProject {
 		github_usernames
 		distribution_method
 		application_categories
+		distribution_instructions
+		namespace
+		ocp_versions
+		public_pgp_key
+		github_pull_request
 	}
 	container {
 		isv_pid
@@ -2827,6 +3484,27 @@ fragment ComponentSupportedFields on CertificationProject {
 		email_address
 		type
 	}
+	non_container {
+		source_system_id
+	}
+	openstack {
+		service
+	}
+	redhat {
+		product_id
+		product_name
+		product_version
+		product_version_id
+	}
+	self_certification {
+		app_profiler
+		app_runs_on_app_type
+		auth_login
+		certification_url
+		comm_support_on_app_type
+		requested
+		tsanet_member
+	}
 }
 `
 
@@ -2859,6 +3537,57 @@ func ComponentsForListing(
 	return data_, err_
 }
 
+// The mutation executed by DeleteAPIKey.
+const DeleteAPIKey_Operation = `
+mutation DeleteAPIKey ($orgID: Int, $keyID: Int) {
+	delete_api_key(org_id: $orgID, key_id: $keyID) {
+		data {
+			... APIKeySupportedFields
+		}
+		error {
+			status
+			detail
+		}
+	}
+}
+fragment APIKeySupportedFields on ApiKey {
+	id
+	description
+	org_id
+	created
+	last_used
+	created_by
+	created_on_behalf_of
+}
+`
+
+func DeleteAPIKey(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	orgID int,
+	keyID int,
+) (data_ *DeleteAPIKeyResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "DeleteAPIKey",
+		Query:  DeleteAPIKey_Operation,
+		Variables: &__DeleteAPIKeyInput{
+			OrgID: orgID,
+			KeyID: keyID,
+		},
+	}
+
+	data_ = &DeleteAPIKeyResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The mutation executed by DeleteProduct.
 const DeleteProduct_Operation = `
 mutation DeleteProduct ($id: ObjectIDFilterScalar) {
@@ -3022,6 +3751,7 @@ fragment ComponentSupportedFields on CertificationProject {
 	type
 	project_status
 	certification_status
+	product_listings
 	creation_date
 	last_update_date
 	helm_chart {
@@ -3032,6 +3762,11 @@ fragment ComponentSupportedFields on CertificationProject {
 		github_usernames
 		distribution_method
 		application_categories
+		distribution_instructions
+		namespace
+		ocp_versions
+		public_pgp_key
+		github_pull_request
 	}
 	container {
 		isv_pid
@@ -3051,6 +3786,27 @@ fragment ComponentSupportedFields on CertificationProject {
 		email_address
 		type
 	}
+	non_container {
+		source_system_id
+	}
+	openstack {
+		service
+	}
+	redhat {
+		product_id
+		product_name
+		product_version
+		product_version_id
+	}
+	self_certification {
+		app_profiler
+		app_runs_on_app_type
+		auth_login
+		certification_url
+		comm_support_on_app_type
+		requested
+		tsanet_member
+	}
 }
 `
 
@@ -3083,6 +3839,60 @@ func MyProjects(
 	return data_, err_
 }
 
+// The mutation executed by NewAPIKey.
+const NewAPIKey_Operation = `
+mutation NewAPIKey ($orgID: Int, $description: String) {
+	create_api_key(input: {org_id:$orgID,description:$description}) {
+		data {
+			api_key
+			key_data {
+				... APIKeySupportedFields
+			}
+		}
+		error {
+			status
+			detail
+		}
+	}
+}
+fragment APIKeySupportedFields on ApiKey {
+	id
+	description
+	org_id
+	created
+	last_used
+	created_by
+	created_on_behalf_of
+}
+`
+
+func NewAPIKey(
+	ctx_ context.Context,
+	client_ graphql.Client,
+	orgID int,
+	description string,
+) (data_ *NewAPIKeyResponse, err_ error) {
+	req_ := &graphql.Request{
+		OpName: "NewAPIKey",
+		Query:  NewAPIKey_Operation,
+		Variables: &__NewAPIKeyInput{
+			OrgID:       orgID,
+			Description: description,
+		},
+	}
+
+	data_ = &NewAPIKeyResponse{}
+	resp_ := &graphql.Response{Data: data_}
+
+	err_ = client_.MakeRequest(
+		ctx_,
+		req_,
+		resp_,
+	)
+
+	return data_, err_
+}
+
 // The mutation executed by NewComponent.
 const NewComponent_Operation = `
 mutation NewComponent ($new: CertificationProjectInput!) {
@@ -3106,6 +3916,7 @@ fragment ComponentSupportedFields on CertificationProject {
 	type
 	project_status
 	certification_status
+	product_listings
 	creation_date
 	last_update_date
 	helm_chart {
@@ -3116,6 +3927,11 @@ fragment ComponentSupportedFields on CertificationProject {
 		github_usernames
 		distribution_method
 		application_categories
+		distribution_instructions
+		namespace
+		ocp_versions
+		public_pgp_key
+		github_pull_request
 	}
 	container {
 		isv_pid
@@ -3135,6 +3951,27 @@ fragment ComponentSupportedFields on CertificationProject {
 		email_address
 		type
 	}
+	non_container {
+		source_system_id
+	}
+	openstack {
+		service
+	}
+	redhat {
+		product_id
+		product_name
+		product_version
+		product_version_id
+	}
+	self_certification {
+		app_profiler
+		app_runs_on_app_type
+		auth_login
+		certification_url
+		comm_support_on_app_type
+		requested
+		tsanet_member
+	}
 }
 `
 
@@ -0,0 +1,278 @@
+// Package lint checks product listing declarations against best practices
+// that go beyond the constraints of their schema.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var ErrUnknownRule = errors.New("unknown lint rule")
+
+// Level is the severity of a finding, named as in SARIF.
+type Level = string
+
+const (
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelNote    Level = "note"
+)
+
+// Finding is a single place where a declaration does not follow a rule.
+type Finding struct {
+	Rule  string
+	Level Level
+	// Path is the location of the offending value within the declaration,
+	// e.g. spec.linked_resources[2].url
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Level, f.Path, f.Message, f.Rule)
+}
+
+// Rule is a best practice that declarations are checked against.
+type Rule struct {
+	// ID identifies the rule, e.g. to disable it.
+	ID          string
+	Description string
+	Level       Level
+	check       func(d *resource.ProductListingDeclaration, report func(path, format string, args ...any))
+}
+
+// Rules returns every rule, ordered by ID.
+func Rules() []Rule {
+	return []Rule{
+		{
+			ID:          "container-image-location",
+			Description: "Container components should declare the registry and repository of their image.",
+			Level:       LevelWarning,
+			check:       checkContainerImageLocation,
+		},
+		{
+			ID:          "duplicate-component-names",
+			Description: "Components should have distinct names, which identify them within the product listing.",
+			Level:       LevelError,
+			check:       checkDuplicateComponentNames,
+		},
+		{
+			ID:          "duplicate-search-alias-keys",
+			Description: "Search aliases should have distinct keys.",
+			Level:       LevelWarning,
+			check:       checkDuplicateSearchAliasKeys,
+		},
+		{
+			ID:          "email-address",
+			Description: "Contact and support email addresses should be well formed.",
+			Level:       LevelError,
+			check:       checkEmailAddresses,
+		},
+		{
+			ID:          "https-url",
+			Description: "Linked resources, support and legal documents should be linked with HTTPS URLs.",
+			Level:       LevelWarning,
+			check:       checkHTTPSURLs,
+		},
+		{
+			ID:          "placeholder-text",
+			Description: "Placeholder text written by product create should be replaced.",
+			Level:       LevelError,
+			check:       checkPlaceholderText,
+		},
+	}
+}
+
+// Linter checks declarations against the rules that are enabled.
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a Linter checking every rule except those with the IDs in
+// disabled, which must all be known rules.
+func New(disabled []string) (*Linter, error) {
+	rules := Rules()
+	for _, id := range disabled {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.ID == id }) {
+			return nil, fmt.Errorf("%w %q", ErrUnknownRule, id)
+		}
+	}
+
+	rules = slices.DeleteFunc(rules, func(r Rule) bool { return slices.Contains(disabled, r.ID) })
+	return &Linter{rules: rules}, nil
+}
+
+// Rules returns the rules that are enabled.
+func (l *Linter) Rules() []Rule {
+	return l.rules
+}
+
+// Lint returns the findings of each enabled rule for d, ordered by rule.
+func (l *Linter) Lint(d *resource.ProductListingDeclaration) []Finding {
+	findings := []Finding{}
+	for _, rule := range l.rules {
+		rule.check(d, func(path, format string, args ...any) {
+			findings = append(findings, Finding{Rule: rule.ID, Level: rule.Level, Path: path, Message: fmt.Sprintf(format, args...)})
+		})
+	}
+
+	return findings
+}
+
+func checkHTTPSURLs(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	check := func(path, value string) {
+		if value == "" {
+			return
+		}
+
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" {
+			report(path, "%q is not an absolute URL", value)
+			return
+		}
+
+		if u.Scheme != "https" {
+			report(path, "%q should use HTTPS", value)
+		}
+	}
+
+	for i, r := range d.Spec.LinkedResources {
+		check(fmt.Sprintf("spec.linked_resources[%d].url", i), r.URL)
+	}
+
+	if d.Spec.Support != nil {
+		check("spec.support.url", d.Spec.Support.URL)
+	}
+
+	if d.Spec.Legal != nil {
+		check("spec.legal.license_agreement_url", d.Spec.Legal.LicenseAgreementURL)
+		check("spec.legal.privacy_policy_url", d.Spec.Legal.PrivacyPolicyURL)
+	}
+}
+
+func checkEmailAddresses(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	check := func(path, value string) {
+		if value == "" {
+			return
+		}
+
+		if !isEmailAddress(value) {
+			report(path, "%q is not a valid email address", value)
+		}
+	}
+
+	for i, c := range d.Spec.Contacts {
+		check(fmt.Sprintf("spec.contacts[%d].email_address", i), c.EmailAddress)
+	}
+
+	if d.Spec.Support != nil {
+		check("spec.support.email_address", d.Spec.Support.EmailAddress)
+	}
+
+	for i, c := range d.With.Components {
+		if c == nil {
+			continue
+		}
+
+		for j, contact := range c.Contacts {
+			check(fmt.Sprintf("with.components[%d].contacts[%d].email_address", i, j), contact.EmailAddress)
+		}
+	}
+}
+
+// isEmailAddress returns true if s is a bare email address, without a display
+// name, whose domain has at least two labels.
+func isEmailAddress(s string) bool {
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Address != s {
+		return false
+	}
+
+	_, domain, _ := strings.Cut(s, "@")
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// placeholders are the values product create writes, by path.
+var placeholders = []struct{ path, text string }{
+	{"spec.name", "My New Product"},
+	{"spec.descriptions.long", "This can contain long form content about your product."},
+	{"spec.descriptions.short", "A brief synopsis"},
+}
+
+func checkPlaceholderText(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	values := map[string]string{"spec.name": d.Spec.Name}
+	if d.Spec.Descriptions != nil {
+		values["spec.descriptions.long"] = d.Spec.Descriptions.Long
+		values["spec.descriptions.short"] = d.Spec.Descriptions.Short
+	}
+
+	for _, p := range placeholders {
+		if strings.TrimSpace(values[p.path]) == p.text {
+			report(p.path, "%q is placeholder text from product create", p.text)
+		}
+	}
+}
+
+func checkDuplicateComponentNames(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	first := map[string]int{}
+	for i, c := range d.With.Components {
+		if c == nil || c.Name == "" {
+			continue
+		}
+
+		if j, ok := first[c.Name]; ok {
+			report(fmt.Sprintf("with.components[%d].name", i), "%q is also the name of with.components[%d]", c.Name, j)
+			continue
+		}
+		first[c.Name] = i
+	}
+}
+
+func checkDuplicateSearchAliasKeys(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	first := map[string]int{}
+	for i, alias := range d.Spec.SearchAliases {
+		key := strings.ToLower(strings.TrimSpace(alias.Key))
+		if key == "" {
+			continue
+		}
+
+		if j, ok := first[key]; ok {
+			report(fmt.Sprintf("spec.search_aliases[%d].key", i), "%q is also the key of spec.search_aliases[%d]", alias.Key, j)
+			continue
+		}
+		first[key] = i
+	}
+}
+
+func checkContainerImageLocation(d *resource.ProductListingDeclaration, report func(path, format string, args ...any)) {
+	for i, c := range d.With.Components {
+		if c == nil || c.Type != resource.ComponentTypeContainer {
+			continue
+		}
+
+		path := fmt.Sprintf("with.components[%d]", i)
+		if c.Container == nil {
+			report(path, "container component %q does not declare its image's registry and repository", c.Name)
+			continue
+		}
+
+		// Images that are not distributed from a registry have no location.
+		if c.Container.DistributionMethod == resource.ContainerDistributionNonRegistry {
+			continue
+		}
+
+		if c.Container.Registry == "" {
+			report(path+".container", "container component %q does not declare the registry of its image", c.Name)
+		}
+
+		if c.Container.Repository == "" {
+			report(path+".container", "container component %q does not declare the repository of its image", c.Name)
+		}
+	}
+}
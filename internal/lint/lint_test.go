@@ -0,0 +1,180 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+
+	"github.com/opdev/productctl/internal/lint"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// lintClean follows every rule.
+const lintClean = `kind: ProductListing
+spec:
+  name: My Product
+  descriptions:
+    short: Makes things that are hard a little easier.
+  contacts:
+  - email_address: team@example.com
+  support:
+    email_address: support@example.com
+    url: https://example.com/support
+  linked_resources:
+  - url: https://example.com/docs
+  search_aliases:
+  - key: product
+    value: my product
+with:
+  components:
+  - name: operator
+    type: Containers
+    container:
+      registry: quay.io
+      repository: example/operator
+  - name: bundle
+    type: Containers
+    container:
+      distribution_method: non_registry
+`
+
+var _ = Describe("Lint", func() {
+	read := func(s string) *resource.ProductListingDeclaration {
+		d, err := resource.ReadProductListing(strings.NewReader(s))
+		Expect(err).ToNot(HaveOccurred())
+		return d
+	}
+
+	lintAll := func(s string) []lint.Finding {
+		linter, err := lint.New(nil)
+		Expect(err).ToNot(HaveOccurred())
+		return linter.Lint(read(s))
+	}
+
+	finding := func(rule, path string) OmegaMatcher {
+		return MatchFields(IgnoreExtras, Fields{"Rule": Equal(rule), "Path": Equal(path)})
+	}
+
+	It("should find nothing in a declaration following every rule", func() {
+		Expect(lintAll(lintClean)).To(BeEmpty())
+	})
+
+	DescribeTable("should report declarations breaking a rule",
+		func(declaration, rule, path string) {
+			Expect(lintAll(declaration)).To(ConsistOf(finding(rule, path)))
+		},
+		Entry("linked resources over HTTP",
+			strings.Replace(lintClean, "https://example.com/docs", "http://example.com/docs", 1),
+			"https-url", "spec.linked_resources[0].url"),
+		Entry("support URLs that are not absolute",
+			strings.Replace(lintClean, "https://example.com/support", "example.com/support", 1),
+			"https-url", "spec.support.url"),
+		Entry("malformed contact email addresses",
+			strings.Replace(lintClean, "team@example.com", "team@", 1),
+			"email-address", "spec.contacts[0].email_address"),
+		Entry("support email addresses with display names",
+			strings.Replace(lintClean, "support@example.com", "Support <support@example.com>", 1),
+			"email-address", "spec.support.email_address"),
+		Entry("email addresses without a top level domain",
+			strings.Replace(lintClean, "team@example.com", "team@localhost", 1),
+			"email-address", "spec.contacts[0].email_address"),
+		Entry("the placeholder name",
+			strings.Replace(lintClean, "name: My Product", "name: My New Product", 1),
+			"placeholder-text", "spec.name"),
+		Entry("the placeholder short description",
+			strings.Replace(lintClean, "Makes things that are hard a little easier.", "A brief synopsis", 1),
+			"placeholder-text", "spec.descriptions.short"),
+		Entry("components sharing a name",
+			strings.Replace(lintClean, "name: bundle", "name: operator", 1),
+			"duplicate-component-names", "with.components[1].name"),
+		Entry("containers without a repository",
+			strings.Replace(lintClean, "      repository: example/operator\n", "", 1),
+			"container-image-location", "with.components[0].container"),
+	)
+
+	It("should report search aliases sharing a key, ignoring case", func() {
+		declaration := strings.Replace(lintClean, "    value: my product\n", "    value: my product\n  - key: Product\n    value: the product\n", 1)
+		Expect(lintAll(declaration)).To(ConsistOf(finding("duplicate-search-alias-keys", "spec.search_aliases[1].key")))
+	})
+
+	It("should not check disabled rules", func() {
+		linter, err := lint.New([]string{"https-url"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(linter.Rules()).ToNot(ContainElement(HaveField("ID", "https-url")))
+		Expect(linter.Lint(read(strings.Replace(lintClean, "https://", "http://", -1)))).To(BeEmpty())
+	})
+
+	It("should refuse to disable unknown rules", func() {
+		_, err := lint.New([]string{"no-such-rule"})
+		Expect(err).To(MatchError(lint.ErrUnknownRule))
+	})
+})
+
+var _ = Describe("Write", func() {
+	var rules []lint.Rule
+	var results []lint.Result
+
+	BeforeEach(func() {
+		rules = lint.Rules()
+		results = []lint.Result{
+			{
+				Finding: lint.Finding{Rule: "https-url", Level: lint.LevelWarning, Path: "spec.support.url", Message: `"http://example.com" should use HTTPS`},
+				File:    "listings/my.product.yaml",
+				Line:    12,
+			},
+			{
+				Finding: lint.Finding{Rule: "placeholder-text", Level: lint.LevelError, Path: "spec.name", Message: "placeholder, 100%"},
+				File:    "stdin",
+			},
+		}
+	})
+
+	It("should write each result with its location as text", func() {
+		var out bytes.Buffer
+		Expect(lint.Write(&out, lint.FormatText, rules, results)).To(Succeed())
+		Expect(out.String()).To(Equal(`listings/my.product.yaml:12: warning: spec.support.url: "http://example.com" should use HTTPS [https-url]
+stdin: error: spec.name: placeholder, 100% [placeholder-text]
+`))
+	})
+
+	It("should write each result as a GitHub Actions annotation", func() {
+		var out bytes.Buffer
+		Expect(lint.Write(&out, lint.FormatGitHub, rules, results)).To(Succeed())
+		Expect(out.String()).To(Equal(`::warning file=listings/my.product.yaml,line=12,title=https-url::spec.support.url: "http://example.com" should use HTTPS
+::error file=stdin,title=placeholder-text::spec.name: placeholder, 100%25
+`))
+	})
+
+	It("should write a SARIF log", func() {
+		var out bytes.Buffer
+		Expect(lint.Write(&out, lint.FormatSARIF, rules, results)).To(Succeed())
+
+		var log map[string]any
+		Expect(json.Unmarshal(out.Bytes(), &log)).To(Succeed())
+		Expect(log).To(HaveKeyWithValue("version", "2.1.0"))
+		Expect(log).To(HaveKeyWithValue("runs", HaveLen(1)))
+
+		run := log["runs"].([]any)[0].(map[string]any)
+		Expect(run).To(HaveKeyWithValue("tool", HaveKeyWithValue("driver", HaveKeyWithValue("rules", HaveLen(len(rules))))))
+		Expect(run).To(HaveKeyWithValue("results", HaveLen(2)))
+
+		result := run["results"].([]any)[0].(map[string]any)
+		Expect(result).To(HaveKeyWithValue("ruleId", "https-url"))
+		Expect(result).To(HaveKeyWithValue("level", "warning"))
+		Expect(result["locations"]).To(ConsistOf(And(
+			HaveKeyWithValue("physicalLocation", And(
+				HaveKeyWithValue("artifactLocation", HaveKeyWithValue("uri", "listings/my.product.yaml")),
+				HaveKeyWithValue("region", HaveKeyWithValue("startLine", BeNumerically("==", 12))),
+			)),
+			HaveKeyWithValue("logicalLocations", ConsistOf(HaveKeyWithValue("fullyQualifiedName", "spec.support.url"))),
+		)))
+	})
+
+	It("should refuse unknown formats", func() {
+		Expect(lint.Write(&bytes.Buffer{}, "xml", rules, results)).To(MatchError(lint.ErrUnknownFormat))
+	})
+})
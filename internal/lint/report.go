@@ -0,0 +1,206 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/opdev/productctl/internal/version"
+)
+
+var ErrUnknownFormat = errors.New("unknown lint output format")
+
+// Formats in which results are written.
+const (
+	FormatText   = "text"
+	FormatGitHub = "github"
+	FormatSARIF  = "sarif"
+)
+
+// Formats lists every format results may be written in.
+var Formats = []string{FormatText, FormatGitHub, FormatSARIF}
+
+// Result is a finding for a declaration read from a file.
+type Result struct {
+	Finding
+	// File is the name of the file holding the declaration.
+	File string
+	// Line is the line of File holding the offending value, counted from 1,
+	// or 0 if it is not known.
+	Line int
+}
+
+// location describes where the result was found, e.g. my.product.yaml:12.
+func (r Result) location() string {
+	if r.Line == 0 {
+		return r.File
+	}
+
+	return fmt.Sprintf("%s:%d", r.File, r.Line)
+}
+
+// Write writes results to w in format. rules are the rules that were checked.
+func Write(w io.Writer, format string, rules []Rule, results []Result) error {
+	switch format {
+	case FormatText:
+		for _, r := range results {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", r.location(), r.Finding); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatGitHub:
+		return writeGitHub(w, results)
+	case FormatSARIF:
+		return writeSARIF(w, rules, results)
+	}
+
+	return fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, format, strings.Join(Formats, ", "))
+}
+
+// writeGitHub writes results as GitHub Actions workflow commands, which
+// annotate the files in pull requests.
+func writeGitHub(w io.Writer, results []Result) error {
+	commands := map[Level]string{LevelError: "error", LevelWarning: "warning", LevelNote: "notice"}
+	for _, r := range results {
+		properties := "file=" + escapeGitHubProperty(r.File)
+		if r.Line > 0 {
+			properties += fmt.Sprintf(",line=%d", r.Line)
+		}
+		properties += ",title=" + escapeGitHubProperty(r.Rule)
+
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", commands[r.Level], properties, escapeGitHubData(r.Path+": "+r.Message)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var (
+	gitHubData     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	gitHubProperty = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeGitHubData(s string) string {
+	return gitHubData.Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return gitHubProperty.Replace(s)
+}
+
+// The subset of SARIF 2.1.0 written by writeSARIF.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Version        string      `json:"version"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID                   string             `json:"id"`
+		ShortDescription     sarifMessage       `json:"shortDescription"`
+		DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	}
+
+	sarifConfiguration struct {
+		Level Level `json:"level"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     Level           `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+		LogicalLocations []sarifLogical        `json:"logicalLocations"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifact `json:"artifactLocation"`
+		Region           *sarifRegion  `json:"region,omitempty"`
+	}
+
+	sarifArtifact struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+
+	sarifLogical struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+	}
+)
+
+// writeSARIF writes results as a SARIF log, as accepted by code scanning
+// services.
+func writeSARIF(w io.Writer, rules []Rule, results []Result) error {
+	driver := sarifDriver{
+		Name:           version.Version.BaseName,
+		InformationURI: "https://" + version.Version.Name,
+		Version:        version.Version.Version,
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: rule.Level},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: make([]sarifResult, 0, len(results))}
+	for _, r := range results {
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(r.File)}},
+			LogicalLocations: []sarifLogical{{FullyQualifiedName: r.Path}},
+		}
+		if r.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: r.Line}
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    r.Rule,
+			Level:     r.Level,
+			Message:   sarifMessage{Text: r.Path + ": " + r.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,387 @@
+// Package markup converts between Markdown and the restricted subset of HTML
+// the catalog accepts in formatted fields, such as FAQ answers.
+package markup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/yuin/goldmark"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	ErrUnsupportedMarkdown = errors.New("markdown cannot be rendered to catalog HTML")
+	ErrDisallowedHTML      = errors.New("HTML is not accepted by the catalog")
+	ErrMalformedHTML       = errors.New("malformed HTML")
+)
+
+// allowedElements lists the elements the catalog accepts, and the attributes
+// accepted on each.
+var allowedElements = map[string][]string{
+	"a":          {"href", "title"},
+	"b":          nil,
+	"blockquote": nil,
+	"br":         nil,
+	"code":       nil,
+	"em":         nil,
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"hr":         nil,
+	"i":          nil,
+	"li":         nil,
+	"ol":         {"start"},
+	"p":          nil,
+	"pre":        nil,
+	"strong":     nil,
+	"u":          nil,
+	"ul":         nil,
+}
+
+// voidElements are the elements that have no content, and so are never
+// closed.
+var voidElements = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+
+// Check returns an error describing each problem of s found by Problems.
+func Check(s string) error {
+	return errors.Join(Problems(s)...)
+}
+
+// Problems returns an error for each element and attribute of s that the
+// catalog does not accept, wrapping ErrDisallowedHTML, and for each element
+// that is not closed or is closed out of order, wrapping ErrMalformedHTML.
+func Problems(s string) []error {
+	var errs []error
+	var open []string
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); !errors.Is(err, io.EOF) {
+				errs = append(errs, fmt.Errorf("%w: %w", ErrMalformedHTML, err))
+			}
+
+			for _, element := range slices.Backward(open) {
+				errs = append(errs, fmt.Errorf("%w: <%s> is not closed", ErrMalformedHTML, element))
+			}
+			return errs
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attributes, ok := allowedElements[token.Data]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%w: <%s> elements", ErrDisallowedHTML, token.Data))
+			}
+
+			for _, attr := range token.Attr {
+				if ok && !slices.Contains(attributes, attr.Key) {
+					errs = append(errs, fmt.Errorf("%w: the %s attribute of <%s> elements", ErrDisallowedHTML, attr.Key, token.Data))
+				}
+			}
+
+			if token.Type == html.StartTagToken && !slices.Contains(voidElements, token.Data) {
+				open = append(open, token.Data)
+			}
+		case html.EndTagToken:
+			element := tokenizer.Token().Data
+			i := slices.Index(open, element)
+			for j := len(open) - 1; j > i && i >= 0; j-- {
+				errs = append(errs, fmt.Errorf("%w: <%s> is not closed before </%s>", ErrMalformedHTML, open[j], element))
+			}
+
+			switch {
+			case i >= 0:
+				// Assume unclosed elements end here, so that each is only
+				// reported once.
+				open = open[:i]
+			case !slices.Contains(voidElements, element):
+				errs = append(errs, fmt.Errorf("%w: </%s> closes no open element", ErrMalformedHTML, element))
+			}
+		}
+	}
+}
+
+// Length returns the length of s as the catalog measures it: the characters
+// of the HTML as it is stored, markup included, counted as UTF-16 code units,
+// so that characters such as emoji count as two.
+func Length(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// codeLanguage matches the language goldmark notes on fenced code blocks,
+// which the catalog does not accept.
+var codeLanguage = regexp.MustCompile(`<code class="[^"]*">`)
+
+// ToHTML renders the CommonMark Markdown s as HTML the catalog accepts. Raw
+// HTML and elements the catalog does not accept, such as images, are
+// rejected.
+func ToHTML(s string) (string, error) {
+	var out bytes.Buffer
+	if err := goldmark.Convert([]byte(s), &out); err != nil {
+		return "", err
+	}
+
+	rendered := out.String()
+	if strings.Contains(rendered, "<!-- raw HTML omitted -->") {
+		return "", fmt.Errorf("%w: raw HTML is not supported, write it as Markdown", ErrUnsupportedMarkdown)
+	}
+
+	rendered = codeLanguage.ReplaceAllString(rendered, "<code>")
+	if err := Check(rendered); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnsupportedMarkdown, err)
+	}
+
+	return strings.TrimSuffix(rendered, "\n"), nil
+}
+
+// ToMarkdown converts s, HTML the catalog accepts, to Markdown that ToHTML
+// renders as the same HTML. It returns false if s cannot be written as
+// Markdown, e.g. because it holds elements Markdown has no syntax for.
+func ToMarkdown(s string) (string, bool) {
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", false
+	}
+
+	w := markdownWriter{}
+	blocks := w.blocks(nodes)
+	if w.unsupported {
+		return "", false
+	}
+
+	markdown := strings.Join(blocks, "\n\n")
+	rendered, err := ToHTML(markdown)
+	if err != nil || normalize(rendered) != normalize(s) {
+		return "", false
+	}
+
+	return markdown, true
+}
+
+// betweenTags matches the whitespace separating elements, which does not
+// change how HTML is displayed.
+var betweenTags = regexp.MustCompile(`>\s+<`)
+
+// normalize returns s rendered consistently, so that equivalent HTML compares
+// equal.
+func normalize(s string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(s), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return s
+	}
+
+	var out strings.Builder
+	for _, n := range nodes {
+		if err := html.Render(&out, n); err != nil {
+			return s
+		}
+	}
+
+	return betweenTags.ReplaceAllString(strings.TrimSpace(out.String()), "><")
+}
+
+// markdownWriter writes HTML nodes as Markdown, noting whether any of them
+// has no Markdown equivalent.
+type markdownWriter struct {
+	unsupported bool
+}
+
+// blocks returns the Markdown of each block of nodes.
+func (w *markdownWriter) blocks(nodes []*html.Node) []string {
+	var blocks []string
+	var inline []*html.Node
+	flush := func() {
+		if text := strings.TrimSpace(w.inline(inline)); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline = nil
+	}
+
+	for _, n := range nodes {
+		if n.Type != html.ElementNode || !isBlock(n.Data) {
+			inline = append(inline, n)
+			continue
+		}
+
+		flush()
+		blocks = append(blocks, w.block(n))
+	}
+	flush()
+
+	return blocks
+}
+
+// isBlock returns true for the elements written as Markdown blocks.
+func isBlock(element string) bool {
+	switch element {
+	case "p", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "pre", "blockquote", "hr":
+		return true
+	}
+
+	return false
+}
+
+// block returns the Markdown of the block element n.
+func (w *markdownWriter) block(n *html.Node) string {
+	switch n.Data {
+	case "p":
+		return strings.TrimSpace(w.inline(children(n)))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return strings.Repeat("#", int(n.Data[1]-'0')) + " " + strings.TrimSpace(w.inline(children(n)))
+	case "hr":
+		return "---"
+	case "blockquote":
+		return prefixLines(strings.Join(w.blocks(children(n)), "\n\n"), "> ", "> ")
+	case "pre":
+		code := children(n)
+		if len(code) != 1 || code[0].Type != html.ElementNode || code[0].Data != "code" {
+			w.unsupported = true
+			return ""
+		}
+
+		text := textContent(code[0])
+		if strings.Contains(text, "```") {
+			w.unsupported = true
+			return ""
+		}
+
+		return "```\n" + text + "```"
+	case "ul", "ol":
+		var items []string
+		number := 1
+		if start := attribute(n, "start"); start != "" {
+			if _, err := fmt.Sscan(start, &number); err != nil {
+				w.unsupported = true
+			}
+		}
+
+		for _, item := range children(n) {
+			if item.Type != html.ElementNode || item.Data != "li" {
+				if item.Type != html.TextNode || strings.TrimSpace(item.Data) != "" {
+					w.unsupported = true
+				}
+				continue
+			}
+
+			marker := "- "
+			if n.Data == "ol" {
+				marker = fmt.Sprintf("%d. ", number)
+				number++
+			}
+
+			content := strings.Join(w.blocks(children(item)), "\n\n")
+			items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+		}
+
+		return strings.Join(items, "\n")
+	}
+
+	w.unsupported = true
+	return ""
+}
+
+// markdownSpecial matches the characters of text that must be escaped so
+// that they are not read as Markdown.
+var markdownSpecial = regexp.MustCompile("[\\\\`*_\\[\\]<>#!|~]")
+
+// inline returns the Markdown of the inline nodes.
+func (w *markdownWriter) inline(nodes []*html.Node) string {
+	var out strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case html.TextNode:
+			out.WriteString(markdownSpecial.ReplaceAllString(n.Data, `\$0`))
+		case html.ElementNode:
+			content := w.inline(children(n))
+			switch n.Data {
+			case "strong", "b":
+				out.WriteString("**" + content + "**")
+			case "em", "i":
+				out.WriteString("*" + content + "*")
+			case "br":
+				out.WriteString("\\\n")
+			case "code":
+				text := textContent(n)
+				if strings.Contains(text, "`") {
+					w.unsupported = true
+				}
+				out.WriteString("`" + text + "`")
+			case "a":
+				out.WriteString("[" + content + "](" + attribute(n, "href"))
+				if title := attribute(n, "title"); title != "" {
+					out.WriteString(` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`)
+				}
+				out.WriteString(")")
+			default:
+				w.unsupported = true
+			}
+		default:
+			w.unsupported = true
+		}
+	}
+
+	return out.String()
+}
+
+// children returns the child nodes of n.
+func children(n *html.Node) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+
+	return nodes
+}
+
+// textContent returns the text held by n and its descendants.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var out strings.Builder
+	for _, c := range children(n) {
+		out.WriteString(textContent(c))
+	}
+
+	return out.String()
+}
+
+// attribute returns the value of the attribute key of n.
+func attribute(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+// prefixLines prefixes the first line of s with first, and each following
+// non-empty line with rest.
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		case strings.TrimSpace(rest) != "":
+			lines[i] = strings.TrimRight(rest, " ")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
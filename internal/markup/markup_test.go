@@ -0,0 +1,114 @@
+package markup_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/markup"
+)
+
+var _ = Describe("Markup", func() {
+	Context("rendering Markdown", func() {
+		It("should render the elements the catalog accepts", func() {
+			rendered, err := markup.ToHTML("# Install\n\nRun **this**, see [the docs](https://example.com \"Docs\").\n\n- one\n- two\n\n```sh\nmake install\n```\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rendered).To(Equal("<h1>Install</h1>\n<p>Run <strong>this</strong>, see <a href=\"https://example.com\" title=\"Docs\">the docs</a>.</p>\n<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<pre><code>make install\n</code></pre>"))
+		})
+
+		DescribeTable("should reject Markdown the catalog cannot display",
+			func(markdown string) {
+				_, err := markup.ToHTML(markdown)
+				Expect(err).To(MatchError(markup.ErrUnsupportedMarkdown))
+			},
+			Entry("with raw HTML", "Some <script>alert(1)</script> text"),
+			Entry("with images", "![logo](https://example.com/logo.png)"),
+		)
+	})
+
+	Context("checking HTML", func() {
+		It("should accept the elements the catalog accepts", func() {
+			Expect(markup.Check(`<p>Some <a href="https://example.com">link</a></p>`)).To(Succeed())
+		})
+
+		DescribeTable("should report malformed HTML",
+			func(html, message string) {
+				err := markup.Check(html)
+				Expect(err).To(MatchError(markup.ErrMalformedHTML))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("with unclosed elements", "<p>Some <strong>text", "<strong> is not closed"),
+			Entry("with elements closed out of order", "<p><em>Some</p></em>", "<em> is not closed before </p>"),
+			Entry("with stray closing tags", "<p>Some</p></div>", "</div> closes no open element"),
+		)
+
+		It("should accept void elements, which are never closed", func() {
+			Expect(markup.Check("<p>One<br>Two<br/></p><hr>")).To(Succeed())
+		})
+
+		It("should count UTF-16 code units, including markup", func() {
+			Expect(markup.Length("<p>é🚀</p>")).To(Equal(10))
+		})
+
+		It("should report disallowed elements and attributes", func() {
+			err := markup.Check(`<p onclick="x()">Some <img src="logo.png"></p>`)
+			Expect(err).To(MatchError(markup.ErrDisallowedHTML))
+			Expect(err.Error()).To(ContainSubstring("onclick"))
+			Expect(err.Error()).To(ContainSubstring("<img>"))
+		})
+	})
+
+	Context("converting HTML to Markdown", func() {
+		DescribeTable("should convert HTML that renders back unchanged",
+			func(html, markdown string) {
+				converted, ok := markup.ToMarkdown(html)
+				Expect(ok).To(BeTrue())
+				Expect(converted).To(Equal(markdown))
+			},
+			Entry("with paragraphs", "<p>One</p><p>Two <em>2</em></p>", "One\n\nTwo *2*"),
+			Entry("with lists", "<ol>\n<li>first</li>\n<li>second</li>\n</ol>", "1. first\n2. second"),
+			Entry("with code", "<pre><code>make install\n</code></pre>", "```\nmake install\n```"),
+			Entry("with links", `<p><a href="https://example.com">docs</a></p>`, "[docs](https://example.com)"),
+			Entry("with Markdown syntax in text", "<p>2 * 3 = [six]</p>", `2 \* 3 = \[six\]`),
+		)
+
+		DescribeTable("should not convert HTML that Markdown cannot express",
+			func(html string) {
+				_, ok := markup.ToMarkdown(html)
+				Expect(ok).To(BeFalse())
+			},
+			Entry("with text outside paragraphs", "Plain & simple"),
+			Entry("with underlined text", "<p><u>important</u></p>"),
+			Entry("with tables", "<table><tr><td>cell</td></tr></table>"),
+			Entry("with images", `<p><img src="logo.png"></p>`),
+		)
+	})
+	Context("splitting documents into sections", func() {
+		It("should split the document by its second level headings", func() {
+			sections := markup.Sections("# FAQ\n\nAn introduction.\n\n## Is it supported?\n\nYes.\n\n### Details\n\n- one\n\n## Is it free?\n## Is it *fast*? ##\n\n```\n## not a heading\n```\n")
+			Expect(sections).To(Equal([]markup.Section{
+				{Heading: "Is it supported?", Body: "Yes.\n\n### Details\n\n- one"},
+				{Heading: "Is it free?", Body: ""},
+				{Heading: "Is it fast?", Body: "```\n## not a heading\n```"},
+			}))
+		})
+
+		It("should write headings as plain text", func() {
+			sections := markup.Sections("## Does it support `--json` and [links](https://example.com)?\n## Is it \\*literal\\* &amp; <b>bold</b>?\n")
+			Expect(sections).To(Equal([]markup.Section{
+				{Heading: "Does it support --json and links?"},
+				{Heading: "Is it *literal* & bold?"},
+			}))
+		})
+
+		It("should keep the indentation of the first line of a body", func() {
+			sections := markup.Sections("## Example\n\n    indented code\n    more code\n\nText.  \n\n")
+			Expect(sections).To(Equal([]markup.Section{
+				{Heading: "Example", Body: "    indented code\n    more code\n\nText.  "},
+			}))
+		})
+
+		It("should find no sections in documents without second level headings", func() {
+			Expect(markup.Sections("# Title\n\nText.")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package markup
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Section is the content of a Markdown document under one of its second level
+// headings.
+type Section struct {
+	// Heading is the text of the heading, with its inline Markdown rendered
+	// as plain text, e.g. "Is it fast?" for "## Is it *fast*?".
+	Heading string
+	// Body is the Markdown that follows the heading, up to the next second
+	// level heading, without the blank lines that surround it.
+	Body string
+}
+
+// Sections splits the Markdown document s by its second level headings, e.g.
+// "## Is it supported?". Content before the first of them, such as the title
+// of the document, is ignored, as are headings within lists and quotes.
+func Sections(s string) []Section {
+	source := []byte(s)
+	document := goldmark.DefaultParser().Parse(text.NewReader(source))
+
+	var sections []Section
+	for n := document.FirstChild(); n != nil; n = n.NextSibling() {
+		if !isSectionHeading(n) {
+			continue
+		}
+
+		var heading strings.Builder
+		writeText(&heading, n, source)
+
+		section := Section{Heading: strings.TrimSpace(heading.String())}
+		if body := n.NextSibling(); body != nil && !isSectionHeading(body) {
+			end := len(source)
+			for next := body; next != nil; next = next.NextSibling() {
+				if isSectionHeading(next) {
+					end = lineStart(source, next.Pos())
+					break
+				}
+			}
+			section.Body = trimBlankLines(string(source[lineStart(source, body.Pos()):end]))
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// isSectionHeading returns true if n is a second level heading.
+func isSectionHeading(n ast.Node) bool {
+	h, ok := n.(*ast.Heading)
+	return ok && h.Level == 2
+}
+
+// lineStart returns the offset of the start of the line holding offset.
+func lineStart(source []byte, offset int) int {
+	return bytes.LastIndexByte(source[:offset], '\n') + 1
+}
+
+// writeText writes the text of the inline content of n to b, without its
+// markup. Escaped characters and character references are written as the
+// characters they stand for, and raw HTML is left out.
+func writeText(b *strings.Builder, n ast.Node, source []byte) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			value := c.Segment.Value(source)
+			if !c.IsRaw() {
+				value = util.ResolveEntityNames(util.ResolveNumericReferences(util.UnescapePunctuations(value)))
+			}
+			b.Write(value)
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(c.Value)
+		case *ast.AutoLink:
+			b.Write(c.URL(source))
+		case *ast.RawHTML:
+		default:
+			writeText(b, c, source)
+		}
+	}
+}
+
+// trimBlankLines returns s without its leading and trailing blank lines. The
+// indentation of its first line is kept, as it is significant in Markdown.
+func trimBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
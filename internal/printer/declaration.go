@@ -0,0 +1,30 @@
+package printer
+
+import (
+	"strings"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+// DeclarationColumns renders product listing declarations as a table, one row
+// per declaration.
+var DeclarationColumns = &Columns[*resource.ProductListingDeclaration]{
+	Header: []string{"ID", "NAME", "TYPE", "COMPONENTS"},
+	Rows: func(d *resource.ProductListingDeclaration) [][]string {
+		componentIDs := make([]string, 0, len(d.With.Components))
+		for _, c := range d.With.Components {
+			componentIDs = append(componentIDs, c.ID)
+		}
+
+		return [][]string{{d.Spec.ID, d.Spec.Name, string(d.Spec.Type), strings.Join(componentIDs, ",")}}
+	},
+}
+
+// ComponentColumns renders component declarations as a table, one row per
+// declaration.
+var ComponentColumns = &Columns[*resource.ComponentDeclaration]{
+	Header: []string{"ID", "NAME", "TYPE"},
+	Rows: func(d *resource.ComponentDeclaration) [][]string {
+		return [][]string{{d.Spec.ID, d.Spec.Name, string(d.Spec.Type)}}
+	},
+}
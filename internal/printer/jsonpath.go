@@ -0,0 +1,213 @@
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidJSONPath = errors.New("invalid jsonpath expression")
+
+// jsonPath is a parsed JSONPath template: literal text interleaved with
+// expressions enclosed in braces, e.g. "id={.spec._id}". Expressions support
+// a subset of JSONPath: field access (.name or ['name']), array indexes ([0],
+// negative indexes count from the end), and wildcards ([*] or .*).
+type jsonPath struct {
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	// literal is emitted as-is if steps is nil.
+	literal string
+	steps   []jsonPathStep
+}
+
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses template. A template without braces is treated as a
+// single expression.
+func parseJSONPath(template string) (*jsonPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	p := &jsonPath{}
+	for len(template) > 0 {
+		open := strings.IndexByte(template, '{')
+		if open == -1 {
+			p.segments = append(p.segments, jsonPathSegment{literal: template})
+			break
+		}
+
+		if open > 0 {
+			p.segments = append(p.segments, jsonPathSegment{literal: template[:open]})
+		}
+
+		end := strings.IndexByte(template[open:], '}')
+		if end == -1 {
+			return nil, fmt.Errorf("%w: unclosed brace in %q", ErrInvalidJSONPath, template)
+		}
+
+		steps, err := parseJSONPathExpression(template[open+1 : open+end])
+		if err != nil {
+			return nil, err
+		}
+
+		p.segments = append(p.segments, jsonPathSegment{steps: steps})
+		template = template[open+end+1:]
+	}
+
+	return p, nil
+}
+
+func parseJSONPathExpression(expr string) ([]jsonPathStep, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "$")
+
+	steps := []jsonPathStep{}
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			n := strings.IndexAny(expr, ".[")
+			if n == -1 {
+				n = len(expr)
+			}
+
+			field := expr[:n]
+			expr = expr[n:]
+			switch field {
+			case "":
+				// A bare "." refers to the current object.
+				if len(expr) > 0 && expr[0] == '.' {
+					return nil, fmt.Errorf("%w: recursive descent is not supported", ErrInvalidJSONPath)
+				}
+			case "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			default:
+				steps = append(steps, jsonPathStep{field: field})
+			}
+		case '[':
+			end := strings.IndexByte(expr, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unclosed bracket in %q", ErrInvalidJSONPath, expr)
+			}
+
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("%w: unsupported subscript [%s]", ErrInvalidJSONPath, inner)
+				}
+				steps = append(steps, jsonPathStep{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidJSONPath, expr)
+		}
+	}
+
+	return steps, nil
+}
+
+// execute writes the result of evaluating p against data, which is expected to
+// be the result of decoding JSON into an any. Expressions that match more than
+// one value produce those values separated by spaces. Expressions that match
+// nothing produce no output.
+func (p *jsonPath) execute(w io.Writer, data any) error {
+	for _, segment := range p.segments {
+		if segment.steps == nil {
+			if _, err := io.WriteString(w, segment.literal); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values := []any{data}
+		for _, step := range segment.steps {
+			values = step.apply(values)
+		}
+
+		for i, v := range values {
+			if i > 0 {
+				if _, err := io.WriteString(w, " "); err != nil {
+					return err
+				}
+			}
+
+			if err := writeJSONPathValue(w, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s jsonPathStep) apply(values []any) []any {
+	matched := []any{}
+	for _, v := range values {
+		switch typed := v.(type) {
+		case map[string]any:
+			if s.wildcard {
+				for _, key := range sortedKeys(typed) {
+					matched = append(matched, typed[key])
+				}
+				continue
+			}
+
+			if value, ok := typed[s.field]; ok && !s.isIndex {
+				matched = append(matched, value)
+			}
+		case []any:
+			if s.wildcard {
+				matched = append(matched, typed...)
+				continue
+			}
+
+			if !s.isIndex {
+				continue
+			}
+
+			i := s.index
+			if i < 0 {
+				i += len(typed)
+			}
+
+			if i >= 0 && i < len(typed) {
+				matched = append(matched, typed[i])
+			}
+		}
+	}
+
+	return matched
+}
+
+// writeJSONPathValue writes strings as-is, and all other values as JSON.
+func writeJSONPathValue(w io.Writer, v any) error {
+	if s, ok := v.(string); ok {
+		_, err := io.WriteString(w, s)
+		return err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(b)
+	return err
+}
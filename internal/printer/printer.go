@@ -0,0 +1,215 @@
+// Package printer writes command output in the format requested by the user.
+package printer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrUnknownFormat       = errors.New("unknown output format")
+	ErrUnsupportedFormat   = errors.New("output format is not supported by this command")
+	ErrMissingFormatSource = errors.New("output format requires a template, e.g. jsonpath={.spec._id}")
+)
+
+// Format identifies how output is written.
+type Format = string
+
+const (
+	FormatYAML       Format = "yaml"
+	FormatJSON       Format = "json"
+	FormatTable      Format = "table"
+	FormatJSONPath   Format = "jsonpath"
+	FormatGoTemplate Format = "go-template"
+)
+
+// Columns describes how values of type T are rendered as a table.
+type Columns[T any] struct {
+	Header []string
+	// Rows returns the table rows for a value. Values representing a single
+	// resource typically return a single row, and lists return one row per
+	// item.
+	Rows func(T) [][]string
+}
+
+// Printer writes values of type T in a single format.
+type Printer[T any] struct {
+	format   Format
+	columns  *Columns[T]
+	jsonPath *jsonPath
+	template *template.Template
+}
+
+// New returns a Printer for output, which is one of the Format values. The
+// jsonpath and go-template formats take their template after an equals sign,
+// e.g. "jsonpath={.spec._id}". The table format is only accepted if columns
+// is not nil.
+func New[T any](output string, columns *Columns[T]) (*Printer[T], error) {
+	format, source, hasSource := strings.Cut(output, "=")
+
+	p := &Printer[T]{format: format, columns: columns}
+	switch format {
+	case FormatYAML, FormatJSON:
+	case FormatTable:
+		if columns == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+		}
+	case FormatJSONPath:
+		if !hasSource || source == "" {
+			return nil, ErrMissingFormatSource
+		}
+
+		parsed, err := parseJSONPath(source)
+		if err != nil {
+			return nil, err
+		}
+		p.jsonPath = parsed
+	case FormatGoTemplate:
+		if !hasSource || source == "" {
+			return nil, ErrMissingFormatSource
+		}
+
+		parsed, err := template.New("output").Parse(source)
+		if err != nil {
+			return nil, err
+		}
+		p.template = parsed
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, output)
+	}
+
+	if hasSource && p.jsonPath == nil && p.template == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, output)
+	}
+
+	return p, nil
+}
+
+// Usage returns help text for an output flag, listing the formats accepted
+// depending on whether the command supports tables.
+func Usage(tableSupported bool) string {
+	formats := []string{FormatYAML, FormatJSON}
+	if tableSupported {
+		formats = append(formats, FormatTable)
+	}
+	formats = append(formats, FormatJSONPath+"=<template>", FormatGoTemplate+"=<template>")
+
+	return "The output format. Choose from " + strings.Join(formats, ", ")
+}
+
+// Format returns the format of p.
+func (p *Printer[T]) Format() Format {
+	return p.format
+}
+
+// Print writes values to w. Multiple YAML values are written as a
+// multi-document stream, multiple JSON values as a sequence of JSON documents,
+// and multiple table values as rows of a single table. Templates are executed
+// once for each value, each followed by a newline.
+func (p *Printer[T]) Print(w io.Writer, values ...T) error {
+	if p.format == FormatTable {
+		return p.printTable(w, values)
+	}
+
+	for i, v := range values {
+		if i > 0 && p.format == FormatYAML {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		if err := p.printOne(w, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Printer[T]) printOne(w io.Writer, v T) error {
+	switch p.format {
+	case FormatYAML:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	case FormatJSON:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	// Templates are executed against the JSON representation of v, so that
+	// field names match those seen in other formats.
+	data, err := toJSONData(v)
+	if err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	if p.jsonPath != nil {
+		err = p.jsonPath.execute(&out, data)
+	} else {
+		err = p.template.Execute(&out, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	s := out.String()
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+
+	_, err = io.WriteString(w, s)
+	return err
+}
+
+func (p *Printer[T]) printTable(w io.Writer, values []T) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(p.columns.Header, "\t"))
+	for _, v := range values {
+		for _, row := range p.columns.Rows(v) {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	}
+
+	return tw.Flush()
+}
+
+func toJSONData(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,125 @@
+package printer_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/printer"
+	"github.com/opdev/productctl/internal/resource"
+)
+
+type item struct {
+	ID   string   `json:"_id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+var columns = &printer.Columns[item]{
+	Header: []string{"ID", "NAME"},
+	Rows: func(i item) [][]string {
+		return [][]string{{i.ID, i.Name}}
+	},
+}
+
+var _ = Describe("Printer", func() {
+	var (
+		out   *bytes.Buffer
+		items []item
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		items = []item{
+			{ID: "1", Name: "first", Tags: []string{"a", "b"}},
+			{ID: "2", Name: "second"},
+		}
+	})
+
+	DescribeTable("rejecting invalid formats",
+		func(output string, withColumns bool, expected error) {
+			var c *printer.Columns[item]
+			if withColumns {
+				c = columns
+			}
+			_, err := printer.New(output, c)
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("an unknown format", "xml", true, printer.ErrUnknownFormat),
+		Entry("a template for a format that takes none", "yaml={.x}", true, printer.ErrUnknownFormat),
+		Entry("a table without columns", "table", false, printer.ErrUnsupportedFormat),
+		Entry("jsonpath without a template", "jsonpath", true, printer.ErrMissingFormatSource),
+		Entry("go-template without a template", "go-template=", true, printer.ErrMissingFormatSource),
+		Entry("an invalid jsonpath", "jsonpath={.a[", true, printer.ErrInvalidJSONPath),
+		Entry("recursive descent", "jsonpath={..name}", true, printer.ErrInvalidJSONPath),
+	)
+
+	It("should write multiple values as a multi-document YAML stream", func() {
+		p, err := printer.New[item]("yaml", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Print(out, items...)).To(Succeed())
+		Expect(out.String()).To(Equal("_id: \"1\"\nname: first\ntags:\n- a\n- b\n---\n_id: \"2\"\nname: second\n"))
+	})
+
+	It("should write each value as a JSON document", func() {
+		p, err := printer.New[item]("json", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Print(out, items...)).To(Succeed())
+
+		decoder := json.NewDecoder(out)
+		decoded := []item{}
+		for decoder.More() {
+			var i item
+			Expect(decoder.Decode(&i)).To(Succeed())
+			decoded = append(decoded, i)
+		}
+		Expect(decoded).To(Equal(items))
+	})
+
+	It("should write a single table for all values", func() {
+		p, err := printer.New("table", columns)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(p.Print(out, items...)).To(Succeed())
+		Expect(out.String()).To(Equal("ID   NAME\n1    first\n2    second\n"))
+	})
+
+	DescribeTable("evaluating templates against each value",
+		func(output, expected string) {
+			p, err := printer.New[item](output, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(p.Print(out, items...)).To(Succeed())
+			Expect(out.String()).To(Equal(expected))
+		},
+		Entry("a jsonpath field", "jsonpath={._id}", "1\n2\n"),
+		Entry("a jsonpath without braces", "jsonpath=.name", "first\nsecond\n"),
+		Entry("a jsonpath with literals", "jsonpath=id={$._id} name={['name']}", "id=1 name=first\nid=2 name=second\n"),
+		Entry("a jsonpath index", "jsonpath={.tags[-1]}", "b\n\n"),
+		Entry("a jsonpath wildcard", "jsonpath={.tags[*]}", "a b\n\n"),
+		Entry("a jsonpath object", "jsonpath={.tags}", "[\"a\",\"b\"]\n\n"),
+		Entry("a go-template", "go-template={{._id}}:{{.name}}", "1:first\n2:second\n"),
+	)
+})
+
+var _ = Describe("DeclarationColumns", func() {
+	It("should render a row per declaration with its component IDs", func() {
+		declaration := resource.NewProductListing()
+		declaration.Spec.ID = "abc"
+		declaration.Spec.Name = "my product"
+		declaration.With.Components = []*resource.Component{{ID: "c1"}, {ID: "c2"}}
+
+		Expect(printer.DeclarationColumns.Rows(&declaration)).To(Equal([][]string{{"abc", "my product", "", "c1,c2"}}))
+	})
+})
+
+var _ = Describe("ComponentColumns", func() {
+	It("should render a row per declaration", func() {
+		declaration := resource.NewComponentDeclaration()
+		declaration.Spec.ID = "abc"
+		declaration.Spec.Name = "my component"
+		declaration.Spec.Type = "Containers"
+
+		Expect(printer.ComponentColumns.Rows(&declaration)).To(Equal([][]string{{"abc", "my component", "Containers"}}))
+	})
+})
@@ -1,39 +1,129 @@
 package resource
 
-import "time"
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
 
 type Component struct {
-	ID                   string              `json:"_id,omitempty"`
-	CertificationDate    *time.Time          `json:"certification_date,omitempty"`
-	CertificationLevel   string              `json:"certification_level,omitempty"`
-	Contacts             []ComponentContacts `json:"contacts,omitempty"`
-	Container            *ContainerComponent `json:"container,omitempty"`
-	Name                 string              `json:"name,omitempty"`
-	OperatorDistribution string              `json:"operator_distribution,omitempty"`
-	OrgID                int                 `json:"org_id,omitempty"`
-	PublishedBy          string              `json:"published_by,omitempty"`
-	Badges               []string            `json:"badges,omitempty"`
-	Type                 ComponentType       `json:"type,omitempty" jsonschema:"enum=Containers,enum=Helm Chart,enum=OpenShift-cnf"`
-	CreationDate         *time.Time          `json:"creation_date,omitempty"`
-	HelmChart            *HelmChartComponent `json:"helm_chart,omitempty"`
-	LastUpdateDate       *time.Time          `json:"last_update_date,omitempty"`
+	ID                   string                      `json:"_id,omitempty" managed:"identity"`
+	CertificationDate    *time.Time                  `json:"certification_date,omitempty" managed:"backend"`
+	CertificationLevel   string                      `json:"certification_level,omitempty" managed:"backend" jsonschema_description:"The level of certification achieved by the component."`
+	Contacts             []ComponentContacts         `json:"contacts,omitempty" jsonschema_description:"Technical contacts for the component."`
+	Container            *ContainerComponent         `json:"container,omitempty" jsonschema_description:"Details specific to container components."`
+	Name                 string                      `json:"name,omitempty" jsonschema_description:"The name of the component. Used to identify the component within the product listing."`
+	OperatorDistribution string                      `json:"operator_distribution,omitempty" jsonschema_description:"How operators in this component are distributed."`
+	OrgID                int                         `json:"org_id,omitempty" managed:"identity"`
+	PublishedBy          string                      `json:"published_by,omitempty" managed:"backend" jsonschema_description:"Who publishes the component."`
+	Badges               []string                    `json:"badges,omitempty" managed:"backend" jsonschema_description:"Badges awarded to the component on certification."`
+	Type                 ComponentType               `json:"type,omitempty" jsonschema:"enum=Containers,enum=Helm Chart,enum=OpenShift-cnf,enum=OpenShift-virtualization,enum=OpenStack-infra-container,enum=OpenStack-infra-noncontainer,enum=OpenStack-vnf,enum=OpenStack-app-container,enum=RHEL" jsonschema_description:"The type of component. Determines which of the type-specific details apply."`
+	CreationDate         *time.Time                  `json:"creation_date,omitempty" managed:"backend"`
+	HelmChart            *HelmChartComponent         `json:"helm_chart,omitempty" jsonschema_description:"Details specific to Helm chart components."`
+	LastUpdateDate       *time.Time                  `json:"last_update_date,omitempty" managed:"backend"`
+	NonContainer         *NonContainerComponent      `json:"non_container,omitempty" jsonschema_description:"Details specific to components that are not distributed as container images, e.g. RHEL components."`
+	OpenStack            *OpenStackComponent         `json:"openstack,omitempty" jsonschema_description:"Details specific to OpenStack components."`
+	RedHat               *RedHatComponent            `json:"redhat,omitempty" jsonschema_description:"The Red Hat product the component is certified with."`
+	SelfCertification    *SelfCertificationComponent `json:"self_certification,omitempty" jsonschema_description:"Details of the component's self certification."`
+}
+
+// Sanitize removes identifiers that tie this component to a specific entity in
+// the Catalog, and every other value that is not managed by the user.
+func (c *Component) Sanitize() {
+	clearManaged(reflect.ValueOf(c))
+}
+
+// KeepDeclared sets the values of c that are held only by the declaration,
+// e.g. whether a Helm chart is signed, to those of declared. The backend does
+// not hold these values, so components it returns lack them.
+func (c *Component) KeepDeclared(declared *Component) {
+	if declared == nil {
+		return
+	}
+
+	copyDeclarationOwned(reflect.ValueOf(c).Elem(), reflect.ValueOf(declared).Elem())
 }
 
 type ComponentContacts struct {
-	EmailAddress string `json:"email_address,omitempty"`
-	Type         string `json:"type,omitempty" jsonschema:"enum=Technical contact"`
+	EmailAddress string `json:"email_address,omitempty" jsonschema_description:"The email address of the contact."`
+	Type         string `json:"type,omitempty" jsonschema:"enum=Technical contact" jsonschema_description:"The kind of contact."`
 }
 
 type ComponentType = string
 
 const (
-	ComponentTypeContainer ComponentType = "Containers"
-	ComponentTypeHelmChart ComponentType = "Helm Chart"
-	ComponentTypeCNF       ComponentType = "OpenShift-cnf"
-	// ComponentTypeOCPVirt                    ComponentType = "OpenShift-virtualization"
-	// ComponentTypeOpenStackInfraContainer    ComponentType = "OpenStack-infra-container"
-	// ComponentTypeOpenStackInfraNonContainer ComponentType = "OpenStack-infra-noncontainer"
-	// ComponentTypeOpenStackVNF               ComponentType = "OpenStack-vnf"
-	// ComponentTypeOpenStackAppContainer      ComponentType = "OpenStack-app-container"
-	// ComponentTypeRHEL                       ComponentType = "RHEL"
+	ComponentTypeContainer                  ComponentType = "Containers"
+	ComponentTypeHelmChart                  ComponentType = "Helm Chart"
+	ComponentTypeCNF                        ComponentType = "OpenShift-cnf"
+	ComponentTypeOCPVirt                    ComponentType = "OpenShift-virtualization"
+	ComponentTypeOpenStackInfraContainer    ComponentType = "OpenStack-infra-container"
+	ComponentTypeOpenStackInfraNonContainer ComponentType = "OpenStack-infra-noncontainer"
+	ComponentTypeOpenStackVNF               ComponentType = "OpenStack-vnf"
+	ComponentTypeOpenStackAppContainer      ComponentType = "OpenStack-app-container"
+	ComponentTypeRHEL                       ComponentType = "RHEL"
 )
+
+// componentDetails are the type-specific details of components, and the
+// component types each applies to. Details that apply to every type, e.g.
+// redhat, are not listed.
+var componentDetails = []struct {
+	field string
+	isSet func(c *Component) bool
+	types []ComponentType
+}{
+	{
+		field: "container",
+		isSet: func(c *Component) bool { return c.Container != nil },
+		types: []ComponentType{
+			ComponentTypeContainer,
+			ComponentTypeCNF,
+			ComponentTypeOCPVirt,
+			ComponentTypeOpenStackInfraContainer,
+			ComponentTypeOpenStackVNF,
+			ComponentTypeOpenStackAppContainer,
+		},
+	},
+	{
+		field: "helm_chart",
+		isSet: func(c *Component) bool { return c.HelmChart != nil },
+		types: []ComponentType{ComponentTypeHelmChart},
+	},
+	{
+		field: "non_container",
+		isSet: func(c *Component) bool { return c.NonContainer != nil },
+		types: []ComponentType{ComponentTypeRHEL, ComponentTypeOpenStackInfraNonContainer},
+	},
+	{
+		field: "openstack",
+		isSet: func(c *Component) bool { return c.OpenStack != nil },
+		types: []ComponentType{
+			ComponentTypeOpenStackInfraContainer,
+			ComponentTypeOpenStackInfraNonContainer,
+			ComponentTypeOpenStackVNF,
+			ComponentTypeOpenStackAppContainer,
+		},
+	},
+}
+
+// detailViolations reports the type-specific details of the component, at
+// path, that do not apply to its type, or that are invalid. Components without
+// a type may hold any details.
+func (c *Component) detailViolations(path string) []Violation {
+	var violations []Violation
+	if c.HelmChart != nil {
+		violations = append(violations, c.HelmChart.violations(joinFieldPath(path, "helm_chart"))...)
+	}
+
+	for _, d := range componentDetails {
+		if c.Type != "" && d.isSet(c) && !slices.Contains(d.types, c.Type) {
+			violations = append(violations, Violation{
+				Path:    joinFieldPath(path, d.field),
+				Message: fmt.Sprintf("only applies to components of type %s, found %q", strings.Join(d.types, ", "), c.Type),
+			})
+		}
+	}
+
+	return violations
+}
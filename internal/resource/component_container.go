@@ -10,24 +10,24 @@ const (
 )
 
 type ContainerComponent struct {
-	ApplicationCategories []ApplicationCategory `json:"application_categories,omitempty" jsonschema:"maxItems=3,enum=Accounting,enum=AI / Machine learning,enum=API Management,enum=Application Delivery,enum=Application Server,enum=Automation,enum=Backup & Recovery,enum=Business Intelligence,enum=Business Process Management,enum=Capacity Management,enum=Cloud Management,enum=Collaboration/Groupware/Messaging,enum=Configuration Management,enum=Console,enum=Container Platform / Management,enum=Content Management/Authoring,enum=Customer Relationship Management,enum=Dashboard,enum=Database & Data Management,enum=Data Store,enum=Developer Tools,enum=Enterprise Resource Planning,enum=Identity Management,enum=Integration,enum=Logging,enum=Logging & Metrics,enum=Management,enum=Messaging,enum=Metrics,enum=Migration,enum=Middleware,enum=Mobile Application Development Platform (MADP),enum=Monitoring,enum=Network Management,enum=Networking,enum=Observability,enum=Other,enum=Operating System,enum=Performance Management,enum=Plugin,enum=Policy Enforcement,enum=Programming Languages & Runtimes,enum=Scheduling,enum=Search,enum=Security,enum=Storage,enum=Tracing,enum=Virtualization Platform,enum=Web Services"`
+	ApplicationCategories []ApplicationCategory `json:"application_categories,omitempty" jsonschema:"maxItems=3,enum=Accounting,enum=AI / Machine learning,enum=API Management,enum=Application Delivery,enum=Application Server,enum=Automation,enum=Backup & Recovery,enum=Business Intelligence,enum=Business Process Management,enum=Capacity Management,enum=Cloud Management,enum=Collaboration/Groupware/Messaging,enum=Configuration Management,enum=Console,enum=Container Platform / Management,enum=Content Management/Authoring,enum=Customer Relationship Management,enum=Dashboard,enum=Database & Data Management,enum=Data Store,enum=Developer Tools,enum=Enterprise Resource Planning,enum=Identity Management,enum=Integration,enum=Logging,enum=Logging & Metrics,enum=Management,enum=Messaging,enum=Metrics,enum=Migration,enum=Middleware,enum=Mobile Application Development Platform (MADP),enum=Monitoring,enum=Network Management,enum=Networking,enum=Observability,enum=Other,enum=Operating System,enum=Performance Management,enum=Plugin,enum=Policy Enforcement,enum=Programming Languages & Runtimes,enum=Scheduling,enum=Search,enum=Security,enum=Storage,enum=Tracing,enum=Virtualization Platform,enum=Web Services" jsonschema_description:"The application categories the container is listed under in the catalog."`
 	// NOTE: this is plural in the json and in the API spec, but doesn't
 	// actually contain multiple values.
-	BuildCategory         BuildCategory                 `json:"build_categories,omitempty" jsonschema:"enum=Standalone image,enum=Component image,enum=Operator image,enum=Operator bundle"`
-	DistributionMethod    ContainerDistributionMethod   `json:"distribution_method,omitempty" jsonschema:"enum=rhcc,enum=external,enum=non_registry,enum=marketplace_only"`
-	PID                   string                        `json:"isv_pid,omitempty" jsonschema:"description=This value will be set for you"`
-	OSContentType         ContainerComponentContentType `json:"os_content_type,omitempty" jsonschema:"enum=Red Hat Enterprise Linux,enum=Red Hat Universal Base Image (UBI),enum=Operator Bundle Image,enum=Scratch Image"`
+	BuildCategory         BuildCategory                 `json:"build_categories,omitempty" jsonschema:"enum=Standalone image,enum=Component image,enum=Operator image,enum=Operator bundle" jsonschema_description:"How the container image is built and used."`
+	DistributionMethod    ContainerDistributionMethod   `json:"distribution_method,omitempty" jsonschema:"enum=rhcc,enum=external,enum=non_registry,enum=marketplace_only" jsonschema_description:"Where the container image is distributed from."`
+	PID                   string                        `json:"isv_pid,omitempty" managed:"backend" jsonschema:"description=This value will be set for you"`
+	OSContentType         ContainerComponentContentType `json:"os_content_type,omitempty" jsonschema:"enum=Red Hat Enterprise Linux,enum=Red Hat Universal Base Image (UBI),enum=Operator Bundle Image,enum=Scratch Image" jsonschema_description:"The base the container image is built from."`
 	Privileged            *bool                         `json:"privileged,omitempty" jsonschema:"description=Whether your container user is root"`
 	Registry              string                        `json:"registry,omitempty" jsonschema:"description=The registry hosting your image. E.g. quay.io."`
 	Repository            string                        `json:"repository,omitempty" jsonschema:"description=The org/repository for your image. E.g. my-org/my-image."`
-	RepositoryDescription string                        `json:"repository_description,omitempty"`
-	RepositoryName        string                        `json:"repository_name,omitempty"`
-	ReleaseCategories     []ReleaseCategory             `json:"release_categories,omitempty" jsonschema:"enum=Generally Available,enum=Beta"`
-	ShortDescription      string                        `json:"short_description,omitempty"`
-	SupportPlatforms      []string                      `json:"support_platforms,omitempty"`
-	Type                  ContainerComponentType        `json:"type,omitempty" jsonschema:"enum=container,enum=operator bundle image"`
-	GithubUsernames       []string                      `json:"github_usernames,omitempty"`
-	HostedRegistry        *bool                         `json:"hosted_registry,omitempty"`
+	RepositoryDescription string                        `json:"repository_description,omitempty" jsonschema_description:"A description of the repository, displayed in the catalog."`
+	RepositoryName        string                        `json:"repository_name,omitempty" jsonschema_description:"The name of the repository, displayed in the catalog."`
+	ReleaseCategories     []ReleaseCategory             `json:"release_categories,omitempty" jsonschema:"enum=Generally Available,enum=Beta" jsonschema_description:"The release categories of the container image."`
+	ShortDescription      string                        `json:"short_description,omitempty" jsonschema_description:"A brief synopsis of the container image."`
+	SupportPlatforms      []string                      `json:"support_platforms,omitempty" jsonschema_description:"The platforms the container image is supported on."`
+	Type                  ContainerComponentType        `json:"type,omitempty" jsonschema:"enum=container,enum=operator bundle image" jsonschema_description:"The kind of container image."`
+	GithubUsernames       []string                      `json:"github_usernames,omitempty" jsonschema_description:"GitHub users permitted to submit certification results for the component."`
+	HostedRegistry        *bool                         `json:"hosted_registry,omitempty" jsonschema_description:"Whether the image is hosted in a Red Hat registry."`
 }
 
 type ContainerDistributionMethod = string
@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/opdev/productctl/internal/file"
+)
+
+var ErrUnresolvedComponentReference = errors.New("component reference could not be resolved")
+
+// KindComponent is the kind of standalone component declarations.
+const KindComponent = "Component"
+
+// ComponentDeclaration is a component declared on its own, rather than within
+// a product listing, so that it can be managed once and referenced by several
+// product listings.
+type ComponentDeclaration struct {
+	APIVersion string    `json:"apiVersion,omitempty" jsonschema:"enum=productctl.opdev.io/v1" jsonschema_description:"The version of the declaration format. Declarations of previous versions are converted when read, and can be upgraded with productctl product migrate."`
+	Kind       string    `json:"kind" jsonschema_description:"The kind of resource declared. Must be Component."`
+	Spec       Component `json:"spec" jsonschema_description:"The component."`
+}
+
+// NewComponentDeclaration returns a net-new component declaration.
+func NewComponentDeclaration() ComponentDeclaration {
+	return ComponentDeclaration{
+		APIVersion: CurrentAPIVersion,
+		Kind:       KindComponent,
+	}
+}
+
+// Sanitize removes identifiers that tie this declaration to a specific entity
+// in the Catalog.
+func (d *ComponentDeclaration) Sanitize() {
+	d.Spec.Sanitize()
+}
+
+// ComponentReference references a component managed by its own declaration,
+// either by its ID, or by the file containing its declaration. Referenced
+// components are attached to the product listing when it is applied, but are
+// not modified.
+type ComponentReference struct {
+	ID   string `json:"_id,omitempty" jsonschema_description:"The ID of the referenced component."`
+	File string `json:"file,omitempty" jsonschema_description:"The file containing the declaration of the referenced component, relative to this declaration. The component must have been applied, so that the file contains its ID."`
+
+	// resolvedID is the ID read from File by ResolveComponentReferences.
+	resolvedID string
+}
+
+// ComponentID returns the ID of the referenced component, which is only known
+// for references by file once ResolveComponentReferences has been called.
+func (r *ComponentReference) ComponentID() string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return r.resolvedID
+}
+
+// ResolveComponentReferences reads the ID of each component referenced by
+// file, relative to baseDir. If overlay is not empty and the referenced file
+// has an overlay of that name, the ID is read from the overlay, which holds
+// the ID of the component in its environment. Referenced components must have
+// been applied.
+func (d *ProductListingDeclaration) ResolveComponentReferences(baseDir string, overlay string, opts ...ReadOption) error {
+	for i, ref := range d.With.ComponentRefs {
+		path := fmt.Sprintf("with.component_refs[%d]", i)
+		switch {
+		case ref.ID != "" && ref.File != "":
+			return fmt.Errorf("%w: %s: only one of _id and file may be set", ErrUnresolvedComponentReference, path)
+		case ref.ID != "":
+			continue
+		case ref.File == "":
+			return fmt.Errorf("%w: %s: one of _id or file must be set", ErrUnresolvedComponentReference, path)
+		}
+
+		filename := ref.File
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(baseDir, filename)
+		}
+
+		f, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrUnresolvedComponentReference, path, err)
+		}
+
+		stream, err := ReadComponentStream(f, append(slices.Clip(opts), WithBaseDir(filepath.Dir(filename)))...)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%w: %s: %s: %w", ErrUnresolvedComponentReference, path, ref.File, err)
+		}
+
+		components := stream.Declarations()
+		if len(components) != 1 {
+			return fmt.Errorf("%w: %s: %s must contain exactly one component declaration, found %d", ErrUnresolvedComponentReference, path, ref.File, len(components))
+		}
+
+		component := components[0]
+		if overlay != "" {
+			ov, exists, err := ReadComponentOverlayFile(file.OverlayFilename(filename, overlay), opts...)
+			if err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrUnresolvedComponentReference, path, err)
+			}
+
+			if exists {
+				if component, err = ov.Apply(0, component); err != nil {
+					return fmt.Errorf("%w: %s: %w", ErrUnresolvedComponentReference, path, err)
+				}
+			}
+		}
+
+		if component.Spec.ID == "" {
+			return fmt.Errorf("%w: %s: %s has not been applied, apply it with productctl component apply", ErrUnresolvedComponentReference, path, ref.File)
+		}
+
+		ref.resolvedID = component.Spec.ID
+	}
+
+	return nil
+}
+
+// KeepComponentRefs references the components of d that refs reference,
+// rather than embedding them, so that a product listing fetched from the
+// backend keeps the references of the declaration it was applied from. refs
+// must have been resolved with ResolveComponentReferences, and are kept as
+// they are, by ID or by file. References to components that are no longer
+// attached to d are dropped.
+func (d *ProductListingDeclaration) KeepComponentRefs(refs []*ComponentReference) {
+	attached := map[string]bool{}
+	for _, c := range d.With.Components {
+		attached[c.ID] = true
+	}
+
+	referenced := map[string]bool{}
+	d.With.ComponentRefs = nil
+	for _, ref := range refs {
+		if id := ref.ComponentID(); id != "" && attached[id] && !referenced[id] {
+			referenced[id] = true
+			d.With.ComponentRefs = append(d.With.ComponentRefs, ref)
+		}
+	}
+
+	d.With.Components = slices.DeleteFunc(d.With.Components, func(c *Component) bool { return referenced[c.ID] })
+}
@@ -0,0 +1,140 @@
+package resource_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("ComponentDeclaration", func() {
+	const applied = `kind: Component
+spec:
+  _id: component-id
+  org_id: 1
+  name: base-image
+  type: Containers
+  container:
+    isv_pid: pid
+    registry: quay.io
+`
+
+	It("should read component declarations", func() {
+		component, err := resource.ReadComponent(bytes.NewBufferString(applied))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(component.APIVersion).To(Equal(resource.CurrentAPIVersion))
+		Expect(component.Spec.ID).To(Equal("component-id"))
+		Expect(component.Spec.Container.Registry).To(Equal("quay.io"))
+	})
+
+	It("should reject declarations of other kinds", func() {
+		_, err := resource.ReadComponent(bytes.NewBufferString("kind: ProductListing\n"))
+		Expect(err).To(MatchError(resource.ErrUnknownKind))
+
+		_, err = resource.ReadProductListing(bytes.NewBufferString(applied))
+		Expect(err).To(MatchError(resource.ErrUnknownKind))
+	})
+
+	It("should report unknown fields when strict", func() {
+		_, err := resource.ReadComponentStream(bytes.NewBufferString("kind: Component\n---\nkind: Component\nspec:\n  nmae: typo\n"), resource.WithStrict(true))
+		Expect(err).To(MatchError(resource.ErrUnknownFields))
+		Expect(err.Error()).To(ContainSubstring("line 5, column 3: spec.nmae"))
+	})
+
+	It("should sanitize identifiers", func() {
+		component, err := resource.ReadComponent(bytes.NewBufferString(applied))
+		Expect(err).ToNot(HaveOccurred())
+
+		component.Sanitize()
+		Expect(component.Spec.ID).To(BeEmpty())
+		Expect(component.Spec.OrgID).To(BeZero())
+		Expect(component.Spec.Container.PID).To(BeEmpty())
+		Expect(component.Spec.Name).To(Equal("base-image"))
+	})
+
+	It("should validate against the component schema", func() {
+		component := resource.NewComponentDeclaration()
+		component.Spec.Type = "not a type"
+		err := component.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring("spec.type: "))
+	})
+
+	When("resolving component references", func() {
+		var dir string
+
+		BeforeEach(func() {
+			dir = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(dir, "applied.yaml"), []byte(applied), 0o644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "unapplied.yaml"), []byte("kind: Component\nspec:\n  name: new\n"), 0o644)).To(Succeed())
+		})
+
+		It("should read the IDs of components referenced by file", func() {
+			listing := resource.NewProductListing()
+			listing.With.ComponentRefs = []*resource.ComponentReference{{ID: "by-id"}, {File: "applied.yaml"}}
+
+			Expect(listing.ResolveComponentReferences(dir, "")).To(Succeed())
+			Expect(listing.With.ComponentRefs[0].ComponentID()).To(Equal("by-id"))
+			Expect(listing.With.ComponentRefs[1].ComponentID()).To(Equal("component-id"))
+		})
+
+		It("should read the IDs of components referenced by file from their overlay of the environment", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "applied.stage.overlay.yaml"), []byte("spec:\n  _id: stage-component-id\n"), 0o644)).To(Succeed())
+			listing := resource.NewProductListing()
+			listing.With.ComponentRefs = []*resource.ComponentReference{{File: "applied.yaml"}}
+
+			Expect(listing.ResolveComponentReferences(dir, "stage")).To(Succeed())
+			Expect(listing.With.ComponentRefs[0].ComponentID()).To(Equal("stage-component-id"))
+
+			Expect(listing.ResolveComponentReferences(dir, "prod")).To(Succeed())
+			Expect(listing.With.ComponentRefs[0].ComponentID()).To(Equal("component-id"))
+
+			Expect(os.WriteFile(filepath.Join(dir, "applied.prod.overlay.yaml"), []byte("# not applied to prod yet\n"), 0o644)).To(Succeed())
+			err := listing.ResolveComponentReferences(dir, "prod")
+			Expect(err).To(MatchError(ContainSubstring("has not been applied")))
+		})
+
+		It("should not write resolved IDs back", func() {
+			listing := resource.NewProductListing()
+			listing.With.ComponentRefs = []*resource.ComponentReference{{File: "applied.yaml"}}
+			Expect(listing.ResolveComponentReferences(dir, "")).To(Succeed())
+
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString("kind: ProductListing\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.Replace(0, &listing)).To(Succeed())
+			Expect(string(stream.Bytes())).ToNot(ContainSubstring("component-id"))
+		})
+
+		It("should keep references to attached components when fetched", func() {
+			declared := resource.NewProductListing()
+			declared.With.ComponentRefs = []*resource.ComponentReference{{File: "applied.yaml"}, {ID: "detached"}}
+			Expect(declared.ResolveComponentReferences(dir, "")).To(Succeed())
+
+			fetched := resource.NewProductListing()
+			fetched.With.Components = []*resource.Component{{ID: "component-id", Name: "base-image"}, {ID: "embedded-id", Name: "embedded"}}
+			fetched.KeepComponentRefs(declared.With.ComponentRefs)
+
+			Expect(fetched.With.ComponentRefs).To(HaveExactElements(HaveField("File", "applied.yaml")))
+			Expect(fetched.With.Components).To(HaveExactElements(HaveField("ID", "embedded-id")))
+		})
+
+		DescribeTable("should refuse references that cannot be resolved",
+			func(ref resource.ComponentReference, message string) {
+				listing := resource.NewProductListing()
+				listing.With.ComponentRefs = []*resource.ComponentReference{&ref}
+
+				err := listing.ResolveComponentReferences(dir, "")
+				Expect(err).To(MatchError(resource.ErrUnresolvedComponentReference))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("neither set", resource.ComponentReference{}, "one of _id or file must be set"),
+			Entry("both set", resource.ComponentReference{ID: "id", File: "applied.yaml"}, "only one of _id and file may be set"),
+			Entry("missing file", resource.ComponentReference{File: "missing.yaml"}, "no such file"),
+			Entry("unapplied component", resource.ComponentReference{File: "unapplied.yaml"}, "has not been applied"),
+		)
+	})
+})
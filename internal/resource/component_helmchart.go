@@ -1,13 +1,25 @@
 package resource
 
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+)
+
 type HelmChartComponent struct {
-	ApplicationCategories []ApplicationCategory       `json:"application_categories,omitempty" jsonschema:"maxItems=3,enum=Accounting,enum=AI / Machine learning,enum=API Management,enum=Application Delivery,enum=Application Server,enum=Automation,enum=Backup & Recovery,enum=Business Intelligence,enum=Business Process Management,enum=Capacity Management,enum=Cloud Management,enum=Collaboration/Groupware/Messaging,enum=Configuration Management,enum=Console,enum=Container Platform / Management,enum=Content Management/Authoring,enum=Customer Relationship Management,enum=Dashboard,enum=Database & Data Management,enum=Data Store,enum=Developer Tools,enum=Enterprise Resource Planning,enum=Identity Management,enum=Integration,enum=Logging,enum=Logging & Metrics,enum=Management,enum=Messaging,enum=Metrics,enum=Migration,enum=Middleware,enum=Mobile Application Development Platform (MADP),enum=Monitoring,enum=Network Management,enum=Networking,enum=Observability,enum=Other,enum=Operating System,enum=Performance Management,enum=Plugin,enum=Policy Enforcement,enum=Programming Languages & Runtimes,enum=Scheduling,enum=Search,enum=Security,enum=Storage,enum=Tracing,enum=Virtualization Platform,enum=Web Services"`
-	ChartName             string                      `json:"chart_name,omitempty"`
-	Repository            string                      `json:"repository,omitempty"`
-	ShortDescription      string                      `json:"short_description,omitempty"`
-	LongDescription       string                      `json:"long_description,omitempty"`
-	GitHubUsernames       []string                    `json:"github_usernames,omitempty"`
-	DistributionMethod    HelmChartDistributionMethod `json:"distribution_method,omitempty" jsonschema:"enum=redhat,enum=external,enum=undistributed"`
+	ApplicationCategories    []ApplicationCategory       `json:"application_categories,omitempty" jsonschema:"maxItems=3,enum=Accounting,enum=AI / Machine learning,enum=API Management,enum=Application Delivery,enum=Application Server,enum=Automation,enum=Backup & Recovery,enum=Business Intelligence,enum=Business Process Management,enum=Capacity Management,enum=Cloud Management,enum=Collaboration/Groupware/Messaging,enum=Configuration Management,enum=Console,enum=Container Platform / Management,enum=Content Management/Authoring,enum=Customer Relationship Management,enum=Dashboard,enum=Database & Data Management,enum=Data Store,enum=Developer Tools,enum=Enterprise Resource Planning,enum=Identity Management,enum=Integration,enum=Logging,enum=Logging & Metrics,enum=Management,enum=Messaging,enum=Metrics,enum=Migration,enum=Middleware,enum=Mobile Application Development Platform (MADP),enum=Monitoring,enum=Network Management,enum=Networking,enum=Observability,enum=Other,enum=Operating System,enum=Performance Management,enum=Plugin,enum=Policy Enforcement,enum=Programming Languages & Runtimes,enum=Scheduling,enum=Search,enum=Security,enum=Storage,enum=Tracing,enum=Virtualization Platform,enum=Web Services" jsonschema_description:"The application categories the chart is listed under in the catalog."`
+	ChartName                string                      `json:"chart_name,omitempty" jsonschema_description:"The name of the chart."`
+	Repository               string                      `json:"repository,omitempty" jsonschema_description:"The repository hosting the chart."`
+	ShortDescription         string                      `json:"short_description,omitempty" jsonschema_description:"A brief synopsis of the chart."`
+	LongDescription          string                      `json:"long_description,omitempty" jsonschema_description:"A long form description of the chart."`
+	GitHubUsernames          []string                    `json:"github_usernames,omitempty" jsonschema_description:"GitHub users permitted to submit certification results for the component."`
+	DistributionMethod       HelmChartDistributionMethod `json:"distribution_method,omitempty" jsonschema:"enum=redhat,enum=external,enum=undistributed" jsonschema_description:"Where the chart is distributed from."`
+	DistributionInstructions string                      `json:"distribution_instructions,omitempty" jsonschema_description:"Instructions for users to access a chart that is distributed externally."`
+	Namespace                string                      `json:"namespace,omitempty" jsonschema_description:"The namespace of the chart, often the organization or user that owns it. Required once the component is published, and only changed until then."`
+	OCPVersions              []string                    `json:"ocp_versions,omitempty" jsonschema:"uniqueItems=true,pattern=^[0-9]+\\.[0-9]+$" jsonschema_description:"The OpenShift versions the chart supports, e.g. 4.14."`
+	PublicPGPKey             string                      `json:"public_pgp_key,omitempty" jsonschema_description:"The base64 encoded PGP public key that verifies signed chart submissions. Required for signed charts distributed by Red Hat."`
+	Signed                   bool                        `json:"signed,omitempty" managed:"declaration" jsonschema_description:"Whether chart submissions are signed with the key in public_pgp_key. Kept in the declaration only, as the catalog API has no such field."`
+	GitHubPullRequest        string                      `json:"github_pull_request,omitempty" jsonschema:"pattern=^https://github\\.com/[^/]+/[^/]+/pull/[0-9]+$" jsonschema_description:"The URL of the pull request submitting the chart for certification."`
 }
 
 // HelmChartDistributionMethod is a string alias for the distribution method of a Helm chart.
@@ -18,3 +30,38 @@ const (
 	HelmChartDistributionMethodExternal      HelmChartDistributionMethod = "external"
 	HelmChartDistributionMethodUndistributed HelmChartDistributionMethod = "undistributed"
 )
+
+// armoredPGPPublicKey begins an ASCII armored PGP public key.
+const armoredPGPPublicKey = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+
+// violations reports the values of the chart, at path, that do not satisfy
+// the constraints that its schema cannot express.
+func (h *HelmChartComponent) violations(path string) []Violation {
+	var violations []Violation
+	keyPath := joinFieldPath(path, "public_pgp_key")
+	switch {
+	case h.PublicPGPKey == "" && h.Signed && h.DistributionMethod == HelmChartDistributionMethodRedHat:
+		violations = append(violations, Violation{Path: keyPath, Message: "is required for signed charts distributed by Red Hat"})
+	case h.PublicPGPKey != "" && !isPGPPublicKey(h.PublicPGPKey):
+		violations = append(violations, Violation{Path: keyPath, Message: "must be a base64 encoded PGP public key"})
+	}
+
+	return violations
+}
+
+// isPGPPublicKey returns true if key is a base64 encoded PGP public key,
+// either ASCII armored or binary.
+func isPGPPublicKey(key string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key), ""))
+	if err != nil || len(decoded) == 0 {
+		return false
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(decoded), []byte(armoredPGPPublicKey)) {
+		return true
+	}
+
+	// The first packet of a binary key is a public key packet, tag 6, in
+	// either the new or the old packet format.
+	return decoded[0] == 0xC6 || decoded[0]&0xFC == 0x98
+}
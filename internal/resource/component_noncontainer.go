@@ -0,0 +1,5 @@
+package resource
+
+type NonContainerComponent struct {
+	SourceSystemID string `json:"source_system_id,omitempty" jsonschema_description:"The ID of the component in the system it is built and distributed from."`
+}
@@ -0,0 +1,5 @@
+package resource
+
+type OpenStackComponent struct {
+	Service string `json:"service,omitempty" jsonschema_description:"The OpenStack service the component provides or extends, e.g. cinder. Determines the test suites run on certification."`
+}
@@ -0,0 +1,8 @@
+package resource
+
+type RedHatComponent struct {
+	ProductID        int    `json:"product_id,omitempty" jsonschema_description:"The ID of the Red Hat product."`
+	ProductName      string `json:"product_name,omitempty" jsonschema_description:"The name of the Red Hat product."`
+	ProductVersion   string `json:"product_version,omitempty" jsonschema_description:"The version of the Red Hat product."`
+	ProductVersionID int    `json:"product_version_id,omitempty" jsonschema_description:"The ID of the version of the Red Hat product."`
+}
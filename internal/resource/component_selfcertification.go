@@ -0,0 +1,11 @@
+package resource
+
+type SelfCertificationComponent struct {
+	AppProfiler          *bool  `json:"app_profiler,omitempty" jsonschema_description:"Whether the application has been profiled."`
+	AppRunsOnAppType     *bool  `json:"app_runs_on_app_type,omitempty" jsonschema_description:"Whether the application runs on the platform it is certified for."`
+	AuthLogin            *bool  `json:"auth_login,omitempty" jsonschema_description:"Whether the certification evidence URL requires a customer login."`
+	CertificationURL     string `json:"certification_url,omitempty" jsonschema_description:"The URL of the evidence of the self certification."`
+	CommSupportOnAppType *bool  `json:"comm_support_on_app_type,omitempty" jsonschema_description:"Whether the application is commercially supported on the platform it is certified for."`
+	Requested            *bool  `json:"requested,omitempty" jsonschema_description:"Whether self certification has been requested."`
+	TSANetMember         *bool  `json:"tsanet_member,omitempty" jsonschema_description:"Whether the partner is a TSANet member."`
+}
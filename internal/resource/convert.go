@@ -1,6 +1,9 @@
 package resource
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"reflect"
+)
 
 // JSONConvert aids in the marshaling of the various like types, Generated
 // graphQL types and on-disk types. This is done by round tripping through JSON,
@@ -22,3 +25,19 @@ func JSONConvert[R any](in any) (R, error) {
 
 	return out, nil
 }
+
+// ConvertInput converts in, a declared resource, to R, the input type of the
+// API, as JSONConvert does. Values that are managed by the backend, or that are
+// held only by the declaration, are omitted, as the backend does not accept
+// them as input.
+func ConvertInput[R any](in any) (R, error) {
+	var out R
+
+	value, err := JSONConvert[any](in)
+	if err != nil {
+		return out, err
+	}
+
+	omitOwned(reflect.TypeOf(in), value, ownerBackend, ownerDeclaration)
+	return JSONConvert[R](value)
+}
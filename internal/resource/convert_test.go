@@ -1,6 +1,8 @@
 package resource_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -18,6 +20,35 @@ var _ = Describe("Convert", func() {
 		MyString string `json:"string_key"`
 	}
 
+	When("converting a declared resource to an API input", func() {
+		It("should omit the values managed by the backend", func() {
+			certified := time.Now()
+			input, err := resource.ConvertInput[map[string]any](&resource.Component{
+				ID:                 "c123",
+				OrgID:              1234,
+				Name:               "cname",
+				Badges:             []string{"certified"},
+				PublishedBy:        "isv",
+				CertificationLevel: "Certified",
+				CertificationDate:  &certified,
+				Container:          &resource.ContainerComponent{PID: "p123", Registry: "quay.io"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(Equal(map[string]any{
+				"_id":       "c123",
+				"org_id":    float64(1234),
+				"name":      "cname",
+				"container": map[string]any{"registry": "quay.io"},
+			}))
+		})
+
+		It("should keep the values computed by apply", func() {
+			input, err := resource.ConvertInput[map[string]any](resource.ProductListing{Name: "p", CertProjects: []string{"c123"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input).To(HaveKeyWithValue("cert_projects", ConsistOf("c123")))
+		})
+	})
+
 	When("converting between types through the JSONConvert roundtrip", func() {
 		When("like struct markers are used", func() {
 			var (
@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Differences returns the paths of the values set in declared that are not
+// the same in actual, comparing their JSON representations, e.g.
+// spec.support.url. Values that are not set in declared are not compared, as
+// apply leaves them as they are. Lists are compared element by element, and
+// differ if their lengths differ. Values that are held only by the
+// declaration are not compared, as the backend has no such values.
+func Differences(declared, actual any) ([]string, error) {
+	d, err := JSONConvert[any](declared)
+	if err != nil {
+		return nil, err
+	}
+	if t := reflect.TypeOf(declared); t != nil {
+		omitOwned(t, d, ownerDeclaration)
+	}
+
+	a, err := JSONConvert[any](actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	differences(d, a, "", &paths)
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+func differences(declared, actual any, path string, paths *[]string) {
+	switch d := declared.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			*paths = append(*paths, path)
+			return
+		}
+
+		for key, value := range d {
+			differences(value, a[key], joinFieldPath(path, key), paths)
+		}
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(d) {
+			*paths = append(*paths, path)
+			return
+		}
+
+		for i := range d {
+			differences(d[i], a[i], fmt.Sprintf("%s[%d]", path, i), paths)
+		}
+	default:
+		if !reflect.DeepEqual(declared, actual) {
+			*paths = append(*paths, path)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package resource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Differences", func() {
+	It("should only compare the values that are declared", func() {
+		differences, err := resource.Differences(
+			resource.ProductListing{Name: "p", Descriptions: &resource.ProductListingDescriptions{Short: "short"}},
+			resource.ProductListing{ID: "p123", Name: "p", Type: "container stack", Descriptions: &resource.ProductListingDescriptions{Short: "short", Long: "long"}},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+
+	It("should return the paths of the values that differ, in order", func() {
+		differences, err := resource.Differences(
+			resource.ProductListing{Name: "p", Type: "container stack", Descriptions: &resource.ProductListingDescriptions{Short: "short"}},
+			resource.ProductListing{Name: "q", Type: "container stack"},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(Equal([]string{"descriptions", "name"}))
+	})
+
+	It("should compare lists element by element", func() {
+		differences, err := resource.Differences(
+			map[string]any{"a": []string{"x", "y"}, "b": []string{"x"}},
+			map[string]any{"a": []string{"x", "z"}, "b": []string{"x", "y"}},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(Equal([]string{"a[1]", "b"}))
+	})
+	It("should not compare values that are held only by the declaration", func() {
+		differences, err := resource.Differences(
+			&resource.Component{Name: "chart", HelmChart: &resource.HelmChartComponent{ChartName: "mine", Signed: true}},
+			&resource.Component{ID: "c123", Name: "chart", HelmChart: &resource.HelmChartComponent{ChartName: "mine"}},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(differences).To(BeEmpty())
+	})
+})
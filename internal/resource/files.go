@@ -0,0 +1,231 @@
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+
+	"github.com/opdev/productctl/internal/file"
+)
+
+// DeclarationFiles returns the files holding declarations of the given kinds
+// at path. If path is a file, it is returned as-is, for its reader to report
+// what it holds. If path is a directory, the files file.DeclarationFiles finds
+// within it are returned, skipping those in which no document declares one of
+// kinds, such as values files and the declarations of other kinds, and those
+// that the other files returned read from, such as included files.
+func DeclarationFiles(path string, recursive bool, kinds ...string) ([]string, error) {
+	found, err := file.DeclarationFiles(path, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found) == 1 && found[0] == path {
+		return found, nil
+	}
+
+	files := slices.DeleteFunc(found, func(filename string) bool {
+		return !declaresKind(filename, kinds)
+	})
+
+	referenced := map[string]bool{}
+	for _, filename := range files {
+		// Files that cannot be read are left for their readers to report.
+		refs, _ := ReferencedFiles(filename)
+		for _, ref := range refs {
+			referenced[ref] = true
+		}
+	}
+
+	return slices.DeleteFunc(files, func(filename string) bool {
+		return referenced[filepath.Clean(filename)]
+	}), nil
+}
+
+// declaresKind returns true if any document in filename declares one of kinds.
+// Files that cannot be read or parsed are left for their readers to report.
+func declaresKind(filename string, kinds []string) bool {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return true
+	}
+
+	decoder := yamlv3.NewDecoder(bytes.NewReader(b))
+	for {
+		var root yamlv3.Node
+		err := decoder.Decode(&root)
+		if errors.Is(err, io.EOF) {
+			return false
+		}
+		if err != nil {
+			return true
+		}
+
+		if len(root.Content) == 0 || root.Content[0].Kind != yamlv3.MappingNode {
+			continue
+		}
+
+		if kind := mappingValue(root.Content[0], "kind"); kind != nil && slices.Contains(kinds, kind.Value) {
+			return true
+		}
+	}
+}
+
+// ReferencedFiles returns the files that the declarations in filename read
+// from: the files they include, the declarations of the components they
+// reference by file, and the files those include in turn. filename itself is
+// not returned. Files are returned once each, in the order they are first
+// referenced, with the paths they are read from.
+func ReferencedFiles(filename string) ([]string, error) {
+	var files []string
+	if err := referencedFiles(filename, &files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func referencedFiles(filename string, files *[]string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Dir(filename)
+	add := func(name string) bool {
+		name = filepath.Clean(name)
+		if name == filepath.Clean(filename) || slices.Contains(*files, name) {
+			return false
+		}
+		*files = append(*files, name)
+		return true
+	}
+
+	decoder := yamlv3.NewDecoder(bytes.NewReader(b))
+	for i := 1; ; i++ {
+		var root yamlv3.Node
+		err := decoder.Decode(&root)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: document %d: %w", filename, i, err)
+		}
+
+		if _, err := resolveIncludes(&root, baseDir, func(_ *yamlv3.Node, inc include) {
+			if inc.filename != "" {
+				add(inc.filename)
+			}
+		}); err != nil {
+			return fmt.Errorf("%s: document %d: %w", filename, i, err)
+		}
+
+		if len(root.Content) == 0 {
+			continue
+		}
+
+		with := mappingValue(root.Content[0], "with")
+		if with == nil {
+			continue
+		}
+
+		refs := mappingValue(with, "component_refs")
+		if refs == nil || refs.Kind != yamlv3.SequenceNode {
+			continue
+		}
+
+		for _, ref := range refs.Content {
+			file := mappingValue(ref, "file")
+			if file == nil || file.Kind != yamlv3.ScalarNode || file.Value == "" {
+				continue
+			}
+
+			name := file.Value
+			if !filepath.IsAbs(name) {
+				name = filepath.Join(baseDir, name)
+			}
+
+			if add(name) {
+				if err := referencedFiles(name, files); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// RewriteFileReferences returns the YAML documents b with the name of each
+// file they read from, as ReferencedFiles finds them, replaced by the name
+// rewrite returns for it. Names are those written in b, which are relative
+// to the directory of the file holding b unless they are absolute. Documents
+// in which no name changes retain their original content.
+func RewriteFileReferences(b []byte, rewrite func(name string) (string, error)) ([]byte, error) {
+	var out bytes.Buffer
+	for i, doc := range splitDocuments[struct{}](b) {
+		out.Write(doc.separator)
+
+		empty, err := isEmptyDocument(doc.raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if empty {
+			out.Write(doc.raw)
+			continue
+		}
+
+		var root yamlv3.Node
+		if err := yamlv3.Unmarshal(doc.raw, &root); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		var names []*yamlv3.Node
+		walkValues(&root, "", func(node *yamlv3.Node, _ string) {
+			if node.Kind != yamlv3.MappingNode {
+				return
+			}
+			if file := mappingValue(node, includeDirective); file != nil && file.Kind == yamlv3.ScalarNode {
+				names = append(names, file)
+			}
+		})
+		if with := mappingValue(root.Content[0], "with"); root.Content[0].Kind == yamlv3.MappingNode && with != nil {
+			if refs := mappingValue(with, "component_refs"); refs != nil && refs.Kind == yamlv3.SequenceNode {
+				for _, ref := range refs.Content {
+					if file := mappingValue(ref, "file"); file != nil && file.Kind == yamlv3.ScalarNode && file.Value != "" {
+						names = append(names, file)
+					}
+				}
+			}
+		}
+
+		changed := false
+		for _, name := range names {
+			rewritten, err := rewrite(name.Value)
+			if err != nil {
+				return nil, fmt.Errorf("document %d: %w", i+1, err)
+			}
+			if rewritten != name.Value {
+				name.SetString(rewritten)
+				changed = true
+			}
+		}
+
+		if !changed {
+			out.Write(doc.raw)
+			continue
+		}
+
+		encoded, err := encodeNode(&root)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(encoded)
+	}
+
+	return out.Bytes(), nil
+}
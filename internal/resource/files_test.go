@@ -0,0 +1,147 @@
+package resource_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("ReferencedFiles", func() {
+	var dir string
+
+	write := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(filename), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		write("product/my.product.yaml", `kind: ProductListing
+spec:
+  name: mine
+  descriptions:
+    long: {$file: long.md}
+  faqs: {$file: ../shared/faqs.yaml}
+with:
+  component_refs:
+  - _id: c123
+  - file: ../components/operator.yaml
+---
+kind: ProductListing
+spec:
+  name: other
+  descriptions:
+    long: {$file: long.md}
+`)
+		write("product/long.md", "# Long\n")
+		write("shared/faqs.yaml", "- question: Supported?\n  answer: {$file: answer.html}\n")
+		write("shared/answer.html", "<p>Yes</p>\n")
+		write("components/operator.yaml", "kind: Component\nspec:\n  name: operator\n  container:\n    repository_description: {$file: repository.txt}\n")
+		write("components/repository.txt", "An operator\n")
+	})
+
+	It("should return every file read by the declarations, once each", func() {
+		files, err := resource.ReferencedFiles(filepath.Join(dir, "product", "my.product.yaml"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(Equal([]string{
+			filepath.Join(dir, "product", "long.md"),
+			filepath.Join(dir, "shared", "answer.html"),
+			filepath.Join(dir, "shared", "faqs.yaml"),
+			filepath.Join(dir, "components", "operator.yaml"),
+			filepath.Join(dir, "components", "repository.txt"),
+		}))
+	})
+
+	It("should report files that cannot be read", func() {
+		Expect(os.Remove(filepath.Join(dir, "components", "repository.txt"))).To(Succeed())
+		_, err := resource.ReferencedFiles(filepath.Join(dir, "product", "my.product.yaml"))
+		Expect(err).To(MatchError(resource.ErrInvalidInclude))
+	})
+})
+
+var _ = Describe("DeclarationFiles", func() {
+	var dir string
+
+	write := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(filename), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		write("my.product.yaml", "kind: ProductListing\nspec:\n  name: ${NAME}\n  faqs: {$file: nested/faqs.yaml}\nwith:\n  component_refs:\n  - file: base.component.yaml\n")
+		write("base.component.yaml", "kind: Component\nspec:\n  name: base-image\n  container:\n    repository_description: {$file: nested/repository.yaml}\n")
+		write("values.yaml", "NAME: mine\n")
+		write("nested/faqs.yaml", "- question: Supported?\n  answer: Yes\n")
+		write("nested/repository.yaml", "kind: Component\n")
+		write("nested/other.product.yaml", "kind: ProductListing\nspec:\n  name: other\n")
+	})
+
+	It("should return only the files declaring the kinds requested, leaving out the files they read from", func() {
+		files, err := resource.DeclarationFiles(dir, true, resource.KindProductListing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(Equal([]string{
+			filepath.Join(dir, "my.product.yaml"),
+			filepath.Join(dir, "nested", "other.product.yaml"),
+		}))
+
+		files, err = resource.DeclarationFiles(dir, true, resource.KindComponent)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(Equal([]string{filepath.Join(dir, "base.component.yaml")}))
+	})
+
+	It("should return a file as-is", func() {
+		filename := filepath.Join(dir, "values.yaml")
+		files, err := resource.DeclarationFiles(filename, false, resource.KindProductListing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(Equal([]string{filename}))
+	})
+
+	It("should leave files that cannot be parsed for their readers to report", func() {
+		write("broken.yaml", "kind: [\n")
+		files, err := resource.DeclarationFiles(dir, false, resource.KindProductListing)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(ContainElement(filepath.Join(dir, "broken.yaml")))
+	})
+})
+
+var _ = Describe("RewriteFileReferences", func() {
+	It("should rewrite the included files and the component declarations, leaving other documents as they were", func() {
+		b := []byte(`kind: ProductListing
+spec:
+  name: mine
+  descriptions:
+    long: {$file: /abs/long.md}
+with:
+  component_refs:
+  - _id: c123
+  - file: /abs/operator.yaml
+---
+# untouched
+kind: ProductListing
+spec: {name: other}
+`)
+
+		rewritten, err := resource.RewriteFileReferences(b, func(name string) (string, error) {
+			return filepath.Base(name), nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(rewritten)).To(ContainSubstring("$file: long.md"))
+		Expect(string(rewritten)).To(ContainSubstring("file: operator.yaml"))
+		Expect(string(rewritten)).To(HaveSuffix("---\n# untouched\nkind: ProductListing\nspec: {name: other}\n"))
+	})
+
+	It("should report the names that cannot be rewritten", func() {
+		_, err := resource.RewriteFileReferences([]byte("kind: ProductListing\nspec:\n  faqs: {$file: ../faqs.yaml}\n"), func(name string) (string, error) {
+			return "", errors.New("outside")
+		})
+		Expect(err).To(MatchError(ContainSubstring("document 1: outside")))
+	})
+})
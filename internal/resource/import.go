@@ -0,0 +1,112 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/opdev/productctl/internal/markup"
+)
+
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportFAQs reads FAQs from the Markdown document s, where each second level
+// heading is a question, e.g. "## Is it supported?", and the Markdown under it
+// is its answer, rendered to HTML. Each FAQ must satisfy the constraints
+// JSONSchema declares for FAQs, e.g. the length of answers.
+func ImportFAQs(s string) ([]FAQ, error) {
+	return importSections(s, "FAQ", func(heading, body string) FAQ {
+		return FAQ{Question: heading, Answer: body}
+	})
+}
+
+// ImportFeatures reads features from the Markdown document s, as ImportFAQs
+// does, where each heading is the title of a feature and the Markdown under it
+// its description.
+func ImportFeatures(s string) ([]ProductListingFeature, error) {
+	return importSections(s, "ProductListingFeature", func(heading, body string) ProductListingFeature {
+		return ProductListingFeature{Title: heading, Description: body}
+	})
+}
+
+// importSections builds an entry from the heading and the rendered body of
+// each section of the Markdown document s, and checks it against the schema
+// definition.
+func importSections[T any](s, definition string, entry func(heading, body string) T) ([]T, error) {
+	sections := markup.Sections(s)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("%w: no second level headings, e.g. \"## Heading\", were found", ErrInvalidImport)
+	}
+
+	schema := JSONSchema()
+	seen := map[string]bool{}
+	entries := make([]T, 0, len(sections))
+	var errs []error
+	for _, section := range sections {
+		if section.Heading == "" {
+			errs = append(errs, fmt.Errorf("%w: headings must not be empty", ErrInvalidImport))
+			continue
+		}
+
+		if seen[section.Heading] {
+			errs = append(errs, fmt.Errorf("%w: %q: appears more than once", ErrInvalidImport, section.Heading))
+			continue
+		}
+		seen[section.Heading] = true
+
+		body := ""
+		if section.Body != "" {
+			rendered, err := markup.ToHTML(section.Body)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %q: %w", ErrInvalidImport, section.Heading, err))
+				continue
+			}
+			body = rendered
+		}
+
+		e := entry(section.Heading, body)
+		if err := validateDefinition(schema, definition, e); err != nil {
+			errs = append(errs, fmt.Errorf("%w: %q: %w", ErrInvalidImport, section.Heading, err))
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return entries, nil
+}
+
+// MergeFAQs returns existing with each of imported replacing the FAQ with the
+// same question, or added after them if there is none. Other FAQs are kept as
+// they are, in their order. It also returns the number of FAQs added and
+// replaced.
+func MergeFAQs(existing, imported []FAQ) (merged []FAQ, added, replaced int) {
+	return mergeBy(existing, imported, func(f FAQ) string { return f.Question })
+}
+
+// MergeFeatures returns existing with each of imported replacing the feature
+// with the same title, as MergeFAQs does.
+func MergeFeatures(existing, imported []ProductListingFeature) (merged []ProductListingFeature, added, replaced int) {
+	return mergeBy(existing, imported, func(f ProductListingFeature) string { return f.Title })
+}
+
+func mergeBy[T any](existing, imported []T, key func(T) string) (merged []T, added, replaced int) {
+	merged = append([]T{}, existing...)
+	for _, entry := range imported {
+		i := slices.IndexFunc(merged, func(e T) bool { return key(e) == key(entry) })
+		if i < 0 {
+			merged = append(merged, entry)
+			added++
+			continue
+		}
+
+		merged[i] = entry
+		replaced++
+	}
+
+	return merged, added, replaced
+}
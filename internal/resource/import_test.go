@@ -0,0 +1,61 @@
+package resource_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Importing from Markdown", func() {
+	It("should import FAQs with answers rendered as HTML", func() {
+		faqs, err := resource.ImportFAQs("# FAQ\n\n## Is it supported?\n\nYes, *always*.\n\n## Is it free?\n\nNo.\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(faqs).To(Equal([]resource.FAQ{
+			{Question: "Is it supported?", Answer: "<p>Yes, <em>always</em>.</p>"},
+			{Question: "Is it free?", Answer: "<p>No.</p>"},
+		}))
+	})
+
+	It("should import features", func() {
+		features, err := resource.ImportFeatures("## Fast\n\nIt is fast.\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(features).To(Equal([]resource.ProductListingFeature{{Title: "Fast", Description: "<p>It is fast.</p>"}}))
+	})
+
+	DescribeTable("should reject documents that cannot be imported",
+		func(document, message string) {
+			_, err := resource.ImportFeatures(document)
+			Expect(err).To(MatchError(resource.ErrInvalidImport))
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("without headings", "# Features\n\nNone yet.\n", "no second level headings"),
+		Entry("with repeated headings", "## Fast\n\nYes.\n\n## Fast\n\nVery.\n", `"Fast": appears more than once`),
+		Entry("with titles that are too long", "## "+strings.Repeat("a", 61)+"\n\nYes.\n", "title: must be at most 60 characters long"),
+		Entry("with descriptions that are too long", "## Fast\n\n"+strings.Repeat("a", 1000)+"\n", "description: must be at most 1000 characters long"),
+		Entry("with raw HTML", "## Fast\n\n<script>alert(1)</script>\n", "raw HTML is not supported"),
+	)
+
+	It("should merge by question, keeping unrelated entries", func() {
+		existing := []resource.FAQ{
+			{Question: "Is it supported?", Answer: "<p>Maybe.</p>"},
+			{Question: "Who maintains it?", Answer: "<p>We do.</p>"},
+		}
+		imported := []resource.FAQ{
+			{Question: "Is it free?", Answer: "<p>No.</p>"},
+			{Question: "Is it supported?", Answer: "<p>Yes.</p>"},
+		}
+
+		merged, added, replaced := resource.MergeFAQs(existing, imported)
+		Expect(merged).To(Equal([]resource.FAQ{
+			{Question: "Is it supported?", Answer: "<p>Yes.</p>"},
+			{Question: "Who maintains it?", Answer: "<p>We do.</p>"},
+			{Question: "Is it free?", Answer: "<p>No.</p>"},
+		}))
+		Expect(added).To(Equal(1))
+		Expect(replaced).To(Equal(1))
+		Expect(existing[0].Answer).To(Equal("<p>Maybe.</p>"))
+	})
+})
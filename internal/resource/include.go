@@ -0,0 +1,476 @@
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/invopop/jsonschema"
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/markup"
+)
+
+var (
+	ErrInvalidInclude  = errors.New("invalid include")
+	ErrInvalidMarkdown = errors.New("invalid markdown")
+)
+
+const (
+	// includeDirective is the key of the mapping that includes the content
+	// of a file in place of a value, e.g. {$file: quickstart.html}.
+	includeDirective = "$file"
+	// formatOption is the key that sets the format of an included file, or
+	// of a value written in place, e.g. {format: markdown, value: "*Yes*"}.
+	formatOption = "format"
+	// valueOption is the key holding a value written in place in a format
+	// other than the one the field holds.
+	valueOption = "value"
+)
+
+// Formats of included files and values.
+const (
+	formatText     = "text"
+	formatYAML     = "yaml"
+	formatMarkdown = "markdown"
+)
+
+// htmlFields returns the paths of the fields of declarations that hold HTML,
+// i.e. those of the html format, which may be written in Markdown. Elements of
+// lists are written as [].
+var htmlFields = sync.OnceValue(func() []string {
+	schema := JSONSchema()
+	v := &schemaValidator{root: schema}
+
+	var paths []string
+	var visit func(s *jsonschema.Schema, path string, refs []string)
+	visit = func(s *jsonschema.Schema, path string, refs []string) {
+		if s != nil && s.Ref != "" {
+			if slices.Contains(refs, s.Ref) {
+				return
+			}
+			refs = append(refs, s.Ref)
+		}
+
+		s = v.resolve(s)
+		if s == nil {
+			return
+		}
+
+		if s.Format == formatHTML {
+			paths = append(paths, path)
+		}
+
+		if s.Items != nil {
+			visit(s.Items, path+"[]", refs)
+		}
+
+		if s.Properties != nil {
+			for pair := s.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				visit(pair.Value, joinFieldPath(path, pair.Key), refs)
+			}
+		}
+	}
+	visit(schema, "", nil)
+
+	return paths
+})
+
+// listIndex matches the indices of list elements in paths.
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// isHTMLField returns true if the value at path holds HTML.
+func isHTMLField(path string) bool {
+	return slices.Contains(htmlFields(), listIndex.ReplaceAllString(path, "[]"))
+}
+
+// WithBaseDir resolves the files included by declarations relative to dir,
+// which is typically the directory of the file the declarations are read from.
+// Otherwise, included files are resolved relative to the working directory.
+//
+// A value of a declaration may be replaced by a mapping holding only $file,
+// the name of the file to include in its place. YAML files (.yaml or .yml) are
+// included as the YAML value they hold, which may itself include other files
+// relative to its own directory. Any other file is included as a string.
+//
+// Fields that hold HTML may instead be written in Markdown, which is rendered
+// to the HTML the catalog accepts as the declaration is read. Markdown files
+// (.md or .markdown) are rendered as they are included, as are files included
+// with format: markdown, e.g. {$file: answer.txt, format: markdown}. Markdown
+// may also be written in place, e.g. {format: markdown, value: "**Yes**"}.
+// Variables referenced by Markdown are interpolated before it is rendered.
+func WithBaseDir(dir string) ReadOption {
+	return func(o *readOptions) {
+		o.baseDir = dir
+	}
+}
+
+// include is a file included in a declaration, or a value written in place in
+// another format.
+type include struct {
+	// reference is the mapping that included the file, or held the value.
+	reference yamlv3.Node
+	// filename is the file included, or empty for values written in place.
+	filename string
+	format   string
+	raw      []byte
+	// rendered is the HTML Markdown was rendered as.
+	rendered string
+}
+
+// formatOf returns the format a file is included in by default, by its
+// extension.
+func formatOf(filename string) string {
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".md", ".markdown":
+		return formatMarkdown
+	}
+
+	return formatText
+}
+
+// includeReference returns the file included by node, or the value node
+// holds in place, if node is an include reference. Markdown is only accepted
+// if isHTML is true.
+func includeReference(node *yamlv3.Node, isHTML bool) (inc include, ok bool, err error) {
+	if node.Kind != yamlv3.MappingNode {
+		return include{}, false, nil
+	}
+
+	file := mappingValue(node, includeDirective)
+	value := mappingValue(node, valueOption)
+	format := mappingValue(node, formatOption)
+	switch {
+	case file == nil && (!isHTML || value == nil || format == nil):
+		// Values in place are only recognized in fields that hold HTML,
+		// which are otherwise never mappings.
+		return include{}, false, nil
+	case file != nil && (file.Kind != yamlv3.ScalarNode || file.Value == ""):
+		return include{}, true, fmt.Errorf("%s must be the name of a file", includeDirective)
+	case file != nil && value != nil:
+		return include{}, true, fmt.Errorf("%s and %s may not be used together", includeDirective, valueOption)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i].Value; key != includeDirective && key != formatOption && key != valueOption {
+			return include{}, true, fmt.Errorf("unknown include option %q", key)
+		}
+	}
+
+	inc = include{reference: *node}
+	if file != nil {
+		inc.filename = file.Value
+		inc.format = formatOf(file.Value)
+	} else {
+		if value.Kind != yamlv3.ScalarNode {
+			return include{}, true, fmt.Errorf("%s must be a string", valueOption)
+		}
+		inc.raw = []byte(value.Value)
+	}
+
+	if format != nil {
+		switch format.Value {
+		case formatText, formatMarkdown:
+			inc.format = format.Value
+		default:
+			return include{}, true, fmt.Errorf("unknown %s %q, expected %s or %s", formatOption, format.Value, formatText, formatMarkdown)
+		}
+	}
+
+	if inc.format == formatMarkdown && !isHTML {
+		return include{}, true, errors.New("markdown is only supported in fields that accept HTML")
+	}
+
+	return inc, true, nil
+}
+
+// resolveIncludes replaces each include reference in the YAML document root
+// with the content of the file it references, relative to baseDir. record, if
+// not nil, is called with each node replaced and the file it was replaced
+// with. It returns true if any include was resolved.
+func resolveIncludes(root *yamlv3.Node, baseDir string, record func(node *yamlv3.Node, inc include)) (bool, error) {
+	return resolveIncludesFrom(root, "", baseDir, nil, record)
+}
+
+// resolveIncludesFrom is resolveIncludes, where root is the value at path,
+// and chain holds the files whose content root is, which may not be included
+// again.
+func resolveIncludesFrom(root *yamlv3.Node, path, baseDir string, chain []string, record func(node *yamlv3.Node, inc include)) (bool, error) {
+	resolved := false
+	var errs []error
+	walkValues(root, path, func(node *yamlv3.Node, path string) {
+		inc, ok, err := includeReference(node, isHTMLField(path))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s: %w", ErrInvalidInclude, path, err))
+			return
+		}
+
+		if !ok {
+			return
+		}
+
+		name := inc.filename
+		if name != "" {
+			if !filepath.IsAbs(inc.filename) {
+				inc.filename = filepath.Join(baseDir, inc.filename)
+			}
+
+			if slices.Contains(chain, inc.filename) {
+				errs = append(errs, fmt.Errorf("%w: %s: %s includes itself", ErrInvalidInclude, path, name))
+				return
+			}
+
+			inc.raw, err = os.ReadFile(inc.filename)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %w", ErrInvalidInclude, path, err))
+				return
+			}
+		}
+
+		content := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: string(inc.raw)}
+		switch inc.format {
+		case formatMarkdown:
+			inc.rendered, err = markup.ToHTML(string(inc.raw))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %w", ErrInvalidMarkdown, path, err))
+				return
+			}
+			content.Value = inc.rendered
+		case formatYAML:
+			var document yamlv3.Node
+			if err := yamlv3.Unmarshal(inc.raw, &document); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %s: %w", ErrInvalidInclude, path, name, err))
+				return
+			}
+
+			if len(document.Content) == 0 {
+				errs = append(errs, fmt.Errorf("%w: %s: %s is empty", ErrInvalidInclude, path, name))
+				return
+			}
+
+			content = document.Content[0]
+			if _, err := resolveIncludesFrom(content, path, filepath.Dir(inc.filename), append(chain, inc.filename), record); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+		}
+
+		*node = *content
+		if record != nil {
+			record(node, inc)
+		}
+		resolved = true
+	})
+
+	return resolved, errors.Join(errs...)
+}
+
+// restoreSources returns replacement, a YAML document replacing the document
+// original, with each value that original read from elsewhere written as it
+// was in original: values that were variable references are restored to
+// those references, and the content of included files is replaced with the
+// references that included them. Values are matched by their path, where the
+// elements of lists of named elements are matched by name.
+//
+// The new content of each included file that changed is recorded in updates,
+// by filename. Files included as strings that contain variable references are
+// never updated, so that interpolated values are not written to them. Changed
+// values that were written in Markdown are converted back to Markdown, and
+// are left as they were if they cannot be. Names that are variable references
+// are matched by their values from lookup, the variables original was read
+// with, which may be nil.
+func restoreSources(original, replacement []byte, baseDir string, lookup VariableLookup, updates map[string][]byte) ([]byte, error) {
+	var originalRoot yamlv3.Node
+	if err := yamlv3.Unmarshal(original, &originalRoot); err != nil {
+		return nil, err
+	}
+
+	includes := map[*yamlv3.Node]include{}
+	if _, err := resolveIncludes(&originalRoot, baseDir, func(node *yamlv3.Node, inc include) {
+		includes[node] = inc
+	}); err != nil {
+		return nil, err
+	}
+
+	templates := map[string]*yamlv3.Node{}
+	includePaths := map[string]include{}
+	originalKeys := func(sequence *yamlv3.Node) []string {
+		return interpolatedNameKeys(sequence, lookup)
+	}
+	walkNodes(&originalRoot, "", originalKeys, func(node *yamlv3.Node, path string) {
+		if inc, ok := includes[node]; ok {
+			includePaths[path] = inc
+			return
+		}
+
+		if node.Kind == yamlv3.ScalarNode && hasTemplate(node.Value) {
+			templates[path] = node
+		}
+	})
+
+	if len(templates) == 0 && len(includePaths) == 0 {
+		return replacement, nil
+	}
+
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(replacement, &root); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	walkNodes(&root, "", nameKeys, func(node *yamlv3.Node, path string) {
+		if inc, ok := includePaths[path]; ok {
+			reference, err := inc.update(node, updates)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+			*node = reference
+			return
+		}
+
+		if template, ok := templates[path]; ok && node.Kind == yamlv3.ScalarNode {
+			node.Value = template.Value
+			node.Tag = template.Tag
+			node.Style = template.Style
+		}
+	})
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return encodeNode(&root)
+}
+
+// update records the content of the included file in updates if node, the
+// value that replaces it, differs from it. It returns the reference to write
+// in place of node, which holds the new value of values written in place.
+func (inc include) update(node *yamlv3.Node, updates map[string][]byte) (yamlv3.Node, error) {
+	raw := string(inc.raw)
+	content := node.Value
+	switch inc.format {
+	case formatYAML:
+		b, err := encodeNode(node)
+		if err != nil {
+			return inc.reference, err
+		}
+
+		changed, err := differentYAML(inc.raw, b)
+		if err != nil || !changed {
+			return inc.reference, err
+		}
+
+		updates[inc.filename] = append(leadingComments(inc.raw), b...)
+		return inc.reference, nil
+	case formatMarkdown:
+		if node.Kind != yamlv3.ScalarNode || hasTemplate(raw) || content == inc.rendered {
+			return inc.reference, nil
+		}
+
+		markdown, ok := markup.ToMarkdown(content)
+		if !ok {
+			return inc.reference, nil
+		}
+		content = markdown
+	default:
+		if node.Kind != yamlv3.ScalarNode || hasTemplate(raw) || strings.TrimRight(content, "\n") == strings.TrimRight(raw, "\n") {
+			return inc.reference, nil
+		}
+	}
+
+	if inc.filename == "" {
+		reference := inc.reference
+		reference.Content = slices.Clone(reference.Content)
+		value := &yamlv3.Node{}
+		value.SetString(content)
+		setMappingValue(&reference, valueOption, value)
+		return reference, nil
+	}
+
+	if strings.HasSuffix(raw, "\n") && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	updates[inc.filename] = []byte(content)
+	return inc.reference, nil
+}
+
+// nameKeys identifies the elements of sequence by name if they all have
+// distinct names, and by their index otherwise.
+func nameKeys(sequence *yamlv3.Node) []string {
+	return interpolatedNameKeys(sequence, nil)
+}
+
+// interpolatedNameKeys identifies the elements of sequence as nameKeys does,
+// after interpolating variables from lookup into names that are variable
+// references, if lookup is not nil. Elements are identified by their index if
+// any such name cannot be interpolated.
+func interpolatedNameKeys(sequence *yamlv3.Node, lookup VariableLookup) []string {
+	keys := make([]string, 0, len(sequence.Content))
+	for _, element := range sequence.Content {
+		var name *yamlv3.Node
+		if element.Kind == yamlv3.MappingNode {
+			name = mappingValue(element, "name")
+		}
+
+		key := ""
+		if name != nil && name.Kind == yamlv3.ScalarNode && name.Value != "" {
+			value := name.Value
+			if lookup != nil && hasTemplate(value) {
+				expanded, messages := expand(value, lookup)
+				if len(messages) > 0 {
+					return indexKeys(sequence)
+				}
+				value = expanded
+			}
+			key = fmt.Sprintf("[name=%s]", value)
+		}
+
+		if key == "" || slices.Contains(keys, key) {
+			return indexKeys(sequence)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// differentYAML returns true if the YAML documents a and b hold different
+// values.
+func differentYAML(a, b []byte) (bool, error) {
+	aj, err := yaml.YAMLToJSON(a)
+	if err != nil {
+		return false, err
+	}
+
+	bj, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return false, err
+	}
+
+	return !bytes.Equal(aj, bj), nil
+}
+
+// encodeNode encodes node as YAML, formatted as declarations are.
+func encodeNode(node *yamlv3.Node) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yamlv3.NewEncoder(&out)
+	encoder.SetIndent(2)
+	encoder.CompactSeqIndent()
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,198 @@
+package resource_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Includes", func() {
+	const listing = `# modeline
+kind: ProductListing
+spec:
+  name: my product
+  descriptions:
+    long: {$file: long.html}
+  quick_start_configuration:
+    instructions: {$file: quickstart.html}
+with:
+  components:
+  - {$file: components/api.yaml}
+  - name: inline
+    type: Containers
+`
+
+	var dir string
+
+	write := func(name, content string) {
+		filename := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(filename), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filename, []byte(content), 0o644)).To(Succeed())
+	}
+
+	read := func(content string) (*resource.DeclarationStream, error) {
+		return resource.ReadDeclarationStream(bytes.NewBufferString(content), resource.WithBaseDir(dir), resource.WithStrict(true))
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		write("long.html", "<p>A long description.</p>\n")
+		write("quickstart.html", "<p>Install ${PRODUCT}.</p>\n")
+		write("components/api.yaml", "# the API component\nname: api\ntype: Containers\ncontainer:\n  short_description: {$file: api.html}\n")
+		write("components/api.html", "The API.")
+		write("loop.yaml", "{$file: loop.yaml}\n")
+	})
+
+	It("should include files relative to the declaration and to each other", func() {
+		stream, err := read(listing)
+		Expect(err).ToNot(HaveOccurred())
+
+		declaration := stream.Declarations()[0]
+		Expect(declaration.Spec.Descriptions.Long).To(Equal("<p>A long description.</p>\n"))
+		Expect(declaration.Spec.QuickStartConfiguration.Instructions).To(Equal("<p>Install ${PRODUCT}.</p>\n"))
+		Expect(declaration.With.Components).To(HaveLen(2))
+		Expect(declaration.With.Components[0].Name).To(Equal("api"))
+		Expect(declaration.With.Components[0].Container.ShortDescription).To(Equal("The API."))
+	})
+
+	It("should interpolate variables into included files", func() {
+		stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(listing), resource.WithBaseDir(dir), resource.WithVariables(func(name string) (string, bool) {
+			return "my product", name == "PRODUCT"
+		}))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stream.Declarations()[0].Spec.QuickStartConfiguration.Instructions).To(Equal("<p>Install my product.</p>\n"))
+	})
+
+	DescribeTable("should reject invalid includes",
+		func(content, message string) {
+			_, err := read(content)
+			Expect(err).To(MatchError(resource.ErrInvalidInclude))
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("with a missing file", "kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: missing.html}\n", "no such file"),
+		Entry("with unknown options", "kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: long.html, other: value}\n", "unknown include option"),
+		Entry("without a filename", "kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: [long.html]}\n", "must be the name of a file"),
+		Entry("that include themselves", "kind: ProductListing\nwith:\n  components:\n  - {$file: loop.yaml}\n", "includes itself"),
+		Entry("with unknown formats", "kind: ProductListing\nspec:\n  descriptions:\n    long: {$file: long.html, format: rst}\n", "unknown format"),
+		Entry("with markdown in fields that do not accept HTML", "kind: ProductListing\nspec:\n  name: {$file: answer.md}\n", "only supported in fields that accept HTML"),
+	)
+
+	Context("written in Markdown", func() {
+		const markdownListing = `kind: ProductListing
+spec:
+  name: my product
+  descriptions:
+    long: {$file: long.txt, format: markdown}
+  faqs:
+  - question: Is it supported?
+    answer: {$file: answer.md}
+  features:
+  - title: Fast
+    description:
+      format: markdown
+      value: It is *fast*.
+`
+
+		BeforeEach(func() {
+			write("long.txt", "# About\n\nA **long** description.\n")
+			write("answer.md", "Yes, see [the docs](https://example.com).\n")
+		})
+
+		It("should render Markdown to HTML", func() {
+			stream, err := read(markdownListing)
+			Expect(err).ToNot(HaveOccurred())
+
+			declaration := stream.Declarations()[0]
+			Expect(declaration.Spec.Descriptions.Long).To(Equal("<h1>About</h1>\n<p>A <strong>long</strong> description.</p>"))
+			Expect(declaration.Spec.FAQs[0].Answer).To(Equal(`<p>Yes, see <a href="https://example.com">the docs</a>.</p>`))
+			Expect(declaration.Spec.Features[0].Description).To(Equal("<p>It is <em>fast</em>.</p>"))
+		})
+
+		It("should interpolate variables into Markdown before rendering it", func() {
+			write("answer.md", "Yes, see [the docs](${DOCS_URL}) by ${VENDOR}.\n")
+			values := resource.Values{"DOCS_URL": "https://example.com/docs", "VENDOR": "*Example*"}
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(markdownListing), resource.WithBaseDir(dir), resource.WithVariables(func(name string) (string, bool) {
+				v, ok := values[name]
+				return v, ok
+			}))
+			Expect(err).ToNot(HaveOccurred())
+
+			declaration := stream.Declarations()[0]
+			Expect(declaration.Spec.FAQs[0].Answer).To(Equal(`<p>Yes, see <a href="https://example.com/docs">the docs</a> by <em>Example</em>.</p>`))
+		})
+
+		It("should reject Markdown the catalog cannot display", func() {
+			write("answer.md", "Yes, <script>alert(1)</script>\n")
+			_, err := read(markdownListing)
+			Expect(err).To(MatchError(resource.ErrInvalidMarkdown))
+			Expect(err.Error()).To(ContainSubstring("spec.faqs[0].answer"))
+		})
+
+		It("should write changed values back as Markdown where possible", func() {
+			stream, err := read(markdownListing)
+			Expect(err).ToNot(HaveOccurred())
+
+			applied := stream.Declarations()[0]
+			applied.Spec.FAQs[0].Answer = "<p>No.</p>"
+			applied.Spec.Features[0].Description = "<p>It is <strong>very</strong> fast.</p>"
+			applied.Spec.Descriptions.Long = "<p><u>Underlined</u></p>"
+			Expect(stream.Replace(0, applied)).To(Succeed())
+
+			written := string(stream.Bytes())
+			Expect(written).To(ContainSubstring("long: {$file: long.txt, format: markdown}"))
+			Expect(written).To(ContainSubstring("answer: {$file: answer.md}"))
+			Expect(written).To(ContainSubstring("value: It is **very** fast."))
+
+			updates := stream.IncludeUpdates()
+			Expect(updates).To(HaveKeyWithValue(filepath.Join(dir, "answer.md"), []byte("No.\n")))
+			// Values Markdown cannot express are left as they were.
+			Expect(updates).ToNot(HaveKey(filepath.Join(dir, "long.txt")))
+		})
+	})
+
+	It("should keep includes in place as declarations are replaced, updating the files that changed", func() {
+		stream, err := read(listing)
+		Expect(err).ToNot(HaveOccurred())
+
+		// The backend returns components in its own order, with IDs.
+		applied := stream.Declarations()[0]
+		applied.Spec.ID = "listing-id"
+		applied.With.Components[0], applied.With.Components[1] = applied.With.Components[1], applied.With.Components[0]
+		applied.With.Components[0].ID = "inline-id"
+		applied.With.Components[1].ID = "api-id"
+		applied.Spec.Descriptions.Long = "<p>A revised description.</p>"
+		applied.Spec.QuickStartConfiguration.Instructions = "<p>Install my product.</p>\n"
+		Expect(stream.Replace(0, applied)).To(Succeed())
+
+		written := string(stream.Bytes())
+		Expect(written).To(HavePrefix("# modeline\n"))
+		Expect(written).To(ContainSubstring("_id: listing-id"))
+		Expect(written).To(ContainSubstring("long: {$file: long.html}"))
+		Expect(written).To(ContainSubstring("instructions: {$file: quickstart.html}"))
+		Expect(written).To(ContainSubstring("- {$file: components/api.yaml}"))
+		Expect(written).To(ContainSubstring("_id: inline-id"))
+		Expect(written).ToNot(ContainSubstring("api-id"))
+
+		updates := stream.IncludeUpdates()
+		Expect(updates).To(HaveKeyWithValue(filepath.Join(dir, "long.html"), []byte("<p>A revised description.</p>\n")))
+		Expect(updates).To(HaveKey(filepath.Join(dir, "components/api.yaml")))
+		Expect(string(updates[filepath.Join(dir, "components/api.yaml")])).To(HavePrefix("# the API component\n"))
+		Expect(string(updates[filepath.Join(dir, "components/api.yaml")])).To(ContainSubstring("_id: api-id"))
+		Expect(string(updates[filepath.Join(dir, "components/api.yaml")])).To(ContainSubstring("short_description: {$file: api.html}"))
+		// Files holding variable references are never updated, and files
+		// that did not change are not updated.
+		Expect(updates).ToNot(HaveKey(filepath.Join(dir, "quickstart.html")))
+		Expect(updates).ToNot(HaveKey(filepath.Join(dir, "components/api.html")))
+
+		Expect(stream.IncludeUpdates()).To(BeEmpty())
+
+		reread, err := read(written)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reread.Declarations()[0].With.Components[1].Name).To(Equal("api"))
+	})
+})
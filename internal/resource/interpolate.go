@@ -0,0 +1,364 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+var (
+	ErrUndefinedVariable = errors.New("undefined variable")
+	ErrInvalidTemplate   = errors.New("invalid template")
+	ErrInvalidValues     = errors.New("invalid values")
+)
+
+// VariableLookup returns the value of the variable name, and whether it is
+// defined.
+type VariableLookup func(name string) (value string, ok bool)
+
+// WithVariables interpolates variables into the values of declarations using
+// lookup. Variables are referenced as follows:
+//
+//	${NAME}            the value of NAME, which must be defined
+//	${NAME:-default}   the value of NAME, or default if it is undefined or empty
+//	${NAME:?message}   the value of NAME, failing with message if it is undefined or empty
+//	$${                a literal ${
+//
+// Every variable that is not defined is reported in an *InterpolationError.
+// Only values are interpolated, never field names. Values of declarations
+// read without this option are left as they are.
+func WithVariables(lookup VariableLookup) ReadOption {
+	return func(o *readOptions) {
+		o.variables = lookup
+	}
+}
+
+// TemplateProblem is a variable reference in a declaration that could not be
+// interpolated.
+type TemplateProblem struct {
+	// Path is the location of the value within the declaration.
+	Path    string
+	Line    int
+	Column  int
+	Message string
+
+	err error
+}
+
+// InterpolationError reports every variable reference that could not be
+// interpolated into a declaration.
+type InterpolationError struct {
+	Problems []TemplateProblem
+}
+
+func (e *InterpolationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, "declaration could not be interpolated:")
+	for _, p := range e.Problems {
+		lines = append(lines, fmt.Sprintf("  line %d, column %d: %s: %s", p.Line, p.Column, p.Path, p.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Is returns true for ErrUndefinedVariable and ErrInvalidTemplate if any
+// problem is of that sort.
+func (e *InterpolationError) Is(target error) bool {
+	return slices.ContainsFunc(e.Problems, func(p TemplateProblem) bool {
+		return p.err == target
+	})
+}
+
+// offsetLines shifts the reported line numbers by n, for documents that are
+// part of a larger stream.
+func (e *InterpolationError) offsetLines(n int) {
+	for i := range e.Problems {
+		e.Problems[i].Line += n
+	}
+}
+
+// variableName matches the names of variables. Names may contain dots, to
+// refer to nested values of values files.
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_-]+)*$`)
+
+// hasTemplate returns true if s contains a variable reference or escape.
+func hasTemplate(s string) bool {
+	return strings.Contains(s, "${")
+}
+
+// interpolate replaces the variable references in the values of the YAML
+// document root, in place. root declares a value of type t.
+//
+// Interpolated values are strings, so that e.g. a version 4.10 is not read as
+// the number 4.1. Only an unquoted value consisting of a single reference,
+// such as ${PORT}, is resolved again, and only if it is decoded into a number
+// or boolean field of t, so that variables may supply those.
+func interpolate(root *yamlv3.Node, lookup VariableLookup, t reflect.Type) error {
+	kinds := map[*yamlv3.Node]reflect.Kind{}
+	if root.Kind == yamlv3.DocumentNode && len(root.Content) > 0 {
+		scalarKinds(root.Content[0], t, kinds)
+	}
+
+	problems := []TemplateProblem{}
+	walkValues(root, "", func(node *yamlv3.Node, path string) {
+		if node.Kind != yamlv3.ScalarNode || !hasTemplate(node.Value) {
+			return
+		}
+
+		value, messages := expand(node.Value, lookup)
+		for _, m := range messages {
+			problems = append(problems, TemplateProblem{Path: path, Line: node.Line, Column: node.Column, Message: m.Error(), err: m.sentinel})
+		}
+
+		node.Tag = "!!str"
+		if node.Style == 0 && isSingleReference(node.Value) && isScalarKind(kinds[node]) {
+			node.Tag = ""
+		}
+		node.Value = value
+	})
+
+	if len(problems) > 0 {
+		return &InterpolationError{Problems: problems}
+	}
+
+	return nil
+}
+
+// isSingleReference returns true if s consists of exactly one variable
+// reference.
+func isSingleReference(s string) bool {
+	return strings.HasPrefix(s, "${") && strings.Count(s, "${") == 1 && strings.IndexByte(s, '}') == len(s)-1
+}
+
+// isScalarKind returns true for the kinds of numbers and booleans.
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// scalarKinds records the kind of the field of t that each scalar within node
+// is decoded into. Scalars that are not decoded into a field of t are not
+// recorded.
+func scalarKinds(node *yamlv3.Node, t reflect.Type, kinds map[*yamlv3.Node]reflect.Kind) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		kinds[node] = t.Kind()
+	case yamlv3.MappingNode:
+		switch {
+		case t.Kind() == reflect.Struct && t != timeType:
+			fields := jsonFields(t)
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if fieldType, ok := fields[node.Content[i].Value]; ok {
+					scalarKinds(node.Content[i+1], fieldType, kinds)
+				}
+			}
+		case t.Kind() == reflect.Map:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				scalarKinds(node.Content[i+1], t.Elem(), kinds)
+			}
+		}
+	case yamlv3.SequenceNode:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, item := range node.Content {
+				scalarKinds(item, t.Elem(), kinds)
+			}
+		}
+	}
+}
+
+// templateMessage describes a problem with a single variable reference.
+type templateMessage struct {
+	sentinel error
+	message  string
+}
+
+func (m templateMessage) Error() string {
+	return m.message
+}
+
+// expand replaces each variable reference in s.
+func expand(s string, lookup VariableLookup) (string, []templateMessage) {
+	var out strings.Builder
+	var messages []templateMessage
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			out.WriteString(s)
+			return out.String(), messages
+		}
+
+		// $${ is an escaped ${.
+		if start > 0 && s[start-1] == '$' {
+			out.WriteString(s[:start-1])
+			out.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+
+		out.WriteString(s[:start])
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			messages = append(messages, templateMessage{ErrInvalidTemplate, fmt.Sprintf("%s: unterminated reference %q", ErrInvalidTemplate, s[start:])})
+			out.WriteString(s[start:])
+			return out.String(), messages
+		}
+
+		reference := s[start+2 : start+end]
+		s = s[start+end+1:]
+
+		name, operand, operator := reference, "", ""
+		if i := strings.Index(reference, ":"); i >= 0 && i+1 < len(reference) && (reference[i+1] == '-' || reference[i+1] == '?') {
+			name, operator, operand = reference[:i], reference[i:i+2], reference[i+2:]
+		}
+
+		if !variableName.MatchString(name) {
+			messages = append(messages, templateMessage{ErrInvalidTemplate, fmt.Sprintf("%s: invalid reference ${%s}", ErrInvalidTemplate, reference)})
+			continue
+		}
+
+		value, ok := lookup(name)
+		switch {
+		case operator == ":-" && value == "":
+			value = operand
+		case operator == ":?" && value == "":
+			message := operand
+			if message == "" {
+				message = "a value is required"
+			}
+			messages = append(messages, templateMessage{ErrUndefinedVariable, fmt.Sprintf("%s %s: %s", ErrUndefinedVariable, name, message)})
+		case operator == "" && !ok:
+			messages = append(messages, templateMessage{ErrUndefinedVariable, fmt.Sprintf("%s %s", ErrUndefinedVariable, name)})
+		}
+
+		out.WriteString(value)
+	}
+}
+
+// walkValues calls fn with each node of the YAML document root that is not a
+// mapping key, along with its path, after the nodes it contains. Elements of
+// sequences are identified by their index.
+func walkValues(node *yamlv3.Node, path string, fn func(node *yamlv3.Node, path string)) {
+	walkNodes(node, path, indexKeys, fn)
+}
+
+// walkNodes is walkValues, identifying the elements of sequences by the keys
+// returned by elementKeys.
+func walkNodes(node *yamlv3.Node, path string, elementKeys func(sequence *yamlv3.Node) []string, fn func(node *yamlv3.Node, path string)) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, c := range node.Content {
+			walkNodes(c, path, elementKeys, fn)
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkNodes(node.Content[i+1], joinFieldPath(path, node.Content[i].Value), elementKeys, fn)
+		}
+	case yamlv3.SequenceNode:
+		keys := elementKeys(node)
+		for i, c := range node.Content {
+			walkNodes(c, path+keys[i], elementKeys, fn)
+		}
+	}
+
+	fn(node, path)
+}
+
+// indexKeys identifies the elements of sequence by their index.
+func indexKeys(sequence *yamlv3.Node) []string {
+	keys := make([]string, len(sequence.Content))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("[%d]", i)
+	}
+
+	return keys
+}
+
+// Values are variables read from values files, keyed by name. The values of
+// nested mappings are named by joining their keys with dots.
+type Values map[string]string
+
+// ReadValues reads the values of the YAML mapping in.
+func ReadValues(in io.Reader) (Values, error) {
+	var root yamlv3.Node
+	if err := yamlv3.NewDecoder(in).Decode(&root); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Values{}, nil
+		}
+		return nil, err
+	}
+
+	if root.Content[0].Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("%w: expected a mapping", ErrInvalidValues)
+	}
+
+	values := Values{}
+	var errs []error
+	var flatten func(mapping *yamlv3.Node, prefix string)
+	flatten = func(mapping *yamlv3.Node, prefix string) {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			name := joinFieldPath(prefix, mapping.Content[i].Value)
+			switch value := mapping.Content[i+1]; value.Kind {
+			case yamlv3.MappingNode:
+				flatten(value, name)
+			case yamlv3.ScalarNode:
+				values[name] = value.Value
+			default:
+				errs = append(errs, fmt.Errorf("%w: line %d: %s must be a string, number, boolean or mapping", ErrInvalidValues, value.Line, name))
+			}
+		}
+	}
+	flatten(root.Content[0], "")
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return values, nil
+}
+
+// ReadValuesFile reads the values file at filename, as ReadValues does.
+func ReadValuesFile(filename string) (Values, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values, err := ReadValues(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return values, nil
+}
+
+// ValuesOver returns a VariableLookup resolving variables from values, falling
+// back to the environment.
+func ValuesOver(values Values) VariableLookup {
+	return func(name string) (string, bool) {
+		if value, ok := values[name]; ok {
+			return value, true
+		}
+
+		return os.LookupEnv(name)
+	}
+}
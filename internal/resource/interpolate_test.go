@@ -0,0 +1,228 @@
+package resource_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Interpolation", func() {
+	values := resource.Values{
+		"NAME":         "my product",
+		"EMPTY":        "",
+		"support.url":  "https://example.com/support",
+		"LEGAL_ENTITY": "Example, Inc.",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := values[name]
+		return v, ok
+	}
+
+	read := func(content string) (*resource.ProductListingDeclaration, error) {
+		return resource.ReadProductListing(bytes.NewBufferString(content), resource.WithVariables(lookup))
+	}
+
+	It("should interpolate variables into values", func() {
+		declaration, err := read(`kind: ProductListing
+spec:
+  name: ${NAME}
+  descriptions:
+    long: "Copyright ${LEGAL_ENTITY}"
+  legal:
+    license_agreement_url: ${support.url}/eula
+  support:
+    email_address: ${SUPPORT_EMAIL:-support@example.com}
+    description: ${EMPTY:-no description}
+`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(declaration.Spec.Name).To(Equal("my product"))
+		Expect(declaration.Spec.Descriptions.Long).To(Equal("Copyright Example, Inc."))
+		Expect(declaration.Spec.Legal.LicenseAgreementURL).To(Equal("https://example.com/support/eula"))
+		Expect(declaration.Spec.Support.EmailAddress).To(Equal("support@example.com"))
+		Expect(declaration.Spec.Support.Description).To(Equal("no description"))
+	})
+
+	It("should keep interpolated values as strings unless their field is not a string", func() {
+		values["VERSION"] = "4.10"
+		values["CODE"] = "010"
+		values["FLAG"] = "true"
+		values["PRODUCT_VERSION"] = "17"
+		DeferCleanup(func() {
+			for _, name := range []string{"VERSION", "CODE", "FLAG", "PRODUCT_VERSION"} {
+				delete(values, name)
+			}
+		})
+
+		declaration, err := read(`kind: ProductListing
+spec:
+  name: ${CODE}
+  descriptions:
+    long: ${FLAG}
+  support:
+    description: v${VERSION}
+with:
+  components:
+  - name: ${VERSION}
+    helm_chart:
+      ocp_versions:
+      - ${VERSION}
+    redhat:
+      product_version_id: ${PRODUCT_VERSION}
+    self_certification:
+      requested: ${FLAG}
+`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(declaration.Spec.Name).To(Equal("010"))
+		Expect(declaration.Spec.Descriptions.Long).To(Equal("true"))
+		Expect(declaration.Spec.Support.Description).To(Equal("v4.10"))
+		component := declaration.With.Components[0]
+		Expect(component.Name).To(Equal("4.10"))
+		Expect(component.HelmChart.OCPVersions).To(Equal([]string{"4.10"}))
+		Expect(component.RedHat.ProductVersionID).To(Equal(17))
+		Expect(component.SelfCertification.Requested).To(HaveValue(BeTrue()))
+	})
+
+	It("should leave escaped references and declarations read without variables as they are", func() {
+		declaration, err := read("kind: ProductListing\nspec:\n  name: costs $${NAME}\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(declaration.Spec.Name).To(Equal("costs ${NAME}"))
+
+		declaration, err = resource.ReadProductListing(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: ${NAME}\n"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(declaration.Spec.Name).To(Equal("${NAME}"))
+	})
+
+	It("should report every undefined variable with its location", func() {
+		_, err := read(`kind: ProductListing
+spec:
+  name: ${UNDEFINED}
+  support:
+    email_address: ${SUPPORT_EMAIL:?set the support address}
+`)
+		Expect(err).To(MatchError(resource.ErrUndefinedVariable))
+
+		var interpolation *resource.InterpolationError
+		Expect(err).To(BeAssignableToTypeOf(interpolation))
+		interpolation = err.(*resource.InterpolationError)
+		Expect(interpolation.Problems).To(HaveLen(2))
+		Expect(interpolation.Problems[0].Path).To(Equal("spec.name"))
+		Expect(interpolation.Problems[0].Line).To(Equal(3))
+		Expect(interpolation.Problems[1].Path).To(Equal("spec.support.email_address"))
+		Expect(interpolation.Problems[1].Message).To(ContainSubstring("set the support address"))
+	})
+
+	It("should reject invalid references", func() {
+		_, err := read("kind: ProductListing\nspec:\n  name: ${NAME\n")
+		Expect(err).To(MatchError(resource.ErrInvalidTemplate))
+
+		_, err = read("kind: ProductListing\nspec:\n  name: ${not a name}\n")
+		Expect(err).To(MatchError(resource.ErrInvalidTemplate))
+	})
+
+	It("should report line numbers relative to the start of a stream", func() {
+		_, err := resource.ReadDeclarationStream(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: first\n---\nkind: ProductListing\nspec:\n  name: ${UNDEFINED}\n"), resource.WithVariables(lookup))
+		Expect(err).To(MatchError(resource.ErrUndefinedVariable))
+		Expect(err.Error()).To(ContainSubstring("line 7, column 9"))
+	})
+
+	It("should write variable references back in place of their values", func() {
+		content := `# modeline
+kind: ProductListing
+spec:
+  name: ${NAME}
+  support:
+    email_address: ${SUPPORT_EMAIL:-support@example.com}
+`
+		stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content), resource.WithVariables(lookup))
+		Expect(err).ToNot(HaveOccurred())
+
+		declaration := stream.Declarations()[0]
+		declaration.Spec.ID = "assigned-id"
+		Expect(stream.Replace(0, declaration)).To(Succeed())
+
+		written := string(stream.Bytes())
+		Expect(written).To(HavePrefix("# modeline\n"))
+		Expect(written).To(ContainSubstring("_id: assigned-id"))
+		Expect(written).To(ContainSubstring("name: ${NAME}"))
+		Expect(written).To(ContainSubstring("email_address: ${SUPPORT_EMAIL:-support@example.com}"))
+		Expect(written).ToNot(ContainSubstring("my product"))
+	})
+
+	It("should write references back within list elements whose names are references", func() {
+		values["COMPONENT"] = "api"
+		values["REGISTRY"] = "s3cr3t"
+		DeferCleanup(func() {
+			delete(values, "COMPONENT")
+			delete(values, "REGISTRY")
+		})
+
+		content := `kind: ProductListing
+spec:
+  name: ${NAME}
+with:
+  components:
+  - name: ${COMPONENT}
+    type: Containers
+    container:
+      registry: ${REGISTRY}
+`
+		stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content), resource.WithVariables(lookup))
+		Expect(err).ToNot(HaveOccurred())
+
+		declaration := stream.Declarations()[0]
+		declaration.Spec.ID = "assigned-id"
+		Expect(stream.Replace(0, declaration)).To(Succeed())
+
+		written := string(stream.Bytes())
+		Expect(written).To(ContainSubstring("name: ${COMPONENT}"))
+		Expect(written).To(ContainSubstring("registry: ${REGISTRY}"))
+		Expect(written).ToNot(ContainSubstring("s3cr3t"))
+		Expect(written).ToNot(ContainSubstring("name: api"))
+	})
+
+	It("should interpolate overlays, writing their references back as they were", func() {
+		declaration, err := resource.ReadProductListing(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: base\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		overlay, err := resource.ReadOverlay(bytes.NewBufferString("spec:\n  name: ${NAME}\n"), resource.WithVariables(lookup))
+		Expect(err).ToNot(HaveOccurred())
+
+		merged, err := overlay.Apply(0, declaration)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.Spec.Name).To(Equal("my product"))
+
+		merged.Spec.ID = "assigned-id"
+		Expect(overlay.SetServerIDs(0, merged)).To(BeTrue())
+		Expect(string(overlay.Bytes())).To(Equal("spec:\n  name: ${NAME}\n  _id: assigned-id\n"))
+	})
+
+	It("should read values files, naming nested values with dots", func() {
+		filename := filepath.Join(GinkgoT().TempDir(), "values.yaml")
+		Expect(os.WriteFile(filename, []byte("NAME: my product\nsupport:\n  url: https://example.com\nport: 8443\n"), 0o644)).To(Succeed())
+
+		read, err := resource.ReadValuesFile(filename)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(read).To(Equal(resource.Values{"NAME": "my product", "support.url": "https://example.com", "port": "8443"}))
+
+		_, err = resource.ReadValues(bytes.NewBufferString("list:\n- a\n"))
+		Expect(err).To(MatchError(resource.ErrInvalidValues))
+	})
+
+	It("should prefer values over the environment", func() {
+		GinkgoT().Setenv("PRODUCTCTL_TEST_VARIABLE", "from the environment")
+		GinkgoT().Setenv("PRODUCTCTL_TEST_ONLY_ENVIRONMENT", "from the environment")
+		lookup := resource.ValuesOver(resource.Values{"PRODUCTCTL_TEST_VARIABLE": "from values"})
+
+		value, _ := lookup("PRODUCTCTL_TEST_VARIABLE")
+		Expect(value).To(Equal("from values"))
+		value, _ = lookup("PRODUCTCTL_TEST_ONLY_ENVIRONMENT")
+		Expect(value).To(Equal("from the environment"))
+		_, ok := lookup("PRODUCTCTL_TEST_UNDEFINED")
+		Expect(ok).To(BeFalse())
+	})
+})
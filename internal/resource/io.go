@@ -1,26 +1,151 @@
 package resource
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"reflect"
 
+	yamlv3 "go.yaml.in/yaml/v3"
 	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/markup"
 )
 
+var ErrUnknownKind = errors.New("unknown kind")
+
+// ReadOption configures how declarations are read.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	strict    bool
+	warn      func(warning string)
+	variables VariableLookup
+	baseDir   string
+}
+
+// WithStrict rejects declarations containing fields that do not correspond to
+// any field of the declared resource, e.g. misspelled fields, if strict is
+// set. All such fields are reported in an *UnknownFieldsError.
+func WithStrict(strict bool) ReadOption {
+	return func(o *readOptions) {
+		o.strict = strict
+	}
+}
+
+// WithWarningHandler calls handler with any warnings about declarations that
+// were read successfully, such as the use of a deprecated apiVersion.
+func WithWarningHandler(handler func(warning string)) ReadOption {
+	return func(o *readOptions) {
+		o.warn = handler
+	}
+}
+
 // ReadProductListing reads the ProductListing resource from the io.Reader. It
 // assumes YAML-formatted contents. Caller is responsible for assuring that the
 // returned struct contains the necessary data. Does not fail if extra values
-// are found in the input data.
-func ReadProductListing(in io.Reader) (*ProductListingDeclaration, error) {
+// are found in the input data, unless WithStrict is provided. Fails if the
+// declaration is of any kind other than KindProductListing. Declarations of a
+// previous apiVersion are converted to CurrentAPIVersion, with a warning.
+// Files included by the declaration are resolved, relative to the directory
+// provided with WithBaseDir, and variables are interpolated into its values if
+// WithVariables is provided.
+func ReadProductListing(in io.Reader, opts ...ReadOption) (*ProductListingDeclaration, error) {
 	listing := NewProductListing()
+	if err := readDeclaration(in, &listing, &listing.Kind, KindProductListing, opts...); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// ReadComponent reads a Component resource from the io.Reader, as
+// ReadProductListing does for product listings. Fails if the declaration is of
+// any kind other than KindComponent.
+func ReadComponent(in io.Reader, opts ...ReadOption) (*ComponentDeclaration, error) {
+	component := NewComponentDeclaration()
+	if err := readDeclaration(in, &component, &component.Kind, KindComponent, opts...); err != nil {
+		return nil, err
+	}
+
+	return &component, nil
+}
+
+// readDeclaration reads the declaration from in into target, which must
+// already hold the defaults of its kind. kind points to the kind field of
+// target, which must be expectedKind once read.
+func readDeclaration(in io.Reader, target any, kind *string, expectedKind string, opts ...ReadOption) error {
+	options := readOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	b, err := io.ReadAll(in)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := yaml.Unmarshal(b, &listing); err != nil {
-		return nil, err
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(b, &root); err != nil {
+		return err
 	}
 
-	return &listing, nil
+	// Markdown that references variables is rendered once they are
+	// interpolated, so that the values are rendered with it rather than
+	// written into the HTML it was rendered as.
+	var markdown []*yamlv3.Node
+	included, err := resolveIncludes(&root, options.baseDir, func(node *yamlv3.Node, inc include) {
+		if options.variables != nil && inc.format == formatMarkdown && hasTemplate(string(inc.raw)) {
+			node.Value = string(inc.raw)
+			markdown = append(markdown, node)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	from, _, err := migrateDocument(&root)
+	if err != nil {
+		return err
+	}
+
+	interpolated := false
+	if options.variables != nil {
+		if err := interpolate(&root, options.variables, reflect.TypeOf(target)); err != nil {
+			return err
+		}
+		interpolated = true
+
+		for _, node := range markdown {
+			if node.Value, err = markup.ToHTML(node.Value); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidMarkdown, err)
+			}
+		}
+	}
+
+	if from != CurrentAPIVersion || included || interpolated {
+		if b, err = yamlv3.Marshal(&root); err != nil {
+			return err
+		}
+	}
+
+	if err := yaml.Unmarshal(b, target); err != nil {
+		return err
+	}
+
+	if *kind != expectedKind {
+		return fmt.Errorf("%w %q, expected %q", ErrUnknownKind, *kind, expectedKind)
+	}
+
+	if options.strict {
+		if err := checkUnknownFields(&root, target); err != nil {
+			return err
+		}
+	}
+
+	if from != CurrentAPIVersion && options.warn != nil {
+		options.warn(deprecationWarning(from))
+	}
+
+	return nil
 }
@@ -0,0 +1,199 @@
+package resource
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+)
+
+// The owners of values that are not managed by the user, declared with the
+// managed struct tag, e.g. `managed:"backend"`. Fields without the tag, and
+// those held only by the declaration, are managed by the user.
+const (
+	// ownerBackend fields are set by the backend, and are never sent to it.
+	ownerBackend = "backend"
+	// ownerIdentity fields are set by the backend when an object is created,
+	// and identify the object that the declaration is applied to.
+	ownerIdentity = "identity"
+	// ownerApply fields are computed by apply from the rest of the
+	// declaration, e.g. the IDs of the components attached to a product
+	// listing.
+	ownerApply = "apply"
+	// ownerDeclaration fields are set by the user, but are held only by the
+	// declaration. They are never sent to the backend, which has no such
+	// fields, and are carried over from the declaration when it is applied.
+	ownerDeclaration = "declaration"
+)
+
+const managedTag = "managed"
+
+// managedDescriptions describe the fields of each owner in the JSON Schema,
+// unless they are described by their jsonschema struct tags.
+var managedDescriptions = map[string]string{
+	ownerBackend:  "Set by the backend when the declaration is applied. Changes to this value are not applied.",
+	ownerIdentity: "Set by the backend when the declaration is applied. Changes to this value are not applied.",
+	ownerApply:    "Set by productctl when the declaration is applied. Changes to this value are not applied.",
+}
+
+// jsonFieldName returns the name of f in JSON, or "" if it is not marshaled.
+func jsonFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+
+	return name
+}
+
+// isManaged returns true if f is not managed by the user.
+func isManaged(f reflect.StructField) bool {
+	owner, managed := f.Tag.Lookup(managedTag)
+	return managed && owner != ownerDeclaration
+}
+
+// clearManaged sets every field of v that is not managed by the user to its
+// zero value, descending into structs, pointers and slices.
+func clearManaged(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			clearManaged(v.Elem())
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			clearManaged(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			if isManaged(f) {
+				v.Field(i).SetZero()
+				continue
+			}
+			clearManaged(v.Field(i))
+		}
+	}
+}
+
+// omitOwned removes the values of value, the JSON representation of a value
+// of type t, that belong to any of owners.
+func omitOwned(t reflect.Type, value any, owners ...string) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		omitOwned(t.Elem(), value, owners...)
+	case reflect.Slice:
+		elements, _ := value.([]any)
+		for _, e := range elements {
+			omitOwned(t.Elem(), e, owners...)
+		}
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name := jsonFieldName(f)
+			if name == "" {
+				continue
+			}
+
+			if owner, ok := f.Tag.Lookup(managedTag); ok && slices.Contains(owners, owner) {
+				delete(object, name)
+				continue
+			}
+			omitOwned(f.Type, object[name], owners...)
+		}
+	}
+}
+
+// markManaged marks the properties of schema's definitions that are not
+// managed by the user as read-only, for each struct type reachable from t.
+func markManaged(schema *jsonschema.Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		markManaged(schema, t.Elem(), seen)
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+
+	definition := schema.Definitions[t.Name()]
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name := jsonFieldName(f)
+		if name == "" {
+			continue
+		}
+
+		markManaged(schema, f.Type, seen)
+
+		if !isManaged(f) || definition == nil {
+			continue
+		}
+
+		if property, ok := definition.Properties.Get(name); ok {
+			property.ReadOnly = true
+			if property.Description == "" {
+				property.Description = managedDescriptions[f.Tag.Get(managedTag)]
+			}
+		}
+	}
+}
+
+// copyDeclarationOwned sets every field of dst that is held only by the
+// declaration to its value in src, descending into structs and pointers.
+// Pointers that are nil in dst are only set if src holds such a value within
+// them.
+func copyDeclarationOwned(dst, src reflect.Value) {
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+
+		if !dst.IsNil() {
+			copyDeclarationOwned(dst.Elem(), src.Elem())
+			return
+		}
+
+		elem := reflect.New(dst.Type().Elem())
+		copyDeclarationOwned(elem.Elem(), src.Elem())
+		if !elem.Elem().IsZero() {
+			dst.Set(elem)
+		}
+	case reflect.Struct:
+		for i := range dst.NumField() {
+			f := dst.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			if f.Tag.Get(managedTag) == ownerDeclaration {
+				dst.Field(i).Set(src.Field(i))
+				continue
+			}
+			copyDeclarationOwned(dst.Field(i), src.Field(i))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package resource
+
+import (
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opdev/productctl/internal/markup"
+)
+
+// MarshalMarkdown marshals declaration as YAML, writing the values of fields
+// that hold HTML in Markdown, e.g. {format: markdown, value: "**Yes**"},
+// where the Markdown renders as the same HTML. Other values are written as
+// yaml.Marshal writes them.
+func MarshalMarkdown(declaration any) ([]byte, error) {
+	b, err := yaml.Marshal(declaration)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(b, &root); err != nil {
+		return nil, err
+	}
+
+	converted := false
+	walkValues(&root, "", func(node *yamlv3.Node, path string) {
+		if node.Kind != yamlv3.ScalarNode || node.Value == "" || !isHTMLField(path) {
+			return
+		}
+
+		markdown, ok := markup.ToMarkdown(node.Value)
+		if !ok {
+			return
+		}
+
+		value := &yamlv3.Node{}
+		value.SetString(markdown)
+		*node = yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		setMappingValue(node, formatOption, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: formatMarkdown})
+		setMappingValue(node, valueOption, value)
+		converted = true
+	})
+
+	if !converted {
+		return b, nil
+	}
+
+	return encodeNode(&root)
+}
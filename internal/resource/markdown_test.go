@@ -0,0 +1,34 @@
+package resource_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("MarshalMarkdown", func() {
+	It("should write HTML fields in Markdown where it renders as the same HTML", func() {
+		declaration := resource.NewProductListing()
+		declaration.Spec.Name = "<p>not HTML</p>"
+		declaration.Spec.Descriptions = &resource.ProductListingDescriptions{Long: "<h1>About</h1>\n<p>A <strong>long</strong> description.</p>"}
+		declaration.Spec.FAQs = []resource.FAQ{
+			{Question: "Supported?", Answer: "<p>Yes.</p>"},
+			{Question: "Underlined?", Answer: "<p><u>Yes</u></p>"},
+		}
+
+		b, err := resource.MarshalMarkdown(&declaration)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(b)).To(ContainSubstring("name: <p>not HTML</p>"))
+		Expect(string(b)).To(ContainSubstring("format: markdown"))
+		Expect(string(b)).To(ContainSubstring("value: Yes."))
+		Expect(string(b)).To(ContainSubstring("answer: <p><u>Yes</u></p>"))
+
+		stream, err := resource.ReadDeclarationStream(bytes.NewReader(b), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stream.Declarations()[0].Spec.Descriptions.Long).To(Equal(declaration.Spec.Descriptions.Long))
+		Expect(stream.Declarations()[0].Spec.FAQs).To(Equal(declaration.Spec.FAQs))
+	})
+})
@@ -0,0 +1,562 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+var ErrInvalidOverlay = errors.New("invalid overlay")
+
+// patchDirective is the key of list elements in an overlay that changes how
+// the element is merged. The only supported value is "delete", which removes
+// the element of the same name from the declaration.
+const patchDirective = "$patch"
+
+// Overlay holds changes to the product listing declarations of a stream that
+// are specific to one environment, along with the IDs the backend assigned in
+// that environment. The nth document of an overlay applies to the nth
+// declaration of the stream.
+//
+// Overlays are JSON merge patches (RFC 7386), except that lists whose elements
+// all have a name, such as with.components, are merged element by element by
+// name. Elements not found in the declaration are refused, as they are most
+// often the IDs of elements since removed from the declaration.
+type Overlay struct {
+	overlayDocuments
+}
+
+// ComponentOverlay holds changes to the component declarations of a stream
+// that are specific to one environment, along with the IDs the backend
+// assigned in that environment, as Overlay does for product listing
+// declarations.
+type ComponentOverlay struct {
+	overlayDocuments
+}
+
+// overlayDocuments are the documents of an overlay of any kind.
+type overlayDocuments struct {
+	documents []*overlayDocument
+}
+
+type overlayDocument struct {
+	separator []byte
+	raw       []byte
+	// root is nil for documents that contain no data.
+	root *yamlv3.Node
+	// interpolated is root with variables interpolated, if it was read with
+	// WithVariables. root retains the variable references, so that they are
+	// written back as they were.
+	interpolated *yamlv3.Node
+	modified     bool
+}
+
+// ReadOverlay reads an overlay from in. Fields that do not correspond to a
+// field of a product listing declaration are rejected if WithStrict is
+// provided, and variables are interpolated if WithVariables is provided, as
+// for declarations.
+func ReadOverlay(in io.Reader, opts ...ReadOption) (*Overlay, error) {
+	documents, err := readOverlayDocuments[*ProductListingDeclaration](in, KindProductListing, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Overlay{documents}, nil
+}
+
+// ReadComponentOverlay reads an overlay of component declarations from in, as
+// ReadOverlay does for product listing declarations.
+func ReadComponentOverlay(in io.Reader, opts ...ReadOption) (*ComponentOverlay, error) {
+	documents, err := readOverlayDocuments[*ComponentDeclaration](in, KindComponent, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ComponentOverlay{documents}, nil
+}
+
+// readOverlayDocuments reads the documents of an overlay of declarations of
+// type T, which are of the given kind.
+func readOverlayDocuments[T any](in io.Reader, kind string, opts ...ReadOption) (overlayDocuments, error) {
+	options := readOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return overlayDocuments{}, err
+	}
+
+	o := overlayDocuments{}
+	linesBefore := 0
+	for i, doc := range splitDocuments[struct{}](b) {
+		document := &overlayDocument{separator: doc.separator, raw: doc.raw}
+		o.documents = append(o.documents, document)
+
+		linesBefore += bytes.Count(doc.separator, []byte("\n"))
+		offset := linesBefore
+		linesBefore += bytes.Count(doc.raw, []byte("\n"))
+
+		empty, err := isEmptyDocument(doc.raw)
+		if err != nil {
+			return overlayDocuments{}, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		if empty {
+			continue
+		}
+
+		var root yamlv3.Node
+		if err := yamlv3.Unmarshal(doc.raw, &root); err != nil {
+			return overlayDocuments{}, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		if root.Content[0].Kind != yamlv3.MappingNode {
+			return overlayDocuments{}, fmt.Errorf("document %d: %w: expected a mapping", i+1, ErrInvalidOverlay)
+		}
+
+		if k := mappingValue(root.Content[0], "kind"); k != nil && k.Value != kind {
+			return overlayDocuments{}, fmt.Errorf("document %d: %w: kind %q, expected %s", i+1, ErrInvalidOverlay, k.Value, kind)
+		}
+
+		if options.strict {
+			if err := checkUnknownOverlayFields(&root, reflect.New(reflect.TypeFor[T]().Elem()).Interface()); err != nil {
+				return overlayDocuments{}, fmt.Errorf("document %d: %w", i+1, err)
+			}
+		}
+
+		document.root = &root
+		if options.variables != nil {
+			// The document is parsed again, so that root is unaffected.
+			var interpolated yamlv3.Node
+			if err := yamlv3.Unmarshal(doc.raw, &interpolated); err != nil {
+				return overlayDocuments{}, fmt.Errorf("document %d: %w", i+1, err)
+			}
+
+			if err := interpolate(&interpolated, options.variables, reflect.TypeFor[T]()); err != nil {
+				var interpolation *InterpolationError
+				if errors.As(err, &interpolation) {
+					interpolation.offsetLines(offset)
+				}
+				return overlayDocuments{}, fmt.Errorf("document %d: %w", i+1, err)
+			}
+			document.interpolated = &interpolated
+		}
+	}
+
+	return o, nil
+}
+
+// ReadOverlayFile reads the overlay at filename. A file that does not exist is
+// read as an overlay that changes nothing, and exists is false.
+func ReadOverlayFile(filename string, opts ...ReadOption) (overlay *Overlay, exists bool, err error) {
+	return readOverlayFile(filename, ReadOverlay, opts...)
+}
+
+// ReadComponentOverlayFile reads the component overlay at filename, as
+// ReadOverlayFile does.
+func ReadComponentOverlayFile(filename string, opts ...ReadOption) (overlay *ComponentOverlay, exists bool, err error) {
+	return readOverlayFile(filename, ReadComponentOverlay, opts...)
+}
+
+func readOverlayFile[O any](filename string, read func(io.Reader, ...ReadOption) (*O, error), opts ...ReadOption) (*O, bool, error) {
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return new(O), false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	overlay, err := read(f, opts...)
+	if err != nil {
+		return nil, true, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return overlay, true, nil
+}
+
+// checkUnknownOverlayFields is checkUnknownFields, permitting patch directives.
+func checkUnknownOverlayFields(root *yamlv3.Node, target any) error {
+	err := checkUnknownFields(root, target)
+
+	var unknown *UnknownFieldsError
+	if !errors.As(err, &unknown) {
+		return err
+	}
+
+	fields := unknown.Fields[:0]
+	for _, f := range unknown.Fields {
+		if !strings.HasSuffix(f.Path, "."+patchDirective) {
+			fields = append(fields, f)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &UnknownFieldsError{Fields: fields}
+}
+
+// Apply returns the ith declaration of a stream with this overlay applied. The
+// server-managed values of the declaration, such as its IDs, are removed
+// before the overlay is applied, so that only those recorded in the overlay
+// are used. The declaration itself is not modified.
+func (o *Overlay) Apply(i int, declaration *ProductListingDeclaration) (*ProductListingDeclaration, error) {
+	base, err := JSONConvert[ProductListingDeclaration](declaration)
+	if err != nil {
+		return nil, err
+	}
+	base.Sanitize()
+
+	return applyOverlay(&o.overlayDocuments, i, base)
+}
+
+// Apply returns the ith declaration of a stream with this overlay applied, as
+// Overlay.Apply does for product listing declarations.
+func (o *ComponentOverlay) Apply(i int, declaration *ComponentDeclaration) (*ComponentDeclaration, error) {
+	base, err := JSONConvert[ComponentDeclaration](declaration)
+	if err != nil {
+		return nil, err
+	}
+	base.Sanitize()
+
+	return applyOverlay(&o.overlayDocuments, i, base)
+}
+
+// applyOverlay returns base with the ith document of o merged into it.
+func applyOverlay[T any](o *overlayDocuments, i int, base T) (*T, error) {
+	if i >= len(o.documents) || o.documents[i].root == nil {
+		return &base, nil
+	}
+
+	values := o.documents[i].root
+	if o.documents[i].interpolated != nil {
+		values = o.documents[i].interpolated
+	}
+
+	var patch any
+	if err := values.Decode(&patch); err != nil {
+		return nil, fmt.Errorf("document %d: %w", i+1, err)
+	}
+
+	target, err := JSONConvert[map[string]any](base)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values decoded from YAML are normalized to their JSON representation.
+	patch, err = JSONConvert[any](patch)
+	if err != nil {
+		return nil, fmt.Errorf("document %d: %w", i+1, err)
+	}
+
+	merged, err := mergePatch(target, patch, "")
+	if err != nil {
+		return nil, fmt.Errorf("document %d: %w", i+1, err)
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+
+	var result T
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("document %d: %w: %w", i+1, ErrInvalidOverlay, err)
+	}
+
+	return &result, nil
+}
+
+// mergePatch merges patch into target, returning the result. Both are values
+// decoded from JSON. Lists are replaced unless mergeByName can merge them.
+func mergePatch(target, patch any, path string) (any, error) {
+	switch patch := patch.(type) {
+	case map[string]any:
+		targetMap, ok := target.(map[string]any)
+		if !ok {
+			targetMap = map[string]any{}
+		}
+
+		for key, value := range patch {
+			if key == patchDirective {
+				return nil, fmt.Errorf("%w: %s: %s is only supported in lists of named elements", ErrInvalidOverlay, joinFieldPath(path, key), patchDirective)
+			}
+
+			if value == nil {
+				delete(targetMap, key)
+				continue
+			}
+
+			merged, err := mergePatch(targetMap[key], value, joinFieldPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			targetMap[key] = merged
+		}
+
+		return targetMap, nil
+	case []any:
+		// Lists missing from the declaration are merged as empty lists, so
+		// that their elements are refused too.
+		if targetList, ok := target.([]any); (ok || target == nil) && allNamed(patch) {
+			return mergeByName(targetList, patch, path)
+		}
+
+		// Nulls have no meaning in lists that are replaced, but removing
+		// them from the elements keeps the result consistent.
+		replaced := make([]any, 0, len(patch))
+		for i, element := range patch {
+			merged, err := mergePatch(nil, element, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			replaced = append(replaced, merged)
+		}
+
+		return replaced, nil
+	default:
+		return patch, nil
+	}
+}
+
+// mergeByName merges each element of patch into the element of target with
+// the same name. Elements of patch that match none are refused, unless they
+// delete the element.
+func mergeByName(target, patch []any, path string) (any, error) {
+	merged := make([]any, 0, len(target)+len(patch))
+	merged = append(merged, target...)
+
+	for i, element := range patch {
+		patchElement := element.(map[string]any)
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+
+		directive, hasDirective := patchElement[patchDirective]
+		delete(patchElement, patchDirective)
+		if hasDirective && directive != "delete" {
+			return nil, fmt.Errorf("%w: %s: unknown %s directive %v", ErrInvalidOverlay, joinFieldPath(elementPath, patchDirective), patchDirective, directive)
+		}
+
+		index := -1
+		for j, t := range merged {
+			if m, ok := t.(map[string]any); ok && m["name"] == patchElement["name"] {
+				index = j
+				break
+			}
+		}
+
+		switch {
+		case hasDirective:
+			if index >= 0 {
+				merged = append(merged[:index], merged[index+1:]...)
+			}
+		case index >= 0:
+			result, err := mergePatch(merged[index], patchElement, elementPath)
+			if err != nil {
+				return nil, err
+			}
+			merged[index] = result
+		default:
+			return nil, fmt.Errorf("%w: %s: the declaration has no element named %q, add it to the declaration or remove it from the overlay", ErrInvalidOverlay, elementPath, patchElement["name"])
+		}
+	}
+
+	return merged, nil
+}
+
+// allNamed returns true if list is not empty, and each of its elements is an
+// object with a name.
+func allNamed(list []any) bool {
+	for _, element := range list {
+		m, ok := element.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		if name, ok := m["name"].(string); !ok || name == "" {
+			return false
+		}
+	}
+
+	return len(list) > 0
+}
+
+// SetServerIDs records the IDs of the applied declaration as the IDs of the
+// ith declaration of the stream in this environment. IDs are removed from the
+// overlay if the declaration has none, e.g. after it has been cleaned up. The
+// IDs of components are recorded by their name. It returns true if the overlay
+// changed.
+func (o *Overlay) SetServerIDs(i int, applied *ProductListingDeclaration) bool {
+	document := o.document(i)
+	root := document.root.Content[0]
+
+	changed := setOrRemoveID(mappingOf(root, "spec", applied.Spec.ID != ""), applied.Spec.ID)
+
+	components := mappingValue(root, "with")
+	if components != nil {
+		components = mappingValue(components, "components")
+	}
+	for _, c := range applied.With.Components {
+		if c.Name == "" || (c.ID == "" && components == nil) {
+			continue
+		}
+
+		if components == nil {
+			components = &yamlv3.Node{Kind: yamlv3.SequenceNode}
+			setMappingValue(mappingOf(root, "with", true), "components", components)
+		}
+
+		element := namedElement(components, c.Name, c.ID != "")
+		if setOrRemoveID(element, c.ID) {
+			changed = true
+		}
+	}
+
+	document.modified = document.modified || changed
+	return changed
+}
+
+// SetServerIDs records the ID of the applied declaration as the ID of the ith
+// declaration of the stream in this environment, as Overlay.SetServerIDs does
+// for product listing declarations.
+func (o *ComponentOverlay) SetServerIDs(i int, applied *ComponentDeclaration) bool {
+	document := o.document(i)
+	root := document.root.Content[0]
+
+	changed := setOrRemoveID(mappingOf(root, "spec", applied.Spec.ID != ""), applied.Spec.ID)
+
+	document.modified = document.modified || changed
+	return changed
+}
+
+// document returns the ith document of o, adding empty documents as needed.
+func (o *overlayDocuments) document(i int) *overlayDocument {
+	for len(o.documents) <= i {
+		var separator []byte
+		if len(o.documents) > 0 {
+			separator = []byte("---\n")
+		}
+		o.documents = append(o.documents, &overlayDocument{separator: separator})
+	}
+
+	document := o.documents[i]
+	if document.root == nil {
+		document.root = &yamlv3.Node{
+			Kind:    yamlv3.DocumentNode,
+			Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode}},
+		}
+	}
+
+	return document
+}
+
+// Bytes returns the overlay as YAML. Documents that were not modified retain
+// their original content.
+func (o *overlayDocuments) Bytes() []byte {
+	var out bytes.Buffer
+	for _, document := range o.documents {
+		out.Write(document.separator)
+		if !document.modified {
+			out.Write(document.raw)
+			continue
+		}
+
+		encoder := yamlv3.NewEncoder(&out)
+		encoder.SetIndent(2)
+		// Encoding a node tree parsed by yamlv3 does not fail.
+		_ = encoder.Encode(document.root)
+		_ = encoder.Close()
+	}
+
+	return out.Bytes()
+}
+
+// setOrRemoveID sets the _id of mapping to id, or removes it if id is empty,
+// returning true if mapping changed. A nil mapping is left as-is.
+func setOrRemoveID(mapping *yamlv3.Node, id string) bool {
+	if mapping == nil {
+		return false
+	}
+
+	current := mappingValue(mapping, "_id")
+	switch {
+	case id == "" && current == nil:
+		return false
+	case id == "":
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == "_id" {
+				mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+				break
+			}
+		}
+		return true
+	case current != nil && current.Value == id:
+		return false
+	default:
+		value := &yamlv3.Node{}
+		value.SetString(id)
+		setMappingValue(mapping, "_id", value)
+		return true
+	}
+}
+
+// mappingOf returns the mapping that is the value of key in mapping. If there
+// is none, it is added if create is set, and nil is returned otherwise.
+func mappingOf(mapping *yamlv3.Node, key string, create bool) *yamlv3.Node {
+	if value := mappingValue(mapping, key); value != nil && value.Kind == yamlv3.MappingNode {
+		return value
+	}
+
+	if !create {
+		return nil
+	}
+
+	value := &yamlv3.Node{Kind: yamlv3.MappingNode}
+	setMappingValue(mapping, key, value)
+	return value
+}
+
+// namedElement returns the mapping in sequence with the given name. If there
+// is none, it is added if create is set, and nil is returned otherwise.
+func namedElement(sequence *yamlv3.Node, name string, create bool) *yamlv3.Node {
+	for _, element := range sequence.Content {
+		if value := mappingValue(element, "name"); element.Kind == yamlv3.MappingNode && value != nil && value.Value == name {
+			return element
+		}
+	}
+
+	if !create {
+		return nil
+	}
+
+	element := &yamlv3.Node{Kind: yamlv3.MappingNode}
+	nameValue := &yamlv3.Node{}
+	nameValue.SetString(name)
+	setMappingValue(element, "name", nameValue)
+	sequence.Content = append(sequence.Content, element)
+	return element
+}
+
+// setMappingValue sets the value of key in mapping, adding key if it is not
+// already present.
+func setMappingValue(mapping *yamlv3.Node, key string, value *yamlv3.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+
+	mapping.Content = append(mapping.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}, value)
+}
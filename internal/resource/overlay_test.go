@@ -0,0 +1,212 @@
+package resource_test
+
+import (
+	"bytes"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Overlay", func() {
+	const base = `kind: ProductListing
+spec:
+  _id: prod-id
+  name: my product
+  descriptions:
+    long: the long description
+    short: the short description
+  contacts:
+  - email_address: prod@example.com
+    type: Technical contact
+with:
+  components:
+  - _id: prod-component-id
+    name: operator
+    type: Containers
+    container:
+      registry: registry.example.com
+  - name: chart
+    type: Helm Chart
+`
+
+	var declaration *resource.ProductListingDeclaration
+
+	BeforeEach(func() {
+		var err error
+		declaration, err = resource.ReadProductListing(bytes.NewBufferString(base))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should merge the overlay into the declaration without its IDs", func() {
+		overlay, err := resource.ReadOverlay(bytes.NewBufferString(`# stage
+spec:
+  _id: stage-id
+  descriptions:
+    short: ~
+  contacts:
+  - email_address: stage@example.com
+    type: Technical contact
+with:
+  components:
+  - name: operator
+    _id: stage-component-id
+    container:
+      registry: stage-registry.example.com
+  - name: chart
+    $patch: delete
+  - name: removed
+    $patch: delete
+`), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+
+		merged, err := overlay.Apply(0, declaration)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.Spec.ID).To(Equal("stage-id"))
+		Expect(merged.Spec.Name).To(Equal("my product"))
+		Expect(merged.Spec.Descriptions.Long).To(Equal("the long description"))
+		Expect(merged.Spec.Descriptions.Short).To(BeEmpty())
+		Expect(merged.Spec.Contacts).To(HaveLen(1))
+		Expect(merged.Spec.Contacts[0].EmailAddress).To(Equal("stage@example.com"))
+
+		Expect(merged.With.Components).To(HaveLen(1))
+		Expect(merged.With.Components[0].Name).To(Equal("operator"))
+		Expect(merged.With.Components[0].ID).To(Equal("stage-component-id"))
+		Expect(merged.With.Components[0].Type).To(BeEquivalentTo("Containers"))
+		Expect(merged.With.Components[0].Container.Registry).To(Equal("stage-registry.example.com"))
+
+		Expect(declaration.Spec.ID).To(Equal("prod-id"))
+		Expect(declaration.With.Components).To(HaveLen(2))
+	})
+
+	It("should remove server IDs from declarations it does not cover", func() {
+		overlay, err := resource.ReadOverlay(bytes.NewBufferString(""), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+
+		merged, err := overlay.Apply(0, declaration)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.Spec.ID).To(BeEmpty())
+		Expect(merged.With.Components[0].ID).To(BeEmpty())
+		Expect(merged.Spec.Name).To(Equal("my product"))
+	})
+
+	It("should refuse components of declarations that have none", func() {
+		declaration, err := resource.ReadProductListing(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: my product\n"))
+		Expect(err).ToNot(HaveOccurred())
+		overlay, err := resource.ReadOverlay(bytes.NewBufferString("with:\n  components:\n  - name: removed\n    _id: stale-id\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = overlay.Apply(0, declaration)
+		Expect(err).To(MatchError(resource.ErrInvalidOverlay))
+	})
+
+	DescribeTable("should refuse invalid overlays",
+		func(content string, strict bool, expected error) {
+			overlay, err := resource.ReadOverlay(bytes.NewBufferString(content), resource.WithStrict(strict))
+			if err == nil {
+				_, err = overlay.Apply(0, declaration)
+			}
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("not a mapping", "- spec\n", false, resource.ErrInvalidOverlay),
+		Entry("another kind", "kind: Component\n", false, resource.ErrInvalidOverlay),
+		Entry("unknown fields when strict", "spec:\n  nmae: typo\n", true, resource.ErrUnknownFields),
+		Entry("a misplaced directive", "spec:\n  $patch: delete\n", false, resource.ErrInvalidOverlay),
+		Entry("an unknown directive", "with:\n  components:\n  - name: operator\n    $patch: replace\n", false, resource.ErrInvalidOverlay),
+		Entry("elements missing from the declaration", "with:\n  components:\n  - name: removed\n    _id: stale-id\n", false, resource.ErrInvalidOverlay),
+		Entry("mistyped values", "spec:\n  name: [not, a, name]\n", false, resource.ErrInvalidOverlay),
+	)
+
+	When("recording server IDs", func() {
+		It("should record listing and component IDs, retaining the rest of the overlay", func() {
+			overlay, err := resource.ReadOverlay(bytes.NewBufferString("# first\nspec:\n  name: stage name # renamed\n"), resource.WithStrict(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			applied, err := overlay.Apply(0, declaration)
+			Expect(err).ToNot(HaveOccurred())
+			applied.Spec.ID = "stage-id"
+			applied.With.Components[0].ID = "stage-component-id"
+			overlay.SetServerIDs(0, applied)
+
+			second := resource.NewProductListing()
+			second.Spec.ID = "second-id"
+			overlay.SetServerIDs(2, &second)
+
+			Expect(string(overlay.Bytes())).To(Equal(`# first
+spec:
+  name: stage name # renamed
+  _id: stage-id
+with:
+  components:
+    - name: operator
+      _id: stage-component-id
+---
+---
+spec:
+  _id: second-id
+`))
+
+			reread, err := resource.ReadOverlay(bytes.NewReader(overlay.Bytes()), resource.WithStrict(true))
+			Expect(err).ToNot(HaveOccurred())
+			merged, err := reread.Apply(0, declaration)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(merged.Spec.ID).To(Equal("stage-id"))
+			Expect(merged.Spec.Name).To(Equal("stage name"))
+			Expect(merged.With.Components[0].ID).To(Equal("stage-component-id"))
+		})
+
+		It("should remove IDs the declaration no longer has", func() {
+			content := "spec:\n  _id: stage-id\nwith:\n  components:\n  - name: operator\n    _id: stage-component-id\n"
+			overlay, err := resource.ReadOverlay(bytes.NewBufferString(content), resource.WithStrict(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			cleaned, err := overlay.Apply(0, declaration)
+			Expect(err).ToNot(HaveOccurred())
+			cleaned.Sanitize()
+			overlay.SetServerIDs(0, cleaned)
+
+			Expect(string(overlay.Bytes())).To(Equal("spec: {}\nwith:\n  components:\n    - name: operator\n"))
+		})
+
+		It("should leave unchanged overlays as they were", func() {
+			content := "spec:\n  _id:    stage-id\n"
+			overlay, err := resource.ReadOverlay(bytes.NewBufferString(content), resource.WithStrict(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			applied, err := overlay.Apply(0, declaration)
+			Expect(err).ToNot(HaveOccurred())
+			overlay.SetServerIDs(0, applied)
+			Expect(string(overlay.Bytes())).To(Equal(content))
+		})
+	})
+
+	It("should hold the IDs of component declarations", func() {
+		component, err := resource.ReadComponent(bytes.NewBufferString("kind: Component\nspec:\n  _id: prod-id\n  name: base-image\n"))
+		Expect(err).ToNot(HaveOccurred())
+
+		overlay, err := resource.ReadComponentOverlay(bytes.NewBufferString("# stage\nspec:\n  contacts:\n  - email_address: stage@example.com\n"), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+
+		merged, err := overlay.Apply(0, component)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(merged.Spec.ID).To(BeEmpty())
+		Expect(merged.Spec.Name).To(Equal("base-image"))
+		Expect(merged.Spec.Contacts).To(HaveLen(1))
+
+		merged.Spec.ID = "stage-id"
+		Expect(overlay.SetServerIDs(0, merged)).To(BeTrue())
+		Expect(string(overlay.Bytes())).To(ContainSubstring("_id: stage-id"))
+
+		_, err = resource.ReadComponentOverlay(bytes.NewBufferString("kind: ProductListing\n"))
+		Expect(err).To(MatchError(resource.ErrInvalidOverlay))
+	})
+
+	It("should read missing overlay files as empty", func() {
+		overlay, exists, err := resource.ReadOverlayFile(filepath.Join(GinkgoT().TempDir(), "missing.yaml"), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+		Expect(overlay.Bytes()).To(BeEmpty())
+	})
+})
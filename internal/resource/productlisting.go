@@ -11,23 +11,23 @@ const (
 )
 
 type ProductListing struct {
-	ID                      string                                `json:"_id,omitempty"`
-	Name                    string                                `json:"name,omitempty"`
-	OrgID                   int                                   `json:"org_id,omitempty"`
-	LastUpdateDate          *time.Time                            `json:"last_update_date,omitempty"`
-	Type                    ProductListingType                    `json:"type,omitempty" jsonschema:"enum=container stack,enum=traditional application,enum=openstack infra"`
-	Descriptions            *ProductListingDescriptions           `json:"descriptions,omitempty"`
-	Contacts                []ProductListingContact               `json:"contacts,omitempty" jsonschema:"minItems=1,maxItems=10"`
-	CreationDate            *time.Time                            `json:"creation_date,omitempty"`
-	CertProjects            []string                              `json:"cert_projects,omitempty"`
-	Support                 *ProductListingSupport                `json:"support,omitempty"`
-	Legal                   *ProductListingLegal                  `json:"legal,omitempty"`
-	LinkedResources         []ProductListingLinkedResource        `json:"linked_resources,omitempty" jsonschema:"minItems=3,maxItems=8"`
-	FAQs                    []FAQ                                 `json:"faqs,omitempty"`
+	ID                      string                                `json:"_id,omitempty" managed:"identity"`
+	Name                    string                                `json:"name,omitempty" jsonschema_description:"The name of the product, as displayed in the catalog."`
+	OrgID                   int                                   `json:"org_id,omitempty" managed:"identity"`
+	LastUpdateDate          *time.Time                            `json:"last_update_date,omitempty" managed:"backend"`
+	Type                    ProductListingType                    `json:"type,omitempty" jsonschema:"enum=container stack,enum=traditional application,enum=openstack infra" jsonschema_description:"The type of product. Determines the kinds of components that may be associated with it."`
+	Descriptions            *ProductListingDescriptions           `json:"descriptions,omitempty" jsonschema_description:"Short and long form descriptions of the product."`
+	Contacts                []ProductListingContact               `json:"contacts,omitempty" jsonschema:"minItems=1,maxItems=10" jsonschema_description:"Marketing and technical contacts for the product."`
+	CreationDate            *time.Time                            `json:"creation_date,omitempty" managed:"backend"`
+	CertProjects            []string                              `json:"cert_projects,omitempty" managed:"apply" jsonschema_description:"The IDs of the components associated with the product listing. Managed using with.components."`
+	Support                 *ProductListingSupport                `json:"support,omitempty" jsonschema_description:"How users of the product can get support."`
+	Legal                   *ProductListingLegal                  `json:"legal,omitempty" jsonschema_description:"Legal documents governing use of the product."`
+	LinkedResources         []ProductListingLinkedResource        `json:"linked_resources,omitempty" jsonschema:"minItems=3,maxItems=8" jsonschema_description:"Videos, articles, documentation and other resources about the product."`
+	FAQs                    []FAQ                                 `json:"faqs,omitempty" jsonschema_description:"Frequently asked questions about the product, and their answers."`
 	SearchAliases           []SearchAlias                         `json:"search_aliases,omitempty" jsonschema:"maxItems=5" jsonschema_description:"A collection of key value pairs used assist users searching for your product listing"`
-	FunctionalCategory      []FunctionalCategory                  `json:"functional_categories,omitempty" jsonschema:"minItems=1,maxItems=3,enum=AI/ML,enum=Analytics,enum=App dev,enum=App modernization,enum=Automation,enum=Backup & Recovery,enum=Cloud,enum=Compute,enum=Content management,enum=Data management,enum=Developer tools,enum=DevOps,enum=Edge,enum=Infrastructure,enum=IT & management tools,enum=Migration,enum=Networking,enum=Observability,enum=Orchestration,enum=OS & platforms,enum=Security,enum=Storage,enum=Virtualization"`
-	QuickStartConfiguration ProductListingQuickStartConfiguration `json:"quick_start_configuration,omitempty"`
-	Features                []ProductListingFeature               `json:"features,omitempty"`
+	FunctionalCategory      []FunctionalCategory                  `json:"functional_categories,omitempty" jsonschema:"minItems=1,maxItems=3,enum=AI/ML,enum=Analytics,enum=App dev,enum=App modernization,enum=Automation,enum=Backup & Recovery,enum=Cloud,enum=Compute,enum=Content management,enum=Data management,enum=Developer tools,enum=DevOps,enum=Edge,enum=Infrastructure,enum=IT & management tools,enum=Migration,enum=Networking,enum=Observability,enum=Orchestration,enum=OS & platforms,enum=Security,enum=Storage,enum=Virtualization" jsonschema_description:"The functional categories the product is listed under in the catalog."`
+	QuickStartConfiguration ProductListingQuickStartConfiguration `json:"quick_start_configuration,omitempty" jsonschema_description:"Instructions for getting started with the product."`
+	Features                []ProductListingFeature               `json:"features,omitempty" jsonschema_description:"The features of the product."`
 }
 
 func (p *ProductListing) HasName() bool {
@@ -39,41 +39,41 @@ func (p *ProductListing) HasID() bool {
 }
 
 type ProductListingQuickStartConfiguration struct {
-	Instructions string `json:"instructions,omitempty" jsonschema:"minLength=1,maxLength=10000" jsonschema_description:"Quick start instructions for your users. Supports HTML formatting."`
+	Instructions string `json:"instructions,omitempty" jsonschema:"minLength=1,maxLength=10000,format=html" jsonschema_description:"Quick start instructions for your users. Supports HTML formatting."`
 }
 
 type ProductListingFeature struct {
 	Title       string `json:"title,omitempty" jsonschema:"maxLength=60" jsonschema_description:"The title of a supported feature."`
-	Description string `json:"description,omitempty" jsonschema:"maxLength=1000" jsonschema_description:"A description of the titled feature. Supports HTML Formatting."`
+	Description string `json:"description,omitempty" jsonschema:"maxLength=1000,format=html" jsonschema_description:"A description of the titled feature. Supports HTML Formatting."`
 }
 
 type ProductListingSupport struct {
-	URL          string `json:"url,omitempty"`
-	Description  string `json:"description,omitempty" jsonschema:"minLength=1,maxLength-=500"`
-	EmailAddress string `json:"email_address,omitempty"`
-	PhoneNumber  string `json:"phone_number,omitempty" jsonschema:"minLength=1,maxLength=50"`
+	URL          string `json:"url,omitempty" jsonschema_description:"A page describing how to get support for the product."`
+	Description  string `json:"description,omitempty" jsonschema:"minLength=1,maxLength=500" jsonschema_description:"A description of the support available for the product."`
+	EmailAddress string `json:"email_address,omitempty" jsonschema_description:"An email address for support requests."`
+	PhoneNumber  string `json:"phone_number,omitempty" jsonschema:"minLength=1,maxLength=50" jsonschema_description:"A phone number for support requests."`
 }
 
 type ProductListingLegal struct {
-	LicenseAgreementURL string `json:"license_agreement_url,omitempty"`
-	PrivacyPolicyURL    string `json:"privacy_policy_url,omitempty"`
+	LicenseAgreementURL string `json:"license_agreement_url,omitempty" jsonschema_description:"The location of the license agreement for the product."`
+	PrivacyPolicyURL    string `json:"privacy_policy_url,omitempty" jsonschema_description:"The location of the privacy policy for the product."`
 }
 
 type ProductListingDescriptions struct {
-	Long  string `json:"long,omitempty"`
-	Short string `json:"short,omitempty" jsonschema:"minLength=50"`
+	Long  string `json:"long,omitempty" jsonschema:"format=html" jsonschema_description:"A long form description of the product. Supports HTML formatting."`
+	Short string `json:"short,omitempty" jsonschema:"minLength=50" jsonschema_description:"A brief synopsis of the product, displayed in search results."`
 }
 
 type ProductListingContact struct {
-	EmailAddress string `json:"email_address,omitempty"`
-	Type         string `json:"type,omitempty" jsonschema:"enum=Marketing contact,enum=Technical contact"`
+	EmailAddress string `json:"email_address,omitempty" jsonschema_description:"The email address of the contact."`
+	Type         string `json:"type,omitempty" jsonschema:"enum=Marketing contact,enum=Technical contact" jsonschema_description:"The kind of contact."`
 }
 
 type ProductListingLinkedResource struct {
-	Title       string             `json:"title,omitempty"`
-	Description string             `json:"description,omitempty"`
-	Type        LinkedResourceType `json:"type,omitempty" jsonschema:"enum=Video,enum=Article,enum=Documentation,enum=Website,enum=Podcasts,enum=On-demand Events"`
-	URL         string             `json:"url,omitempty"`
+	Title       string             `json:"title,omitempty" jsonschema_description:"The title of the resource."`
+	Description string             `json:"description,omitempty" jsonschema_description:"A description of the resource."`
+	Type        LinkedResourceType `json:"type,omitempty" jsonschema:"enum=Video,enum=Article,enum=Documentation,enum=Website,enum=Podcasts,enum=On-demand Events" jsonschema_description:"The kind of resource."`
+	URL         string             `json:"url,omitempty" jsonschema_description:"The location of the resource."`
 }
 
 type LinkedResourceType = string
@@ -89,7 +89,7 @@ const (
 
 type FAQ struct {
 	Question string `json:"question,omitempty" jsonschema:"maxLength=500" jsonschema_description:"Common questions"`
-	Answer   string `json:"answer,omitempty" jsonschema:"maxLength=10000" jsonschema_description:"Answers to your questions. May contain HTML."`
+	Answer   string `json:"answer,omitempty" jsonschema:"maxLength=10000,format=html" jsonschema_description:"Answers to your questions. May contain HTML."`
 }
 
 type SearchAlias struct {
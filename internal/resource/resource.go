@@ -3,17 +3,24 @@
 // differ in subtle ways that enable the on-disk representation.
 package resource
 
+import "reflect"
+
+// KindProductListing is the kind of product listing declarations.
+const KindProductListing = "ProductListing"
+
 type ProductListingDeclaration struct {
-	Kind string         `json:"kind"`
-	Spec ProductListing `json:"spec"`
-	With Inclusions     `json:"with,omitempty"`
+	APIVersion string         `json:"apiVersion,omitempty" jsonschema:"enum=productctl.opdev.io/v1" jsonschema_description:"The version of the declaration format. Declarations of previous versions are converted when read, and can be upgraded with productctl product migrate."`
+	Kind       string         `json:"kind" jsonschema_description:"The kind of resource declared. Must be ProductListing."`
+	Spec       ProductListing `json:"spec" jsonschema_description:"The product listing."`
+	With       Inclusions     `json:"with,omitempty" jsonschema_description:"Resources that are managed alongside the product listing."`
 }
 
 // NewProductListing returns a net-new product listing declaration.
 func NewProductListing() ProductListingDeclaration {
 	return ProductListingDeclaration{
-		Kind: "ProductListing",
-		Spec: ProductListing{},
+		APIVersion: CurrentAPIVersion,
+		Kind:       KindProductListing,
+		Spec:       ProductListing{},
 	}
 }
 
@@ -22,29 +29,18 @@ func NewProductListing() ProductListingDeclaration {
 type Inclusions struct {
 	// Components represent the certification projects associated with the given
 	// product listing.
-	Components []*Component `json:"components,omitempty"`
+	Components []*Component `json:"components,omitempty" jsonschema_description:"The components, i.e. certification projects, associated with the product listing."`
+	// ComponentRefs reference components managed by their own declarations,
+	// which are attached to the product listing without being embedded in it.
+	ComponentRefs []*ComponentReference `json:"component_refs,omitempty" jsonschema_description:"Components managed by their own declarations, attached to the product listing by ID or by file."`
 }
 
 // Sanitize removes identifiers that tie this declaration to a specific entity
-// in the Catalog. Common for cases where a given declaration is going to be
-// stored for re-use.
+// in the Catalog, and every other value that is not managed by the user.
+// Common for cases where a given declaration is going to be stored for re-use.
+// References to components managed by their own declarations are retained.
 func (d *ProductListingDeclaration) Sanitize() {
-	d.Spec.CertProjects = nil
-	d.Spec.CreationDate = nil
-	d.Spec.LastUpdateDate = nil
-	d.Spec.ID = ""
-	d.Spec.OrgID = 0
-
-	for i := range d.With.Components {
-		d.With.Components[i].ID = ""
-		d.With.Components[i].OrgID = 0
-		if c := d.With.Components[i].Container; c != nil {
-			c.PID = ""
-		}
-		d.With.Components[i].CreationDate = nil
-		d.With.Components[i].LastUpdateDate = nil
-		d.With.Components[i].CertificationDate = nil
-	}
+	clearManaged(reflect.ValueOf(d))
 }
 
 func (d *ProductListingDeclaration) HasComponents() bool {
@@ -23,19 +23,25 @@ var _ = Describe("Resource", func() {
 					LastUpdateDate: &time.Time{},
 					Type:           resource.ProductListingTypeContainerStack,
 					CreationDate:   &time.Time{},
+					CertProjects:   []string{"c123"},
 				}
 				new.With = resource.Inclusions{
 					Components: []*resource.Component{
 						{
-							ID:                "c123",
-							CertificationDate: &time.Time{},
-							Name:              "cname",
-							OrgID:             1234,
-							Type:              resource.ComponentTypeContainer,
-							CreationDate:      &time.Time{},
-							LastUpdateDate:    &time.Time{},
+							ID:                 "c123",
+							CertificationDate:  &time.Time{},
+							Name:               "cname",
+							OrgID:              1234,
+							Type:               resource.ComponentTypeContainer,
+							CreationDate:       &time.Time{},
+							LastUpdateDate:     &time.Time{},
+							Badges:             []string{"certified"},
+							PublishedBy:        "isv",
+							CertificationLevel: "Certified",
+							Container:          &resource.ContainerComponent{PID: "p123", Registry: "quay.io"},
 						},
 					},
+					ComponentRefs: []*resource.ComponentReference{{ID: "r123"}},
 				}
 
 				declaration = &new
@@ -60,13 +66,26 @@ var _ = Describe("Resource", func() {
 					Expect(c.OrgID).To(BeZero())
 				}
 			})
+			It("should unset values managed by the backend or by apply", func() {
+				Expect(declaration.Spec.CertProjects).To(BeEmpty())
+				for _, c := range declaration.With.Components {
+					Expect(c.Badges).To(BeEmpty())
+					Expect(c.PublishedBy).To(BeEmpty())
+					Expect(c.CertificationLevel).To(BeEmpty())
+					Expect(c.Container.PID).To(BeEmpty())
+				}
+			})
 			It("should leave component information intact", func() {
 				Expect(declaration.Spec.Name).To(Equal("test-fixture"))
 				Expect(declaration.Spec.Type).To(Equal(resource.ProductListingTypeContainerStack))
 				for _, c := range declaration.With.Components {
 					Expect(c.Name).To(Equal("cname"))
+					Expect(c.Container.Registry).To(Equal("quay.io"))
 				}
 			})
+			It("should retain references to components managed by their own declarations", func() {
+				Expect(declaration.With.ComponentRefs).To(ConsistOf(HaveField("ID", "r123")))
+			})
 		})
 
 		When("the declaration has components", func() {
@@ -0,0 +1,54 @@
+package resource
+
+import (
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+// SchemaVersion is the version of the JSON Schema for product listing
+// declarations. It changes only when a declaration that was previously valid
+// is no longer valid.
+const SchemaVersion = "v1"
+
+// SchemaID is the stable $id of the JSON Schema for product listing
+// declarations. It resolves to the schema committed to the repository, which
+// editors use to validate declarations referencing it in a modeline.
+const SchemaID = schemaBaseURL + "productlisting." + SchemaVersion + ".json"
+
+// ComponentSchemaID is the stable $id of the JSON Schema for component
+// declarations, as SchemaID is for product listing declarations.
+const ComponentSchemaID = schemaBaseURL + "component." + SchemaVersion + ".json"
+
+const schemaBaseURL = "https://raw.githubusercontent.com/opdev/productctl/main/schemas/"
+
+// SchemaModeline returns the comment that associates a YAML document with the
+// schema at schemaURL for editors using the YAML language server.
+func SchemaModeline(schemaURL string) string {
+	return "# yaml-language-server: $schema=" + schemaURL + "\n"
+}
+
+// JSONSchema returns the JSON Schema for product listing declarations,
+// reflected from the declaration types and their jsonschema struct tags.
+// Fields that are not managed by the user, per their managed struct tags, are
+// read-only.
+func JSONSchema() *jsonschema.Schema {
+	schema := jsonschema.Reflect(&ProductListingDeclaration{})
+	markManaged(schema, reflect.TypeFor[ProductListingDeclaration](), map[reflect.Type]bool{})
+	schema.ID = jsonschema.ID(SchemaID)
+	schema.Title = "productctl " + KindProductListing + " declaration"
+	schema.Description = "A product listing, and the components it includes, as declared on disk for use with productctl. Schema version " + SchemaVersion + "."
+
+	return schema
+}
+
+// ComponentJSONSchema returns the JSON Schema for component declarations.
+func ComponentJSONSchema() *jsonschema.Schema {
+	schema := jsonschema.Reflect(&ComponentDeclaration{})
+	markManaged(schema, reflect.TypeFor[ComponentDeclaration](), map[reflect.Type]bool{})
+	schema.ID = jsonschema.ID(ComponentSchemaID)
+	schema.Title = "productctl " + KindComponent + " declaration"
+	schema.Description = "A component, as declared on disk for use with productctl, so that it can be referenced by several product listings. Schema version " + SchemaVersion + "."
+
+	return schema
+}
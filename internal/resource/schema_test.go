@@ -0,0 +1,52 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/invopop/jsonschema"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("JSONSchema", func() {
+	It("should carry a stable, versioned ID", func() {
+		Expect(string(resource.JSONSchema().ID)).To(Equal(resource.SchemaID))
+		Expect(resource.SchemaID).To(ContainSubstring(resource.SchemaVersion))
+		Expect(string(resource.ComponentJSONSchema().ID)).To(Equal(resource.ComponentSchemaID))
+	})
+
+	It("should mark fields managed by the backend as read-only", func() {
+		schema := resource.JSONSchema()
+		for definition, properties := range map[string][]string{
+			"ProductListing":     {"_id", "org_id", "creation_date", "last_update_date", "cert_projects"},
+			"Component":          {"_id", "org_id", "creation_date", "last_update_date", "certification_date", "badges", "published_by", "certification_level"},
+			"ContainerComponent": {"isv_pid"},
+		} {
+			for _, name := range properties {
+				property, ok := schema.Definitions[definition].Properties.Get(name)
+				Expect(ok).To(BeTrue(), definition+"."+name)
+				Expect(property.ReadOnly).To(BeTrue(), definition+"."+name)
+			}
+		}
+
+		name, _ := schema.Definitions["ProductListing"].Properties.Get("name")
+		Expect(name.ReadOnly).To(BeFalse())
+		Expect(name.Description).ToNot(BeEmpty())
+	})
+
+	DescribeTable("should match the published schema",
+		func(name string, schema *jsonschema.Schema) {
+			published, err := os.ReadFile("../../schemas/" + name + "." + resource.SchemaVersion + ".json")
+			Expect(err).ToNot(HaveOccurred())
+
+			generated, err := json.MarshalIndent(schema, "", "  ")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(published)).To(Equal(string(generated)+"\n"), "regenerate the published schema with make generate.jsonschema")
+		},
+		Entry("product listings", "productlisting", resource.JSONSchema()),
+		Entry("components", "component", resource.ComponentJSONSchema()),
+	)
+})
@@ -0,0 +1,314 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// Stream is a multi-document YAML stream of declarations of a single kind.
+// The original content of each document is retained so that individual
+// declarations can be replaced without reformatting the others.
+type Stream[T any] struct {
+	documents []*streamDocument[T]
+	// baseDir is the directory files included by declarations are relative
+	// to.
+	baseDir string
+	// variables are the variables declarations were interpolated with, if
+	// any.
+	variables VariableLookup
+	// includeUpdates holds the new content of included files, by filename.
+	includeUpdates map[string][]byte
+}
+
+// DeclarationStream is a stream of product listing declarations.
+type DeclarationStream = Stream[*ProductListingDeclaration]
+
+// ComponentStream is a stream of component declarations.
+type ComponentStream = Stream[*ComponentDeclaration]
+
+type streamDocument[T any] struct {
+	// separator is the document separator line that precedes this document,
+	// including its line ending, exactly as it was read. It is empty for the
+	// first document if the stream did not begin with a separator.
+	separator []byte
+	raw       []byte
+	// hasDeclaration is false for documents that contain no data, such as
+	// those containing only comments.
+	hasDeclaration bool
+	declaration    T
+}
+
+// ReadDeclarationStream reads all YAML documents from the io.Reader, each of
+// which is expected to be a product listing declaration. Documents that
+// contain no data are retained, but are not considered declarations. Options
+// are applied to every declaration, and line numbers in errors are relative to
+// the start of the stream.
+func ReadDeclarationStream(in io.Reader, opts ...ReadOption) (*DeclarationStream, error) {
+	return readStream(in, ReadProductListing, opts...)
+}
+
+// ReadComponentStream reads all YAML documents from the io.Reader, each of
+// which is expected to be a component declaration, as ReadDeclarationStream
+// does for product listing declarations.
+func ReadComponentStream(in io.Reader, opts ...ReadOption) (*ComponentStream, error) {
+	return readStream(in, ReadComponent, opts...)
+}
+
+func readStream[T any](in io.Reader, read func(io.Reader, ...ReadOption) (T, error), opts ...ReadOption) (*Stream[T], error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	options := readOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	stream := &Stream[T]{documents: splitDocuments[T](b), baseDir: options.baseDir, variables: options.variables, includeUpdates: map[string][]byte{}}
+	linesBefore := 0
+	for i, doc := range stream.documents {
+		linesBefore += bytes.Count(doc.separator, []byte("\n"))
+		offset := linesBefore
+		linesBefore += bytes.Count(doc.raw, []byte("\n"))
+
+		empty, err := isEmptyDocument(doc.raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		if empty {
+			continue
+		}
+
+		docOpts := opts
+		if options.warn != nil {
+			docOpts = append(slices.Clip(opts), WithWarningHandler(func(warning string) {
+				options.warn(fmt.Sprintf("document %d: %s", i+1, warning))
+			}))
+		}
+
+		declaration, err := read(bytes.NewReader(doc.raw), docOpts...)
+		if err != nil {
+			var unknownFields *UnknownFieldsError
+			if errors.As(err, &unknownFields) {
+				unknownFields.offsetLines(offset)
+			}
+			var interpolation *InterpolationError
+			if errors.As(err, &interpolation) {
+				interpolation.offsetLines(offset)
+			}
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		doc.declaration = declaration
+		doc.hasDeclaration = true
+	}
+
+	return stream, nil
+}
+
+// Declarations returns the declarations in the stream, in order. Indexes
+// into the returned slice are accepted by Replace.
+func (s *Stream[T]) Declarations() []T {
+	declarations := []T{}
+	for _, doc := range s.documents {
+		if doc.hasDeclaration {
+			declarations = append(declarations, doc.declaration)
+		}
+	}
+
+	return declarations
+}
+
+// Replace replaces the declaration at index i of Declarations with
+// declaration. Only the content of that document changes, apart from any
+// comments preceding its content, such as a schema modeline. Values that were
+// variable references in the document are written as those references, rather
+// than the values they were interpolated with, and the values of included
+// files remain included, with any changes to them held for IncludeUpdates.
+// All other documents retain their original content.
+func (s *Stream[T]) Replace(i int, declaration T) error {
+	doc := s.nthDeclarationDocument(i)
+	if doc == nil {
+		return fmt.Errorf("no declaration at index %d", i)
+	}
+
+	b, err := yaml.Marshal(declaration)
+	if err != nil {
+		return err
+	}
+
+	if b, err = restoreSources(doc.raw, b, s.baseDir, s.variables, s.includeUpdates); err != nil {
+		return err
+	}
+
+	doc.raw = append(leadingComments(doc.raw), b...)
+	doc.declaration = declaration
+	return nil
+}
+
+// IncludeUpdates returns the new content of each file included by the
+// declarations that changed as they were replaced, by filename. Updates are
+// returned once, and not again by later calls.
+func (s *Stream[T]) IncludeUpdates() map[string][]byte {
+	updates := s.includeUpdates
+	s.includeUpdates = map[string][]byte{}
+	return updates
+}
+
+// Line returns the line of the stream, counted from 1, holding the value at
+// path in the declaration at index i of Declarations, e.g.
+// spec.linked_resources[2].url. If the value is not written in the stream,
+// e.g. because it is included from another file, the line of the nearest
+// value holding it is returned. It returns 0 if no such value is found.
+func (s *Stream[T]) Line(i int, path string) int {
+	linesBefore := 0
+	seen := 0
+	var doc *streamDocument[T]
+	for _, d := range s.documents {
+		linesBefore += bytes.Count(d.separator, []byte("\n"))
+		if d.hasDeclaration {
+			if seen == i {
+				doc = d
+				break
+			}
+			seen++
+		}
+		linesBefore += bytes.Count(d.raw, []byte("\n"))
+	}
+
+	if doc == nil {
+		return 0
+	}
+
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(doc.raw, &root); err != nil {
+		return 0
+	}
+
+	// Values are located by their keys, so that mappings and sequences are
+	// located where they begin rather than at their first element.
+	lines := map[string]int{}
+	walkValues(&root, "", func(node *yamlv3.Node, path string) {
+		lines[path] = node.Line
+		if node.Kind == yamlv3.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				lines[joinFieldPath(path, node.Content[i].Value)] = node.Content[i].Line
+			}
+		}
+	})
+
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return linesBefore + line
+		}
+
+		path = path[:max(strings.LastIndexAny(path, ".["), 0)]
+	}
+
+	return 0
+}
+
+// Bytes returns the full content of the stream.
+func (s *Stream[T]) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, doc := range s.documents {
+		buf.Write(doc.separator)
+		buf.Write(doc.raw)
+	}
+
+	return buf.Bytes()
+}
+
+func (s *Stream[T]) nthDeclarationDocument(i int) *streamDocument[T] {
+	seen := 0
+	for _, doc := range s.documents {
+		if !doc.hasDeclaration {
+			continue
+		}
+
+		if seen == i {
+			return doc
+		}
+		seen++
+	}
+
+	return nil
+}
+
+// splitDocuments splits b into documents at each document separator line.
+func splitDocuments[T any](b []byte) []*streamDocument[T] {
+	documents := []*streamDocument[T]{}
+	current := &streamDocument[T]{}
+
+	reader := bufio.NewReader(bytes.NewReader(b))
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if isDocumentSeparator(line) {
+				if len(current.separator) > 0 || len(current.raw) > 0 {
+					documents = append(documents, current)
+				}
+				current = &streamDocument[T]{separator: line}
+			} else {
+				current.raw = append(current.raw, line...)
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	return append(documents, current)
+}
+
+// leadingComments returns the comment and blank lines at the start of the YAML
+// document b.
+func leadingComments(b []byte) []byte {
+	end := 0
+	for end < len(b) {
+		line := b[end:]
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+
+		trimmed := bytes.TrimSpace(line)
+		if len(trimmed) > 0 && trimmed[0] != '#' {
+			break
+		}
+		end += len(line)
+	}
+
+	return bytes.Clone(b[:end])
+}
+
+// isDocumentSeparator returns true if line marks the start of a new YAML
+// document, i.e. "---" optionally followed by whitespace or a comment.
+func isDocumentSeparator(line []byte) bool {
+	if !bytes.HasPrefix(line, []byte("---")) {
+		return false
+	}
+
+	rest := line[3:]
+	return len(rest) == 0 || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n'
+}
+
+// isEmptyDocument returns true if the YAML document b contains no data.
+func isEmptyDocument(b []byte) (bool, error) {
+	j, err := yaml.YAMLToJSON(b)
+	if err != nil {
+		return false, err
+	}
+
+	return string(bytes.TrimSpace(j)) == "null", nil
+}
@@ -0,0 +1,119 @@
+package resource_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Stream", func() {
+	When("reading a multi-document stream", func() {
+		var content string
+
+		BeforeEach(func() {
+			content = `# leading comment, preserved
+---
+kind: ProductListing
+spec:
+  name:     first   # odd formatting, preserved
+---   # a separator with a comment
+# only a comment
+--- 
+kind: ProductListing
+spec:
+  name: second
+`
+		})
+
+		It("should return each declaration in order, skipping empty documents", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content))
+			Expect(err).ToNot(HaveOccurred())
+			declarations := stream.Declarations()
+			Expect(declarations).To(HaveLen(2))
+			Expect(declarations[0].Spec.Name).To(Equal("first"))
+			Expect(declarations[1].Spec.Name).To(Equal("second"))
+		})
+
+		It("should reproduce the original content exactly when nothing is replaced", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(stream.Bytes())).To(Equal(content))
+		})
+
+		It("should only change the replaced document", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content))
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := stream.Declarations()[1]
+			updated.Spec.ID = "abc123"
+			Expect(stream.Replace(1, updated)).To(Succeed())
+
+			out := string(stream.Bytes())
+			Expect(out).To(HavePrefix(`# leading comment, preserved
+---
+kind: ProductListing
+spec:
+  name:     first   # odd formatting, preserved
+---   # a separator with a comment
+# only a comment
+--- 
+`))
+			Expect(out).To(ContainSubstring("_id: abc123"))
+
+			reread, err := resource.ReadDeclarationStream(bytes.NewBufferString(out))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reread.Declarations()).To(HaveLen(2))
+			Expect(reread.Declarations()[1].Spec.ID).To(Equal("abc123"))
+		})
+
+		It("should retain the comments preceding a replaced declaration", func() {
+			modeline := resource.SchemaModeline(resource.SchemaID)
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(modeline + "\n# notes\nkind: ProductListing\nspec:\n  name: first\n"))
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := stream.Declarations()[0]
+			updated.Spec.ID = "abc123"
+			Expect(stream.Replace(0, updated)).To(Succeed())
+
+			out := string(stream.Bytes())
+			Expect(out).To(HavePrefix(modeline + "\n# notes\n"))
+			Expect(out).To(ContainSubstring("_id: abc123"))
+		})
+
+		It("should refuse to replace declarations that do not exist", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.Replace(2, &resource.ProductListingDeclaration{})).ToNot(Succeed())
+		})
+
+		It("should locate values by their path within each declaration", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString(content))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.Line(0, "spec.name")).To(Equal(5))
+			Expect(stream.Line(1, "spec.name")).To(Equal(11))
+			By("falling back to the nearest value holding a missing value")
+			Expect(stream.Line(1, "spec.descriptions.short")).To(Equal(10))
+			Expect(stream.Line(2, "spec.name")).To(Equal(0))
+		})
+	})
+
+	When("reading a single document without separators", func() {
+		It("should return the declaration", func() {
+			stream, err := resource.ReadDeclarationStream(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: only\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.Declarations()).To(HaveLen(1))
+			Expect(stream.Declarations()[0].Spec.Name).To(Equal("only"))
+		})
+	})
+
+	When("a document is not a declaration", func() {
+		It("should report which document failed", func() {
+			_, err := resource.ReadDeclarationStream(bytes.NewBufferString("kind: ProductListing\n---\npackage resource\n"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("document 2"))
+		})
+	})
+})
@@ -0,0 +1,170 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+var ErrUnknownFields = errors.New("declaration contains unknown fields")
+
+// UnknownField is a field found in a declaration that does not correspond to
+// any field of the resource it declares.
+type UnknownField struct {
+	// Path is the location of the field within the declaration, e.g.
+	// with.components[0].container.short_descripton
+	Path   string
+	Line   int
+	Column int
+}
+
+// UnknownFieldsError reports every unknown field found in a declaration.
+type UnknownFieldsError struct {
+	Fields []UnknownField
+}
+
+func (e *UnknownFieldsError) Error() string {
+	lines := make([]string, 0, len(e.Fields)+1)
+	lines = append(lines, ErrUnknownFields.Error()+":")
+	for _, f := range e.Fields {
+		lines = append(lines, fmt.Sprintf("  line %d, column %d: %s", f.Line, f.Column, f.Path))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *UnknownFieldsError) Is(target error) bool {
+	return target == ErrUnknownFields
+}
+
+// offsetLines shifts the reported line numbers by n, for documents that are
+// part of a larger stream.
+func (e *UnknownFieldsError) offsetLines(n int) {
+	for i := range e.Fields {
+		e.Fields[i].Line += n
+	}
+}
+
+// checkUnknownFields returns an *UnknownFieldsError if the YAML document root
+// contains fields that do not correspond to a field of target, identified by
+// their JSON tags. Unlike decoding, field names are matched case-sensitively.
+func checkUnknownFields(root *yamlv3.Node, target any) error {
+	if root.Kind != yamlv3.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+
+	found := []UnknownField{}
+	walkUnknownFields(root.Content[0], reflect.TypeOf(target), "", &found)
+	if len(found) == 0 {
+		return nil
+	}
+
+	return &UnknownFieldsError{Fields: found}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func walkUnknownFields(node *yamlv3.Node, t reflect.Type, path string, found *[]UnknownField) {
+	if node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == timeType || node.Kind != yamlv3.MappingNode {
+			return
+		}
+
+		fields := jsonFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				walkUnknownFields(value, t, path, found)
+				continue
+			}
+
+			childPath := joinFieldPath(path, key.Value)
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				*found = append(*found, UnknownField{Path: childPath, Line: key.Line, Column: key.Column})
+				continue
+			}
+
+			walkUnknownFields(value, fieldType, childPath, found)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yamlv3.SequenceNode {
+			return
+		}
+
+		for i, item := range node.Content {
+			walkUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), found)
+		}
+	case reflect.Map:
+		if node.Kind != yamlv3.MappingNode {
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walkUnknownFields(node.Content[i+1], t.Elem(), joinFieldPath(path, node.Content[i].Value), found)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of the fields of struct type t to their
+// types, following the same rules as encoding/json for embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for n, ft := range jsonFields(embedded) {
+					if _, exists := fields[n]; !exists {
+						fields[n] = ft
+					}
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fields[name] = f.Type
+	}
+
+	return fields
+}
+
+func joinFieldPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+
+	return path + "." + field
+}
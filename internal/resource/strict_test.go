@@ -0,0 +1,76 @@
+package resource_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Strict", func() {
+	const misspelled = `kind: ProductListing
+spec:
+  name: Test Product
+  descriptions:
+    shrt: A brief synopsis
+with:
+  components:
+  - name: first
+  - name: second
+    container:
+      short_descripton: oops
+      registry: quay.io
+    Name: capitalized
+`
+
+	It("should ignore unknown fields by default", func() {
+		listing, err := resource.ReadProductListing(bytes.NewBufferString(misspelled))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing.Spec.Name).To(Equal("Test Product"))
+	})
+
+	It("should report every unknown field with its path and line when strict", func() {
+		_, err := resource.ReadProductListing(bytes.NewBufferString(misspelled), resource.WithStrict(true))
+		Expect(err).To(MatchError(resource.ErrUnknownFields))
+
+		var unknownFields *resource.UnknownFieldsError
+		Expect(err).To(BeAssignableToTypeOf(unknownFields))
+		unknownFields = err.(*resource.UnknownFieldsError)
+		Expect(unknownFields.Fields).To(Equal([]resource.UnknownField{
+			{Path: "spec.descriptions.shrt", Line: 5, Column: 5},
+			{Path: "with.components[1].container.short_descripton", Line: 11, Column: 7},
+			{Path: "with.components[1].Name", Line: 13, Column: 5},
+		}))
+		Expect(err.Error()).To(ContainSubstring("line 11, column 7: with.components[1].container.short_descripton"))
+	})
+
+	It("should accept declarations without unknown fields when strict", func() {
+		_, err := resource.ReadProductListing(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: ok\n  contacts:\n  - email_address: a@example.com\n    type: Technical contact\n"), resource.WithStrict(true))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should report line numbers relative to the start of a stream", func() {
+		_, err := resource.ReadDeclarationStream(bytes.NewBufferString("kind: ProductListing\nspec:\n  name: first\n---\n"+misspelled), resource.WithStrict(true))
+		Expect(err).To(MatchError(resource.ErrUnknownFields))
+		Expect(err.Error()).To(ContainSubstring("document 2"))
+		Expect(err.Error()).To(ContainSubstring("line 9, column 5: spec.descriptions.shrt"))
+	})
+
+	DescribeTable("validating the declared kind",
+		func(content string, valid bool) {
+			_, err := resource.ReadProductListing(bytes.NewBufferString(content))
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(resource.ErrUnknownKind))
+		},
+		Entry("a product listing", "kind: ProductListing\nspec: {}\n", true),
+		Entry("an omitted kind", "spec: {}\n", true),
+		Entry("an unknown kind", "kind: Deployment\nspec: {}\n", false),
+		Entry("a misspelled kind", "kind: ProductListng\nspec: {}\n", false),
+		Entry("an empty kind", "kind: \"\"\nspec: {}\n", false),
+	)
+})
@@ -0,0 +1,338 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/invopop/jsonschema"
+
+	"github.com/opdev/productctl/internal/markup"
+)
+
+var ErrInvalidDeclaration = errors.New("declaration does not satisfy its schema")
+
+// formatHTML is the format of string fields that hold HTML, which is checked
+// against the subset of HTML the catalog accepts.
+const formatHTML = "html"
+
+// Violation is a single schema constraint that a declaration does not
+// satisfy.
+type Violation struct {
+	// Path is the location of the offending value within the declaration,
+	// e.g. spec.linked_resources[2].category
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError reports every violation found in a declaration.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations)+1)
+	lines = append(lines, ErrInvalidDeclaration.Error()+":")
+	for _, v := range e.Violations {
+		lines = append(lines, "  "+v.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidDeclaration
+}
+
+// Validate checks the declaration against the constraints of JSONSchema,
+// without contacting the backend. All violations are reported in a
+// *ValidationError.
+func (d *ProductListingDeclaration) Validate() error {
+	var violations []Violation
+	for i, c := range d.With.Components {
+		if c != nil {
+			violations = append(violations, c.detailViolations(fmt.Sprintf("with.components[%d]", i))...)
+		}
+	}
+
+	return validateAgainst(JSONSchema(), d, violations...)
+}
+
+// Validate checks the declaration against the constraints of
+// ComponentJSONSchema, as for product listing declarations.
+func (d *ComponentDeclaration) Validate() error {
+	return validateAgainst(ComponentJSONSchema(), d, d.Spec.detailViolations("spec")...)
+}
+
+// validateAgainst checks declaration against schema, reporting violations
+// found by other checks alongside those of the schema.
+func validateAgainst(schema *jsonschema.Schema, declaration any, violations ...Violation) error {
+	err := validateValue(schema, schema, declaration)
+	if len(violations) == 0 {
+		return err
+	}
+
+	var invalid *ValidationError
+	switch {
+	case err == nil:
+		return &ValidationError{Violations: violations}
+	case errors.As(err, &invalid):
+		invalid.Violations = append(invalid.Violations, violations...)
+		return invalid
+	}
+
+	return err
+}
+
+// validateDefinition checks value against the definition of schema named
+// definition, e.g. "FAQ".
+func validateDefinition(schema *jsonschema.Schema, definition string, value any) error {
+	return validateValue(schema, schema.Definitions[definition], value)
+}
+
+// validateValue checks value against s, a schema within root.
+func validateValue(root, s *jsonschema.Schema, value any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var data any
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	v := &schemaValidator{root: root}
+	v.validate(s, data, "")
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Violations: v.violations}
+}
+
+// maxListedEnumValues is the number of allowed values included in a
+// violation message. Longer enumerations are referred to, rather than listed.
+const maxListedEnumValues = 10
+
+// schemaValidator validates JSON data against the subset of JSON Schema
+// produced by reflecting the declaration types.
+type schemaValidator struct {
+	root       *jsonschema.Schema
+	violations []Violation
+}
+
+func (v *schemaValidator) report(path, format string, args ...any) {
+	if path == "" {
+		path = "."
+	}
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *schemaValidator) validate(s *jsonschema.Schema, data any, path string) {
+	s = v.resolve(s)
+	if s == nil || s == jsonschema.TrueSchema {
+		return
+	}
+
+	if s == jsonschema.FalseSchema {
+		v.report(path, "is not allowed")
+		return
+	}
+
+	if s.Type != "" && !hasType(data, s.Type) {
+		v.report(path, "must be of type %s, found %s", s.Type, typeOf(data))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(allowed any) bool { return reflect.DeepEqual(allowed, data) }) {
+		if len(s.Enum) > maxListedEnumValues {
+			v.report(path, "%s is not one of the %d allowed values, see the jsonschema command", describe(data), len(s.Enum))
+		} else {
+			v.report(path, "%s is not one of the allowed values: %s", describe(data), joinValues(s.Enum))
+		}
+	}
+
+	switch typed := data.(type) {
+	case string:
+		v.validateString(s, typed, path)
+	case []any:
+		v.validateArray(s, typed, path)
+	case map[string]any:
+		v.validateObject(s, typed, path)
+	}
+}
+
+func (v *schemaValidator) validateString(s *jsonschema.Schema, data string, path string) {
+	// The catalog measures the length of every string in UTF-16 code units,
+	// and that of HTML including its markup.
+	length := uint64(markup.Length(data))
+	counted := ""
+	if s.Format == formatHTML {
+		counted = " including markup"
+	}
+
+	if s.MinLength != nil && length < *s.MinLength {
+		v.report(path, "must be at least %d characters long%s, found %d", *s.MinLength, counted, length)
+	}
+
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.report(path, "must be at most %d characters long%s, found %d", *s.MaxLength, counted, length)
+	}
+
+	if s.Format == formatHTML {
+		for _, problem := range markup.Problems(data) {
+			v.report(path, "%s", problem)
+		}
+	}
+
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(data) {
+			v.report(path, "must match the pattern %q", s.Pattern)
+		}
+	}
+
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, data); err != nil {
+			v.report(path, "must be an RFC 3339 date-time")
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s *jsonschema.Schema, data []any, path string) {
+	length := uint64(len(data))
+	if s.MinItems != nil && length < *s.MinItems {
+		v.report(path, "must contain at least %d items, found %d", *s.MinItems, length)
+	}
+
+	if s.MaxItems != nil && length > *s.MaxItems {
+		v.report(path, "must contain at most %d items, found %d", *s.MaxItems, length)
+	}
+
+	if s.UniqueItems {
+		for i := range data {
+			if slices.ContainsFunc(data[:i], func(earlier any) bool { return reflect.DeepEqual(earlier, data[i]) }) {
+				v.report(fmt.Sprintf("%s[%d]", path, i), "duplicates an earlier item")
+			}
+		}
+	}
+
+	if s.Items != nil {
+		for i, item := range data {
+			v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(s *jsonschema.Schema, data map[string]any, path string) {
+	for _, required := range s.Required {
+		if _, ok := data[required]; !ok {
+			v.report(joinFieldPath(path, required), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		var property *jsonschema.Schema
+		if s.Properties != nil {
+			property, _ = s.Properties.Get(k)
+		}
+
+		if property == nil {
+			property = s.AdditionalProperties
+		}
+
+		v.validate(property, data[k], joinFieldPath(path, k))
+	}
+}
+
+// resolve follows local references to the definitions of the root schema.
+func (v *schemaValidator) resolve(s *jsonschema.Schema) *jsonschema.Schema {
+	for s != nil && s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		if !ok {
+			return nil
+		}
+		s = v.root.Definitions[name]
+	}
+
+	return s
+}
+
+func hasType(data any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := data.(map[string]any)
+		return ok
+	case "array":
+		_, ok := data.([]any)
+		return ok
+	case "string":
+		_, ok := data.(string)
+		return ok
+	case "boolean":
+		_, ok := data.(bool)
+		return ok
+	case "number":
+		_, ok := data.(float64)
+		return ok
+	case "integer":
+		n, ok := data.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return data == nil
+	}
+
+	return true
+}
+
+func typeOf(data any) string {
+	switch data.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case nil:
+		return "null"
+	}
+
+	return fmt.Sprintf("%T", data)
+}
+
+func describe(data any) string {
+	if s, ok := data.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	return fmt.Sprintf("%v", data)
+}
+
+func joinValues(values []any) string {
+	described := make([]string, 0, len(values))
+	for _, value := range values {
+		described = append(described, describe(value))
+	}
+
+	return strings.Join(described, ", ")
+}
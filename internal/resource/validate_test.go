@@ -0,0 +1,241 @@
+package resource_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Validate", func() {
+	It("should accept a declaration satisfying the schema", func() {
+		listing, err := resource.ReadProductListing(bytes.NewBufferString(`kind: ProductListing
+spec:
+  name: Valid Product
+  type: container stack
+  functional_categories:
+  - Security
+  descriptions:
+    short: A short description that is long enough to satisfy the schema.
+with:
+  components:
+  - name: first
+    container:
+      type: container
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing.Validate()).To(Succeed())
+	})
+
+	It("should report every violation with its path", func() {
+		listing, err := resource.ReadProductListing(bytes.NewBufferString(`kind: ProductListing
+spec:
+  name: Invalid Product
+  type: not a type
+  functional_categories:
+  - Security
+  - Storage
+  - Cloud
+  - Edge
+  descriptions:
+    short: Too short
+  linked_resources:
+  - url: https://example.com
+    type: Video
+  contacts:
+  - email_address: someone@example.com
+    type: Technical contact
+    description: ""
+with:
+  components:
+  - name: first
+    container:
+      type: container
+      release_categories:
+      - Nightly
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = listing.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+
+		var validationErr *resource.ValidationError
+		Expect(err).To(BeAssignableToTypeOf(validationErr))
+		validationErr = err.(*resource.ValidationError)
+
+		paths := []string{}
+		for _, v := range validationErr.Violations {
+			paths = append(paths, v.Path)
+		}
+		Expect(paths).To(ConsistOf(
+			"spec.descriptions.short",
+			"spec.functional_categories",
+			"spec.linked_resources",
+			"spec.type",
+			"with.components[0].container.release_categories[0]",
+		))
+		Expect(err.Error()).To(ContainSubstring("spec.descriptions.short: must be at least 50 characters long, found 9"))
+		Expect(err.Error()).To(ContainSubstring(`spec.type: "not a type" is not one of the allowed values`))
+	})
+
+	It("should count characters rather than bytes", func() {
+		listing := resource.NewProductListing()
+		listing.Spec.Descriptions = &resource.ProductListingDescriptions{
+			// 50 characters, but more than 50 bytes.
+			Short: "ééééééééééééééééééééééééééééééééééééééééééééééééé!",
+		}
+		Expect(listing.Validate()).To(Succeed())
+	})
+	It("should count characters outside the Basic Multilingual Plane twice in every field", func() {
+		listing := resource.NewProductListing()
+		// 31 emoji, which the catalog counts as 62 characters.
+		listing.Spec.Features = []resource.ProductListingFeature{{Title: strings.Repeat("🚀", 31)}}
+
+		err := listing.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring("spec.features[0].title: must be at most 60 characters long, found 62"))
+	})
+
+	It("should check fields that hold HTML against the HTML the catalog accepts", func() {
+		listing := resource.NewProductListing()
+		listing.Spec.FAQs = []resource.FAQ{{Question: "Is it safe?", Answer: "<p>Yes<script>alert(1)</script>"}}
+		listing.Spec.Features = []resource.ProductListingFeature{{Title: "Fast", Description: "<p><b>Very</p>"}}
+		listing.Spec.QuickStartConfiguration.Instructions = `<p>See <a href="https://example.com">the docs</a>.</p>`
+
+		err := listing.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring("spec.faqs[0].answer: HTML is not accepted by the catalog: <script> elements"))
+		Expect(err.Error()).To(ContainSubstring("spec.faqs[0].answer: malformed HTML: <p> is not closed"))
+		Expect(err.Error()).To(ContainSubstring("spec.features[0].description: malformed HTML: <b> is not closed before </p>"))
+		Expect(err.Error()).ToNot(ContainSubstring("instructions"))
+	})
+
+	It("should count the length of HTML as the catalog does", func() {
+		listing := resource.NewProductListing()
+		// 995 characters of text, where the emoji counts twice, and 7 of markup.
+		listing.Spec.Features = []resource.ProductListingFeature{{Title: "Fast", Description: "<p>" + strings.Repeat("a", 994) + "🚀</p>"}}
+
+		err := listing.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring("spec.features[0].description: must be at most 1000 characters long including markup, found 1003"))
+	})
+
+	It("should accept the details of each type of component", func() {
+		listing, err := resource.ReadProductListing(bytes.NewBufferString(`kind: ProductListing
+spec:
+  name: Infrastructure
+  type: openstack infra
+with:
+  components:
+  - name: driver
+    type: OpenStack-infra-noncontainer
+    non_container:
+      source_system_id: abc123
+    openstack:
+      service: cinder
+  - name: agent
+    type: RHEL
+    non_container:
+      source_system_id: def456
+    redhat:
+      product_name: Red Hat Enterprise Linux
+      product_version: "9"
+    self_certification:
+      requested: true
+      certification_url: https://example.com/evidence
+  - name: vm
+    type: OpenShift-virtualization
+    container:
+      type: container
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing.Validate()).To(Succeed())
+	})
+
+	It("should report details that do not apply to the type of the component", func() {
+		listing, err := resource.ReadProductListing(bytes.NewBufferString(`kind: ProductListing
+spec:
+  name: Traditional
+  type: traditional application
+with:
+  components:
+  - name: agent
+    type: RHEL
+    container:
+      type: container
+    openstack:
+      service: nova
+  - name: untyped
+    helm_chart:
+      chart_name: mine
+`))
+		Expect(err).ToNot(HaveOccurred())
+
+		err = listing.Validate()
+		Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+		Expect(err.Error()).To(ContainSubstring(`with.components[0].container: only applies to components of type Containers, OpenShift-cnf, OpenShift-virtualization, OpenStack-infra-container, OpenStack-vnf, OpenStack-app-container, found "RHEL"`))
+		Expect(err.Error()).To(ContainSubstring("with.components[0].openstack: only applies to components of type OpenStack-"))
+		Expect(err.Error()).ToNot(ContainSubstring("components[1]"))
+
+		component := resource.NewComponentDeclaration()
+		component.Spec = resource.Component{Name: "chart", Type: resource.ComponentTypeContainer, HelmChart: &resource.HelmChartComponent{}}
+		Expect(component.Validate()).To(MatchError(ContainSubstring("spec.helm_chart: only applies to components of type Helm Chart")))
+	})
+
+	It("should report unknown component types", func() {
+		component := resource.NewComponentDeclaration()
+		component.Spec = resource.Component{Name: "mine", Type: "Mainframe"}
+		Expect(component.Validate()).To(MatchError(ContainSubstring(`spec.type: "Mainframe" is not one of the allowed values`)))
+	})
+
+	When("validating Helm chart components", func() {
+		// The base64 encoding of an ASCII armored public key.
+		armoredKey := "LS0tLS1CRUdJTiBQR1AgUFVCTElDIEtFWSBCTE9DSy0tLS0tCgptRE1FWjJJUHd4WUpLd1lCQkFIYVJ3OEJBUWRBCi0tLS0tRU5EIFBHUCBQVUJMSUMgS0VZIEJMT0NLLS0tLS0K"
+
+		chart := func(h resource.HelmChartComponent) *resource.ComponentDeclaration {
+			component := resource.NewComponentDeclaration()
+			component.Spec = resource.Component{Name: "chart", Type: resource.ComponentTypeHelmChart, HelmChart: &h}
+			return &component
+		}
+
+		It("should accept a complete chart", func() {
+			Expect(chart(resource.HelmChartComponent{
+				ChartName:          "mine",
+				DistributionMethod: resource.HelmChartDistributionMethodRedHat,
+				Namespace:          "my-org",
+				OCPVersions:        []string{"4.14", "4.15"},
+				PublicPGPKey:       armoredKey,
+				Signed:             true,
+				GitHubPullRequest:  "https://github.com/openshift-helm-charts/charts/pull/1234",
+			}).Validate()).To(Succeed())
+		})
+
+		It("should accept binary keys", func() {
+			Expect(chart(resource.HelmChartComponent{PublicPGPKey: "xjMEZ2IPwxYJKwYBBAHaRw8BAQdA"}).Validate()).To(Succeed())
+		})
+
+		It("should require a PGP key for signed charts distributed by Red Hat", func() {
+			err := chart(resource.HelmChartComponent{DistributionMethod: resource.HelmChartDistributionMethodRedHat, Signed: true}).Validate()
+			Expect(err).To(MatchError(ContainSubstring("spec.helm_chart.public_pgp_key: is required for signed charts distributed by Red Hat")))
+
+			Expect(chart(resource.HelmChartComponent{DistributionMethod: resource.HelmChartDistributionMethodRedHat}).Validate()).To(Succeed())
+			Expect(chart(resource.HelmChartComponent{DistributionMethod: resource.HelmChartDistributionMethodExternal, Signed: true}).Validate()).To(Succeed())
+		})
+
+		It("should report values the catalog does not accept", func() {
+			err := chart(resource.HelmChartComponent{
+				OCPVersions:       []string{"4.14", "latest", "4.14"},
+				PublicPGPKey:      "not a key",
+				GitHubPullRequest: "https://example.com/pull/1",
+			}).Validate()
+			Expect(err).To(MatchError(resource.ErrInvalidDeclaration))
+			Expect(err.Error()).To(ContainSubstring("spec.helm_chart.ocp_versions[1]: must match the pattern"))
+			Expect(err.Error()).To(ContainSubstring("spec.helm_chart.ocp_versions[2]: duplicates an earlier item"))
+			Expect(err.Error()).To(ContainSubstring("spec.helm_chart.public_pgp_key: must be a base64 encoded PGP public key"))
+			Expect(err.Error()).To(ContainSubstring("spec.helm_chart.github_pull_request: must match the pattern"))
+		})
+	})
+})
@@ -0,0 +1,246 @@
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+const (
+	// APIVersionV1Alpha1 is the version of declarations written before
+	// declarations were versioned, i.e. those without an apiVersion.
+	//
+	// Deprecated: Use APIVersionV1.
+	APIVersionV1Alpha1 = "productctl.opdev.io/v1alpha1"
+	// APIVersionV1 is the first explicitly versioned declaration format.
+	APIVersionV1 = "productctl.opdev.io/v1"
+
+	// CurrentAPIVersion is the version of declarations written by productctl.
+	// Declarations of any other known version are converted to it when read.
+	CurrentAPIVersion = APIVersionV1
+)
+
+var ErrUnknownAPIVersion = errors.New("unknown apiVersion")
+
+// conversion converts a declaration document to the version to, in place,
+// reporting whether it changed the document. Conversions do not need to set
+// the apiVersion of the document.
+type conversion struct {
+	to      string
+	convert func(declaration *yamlv3.Node) (bool, error)
+}
+
+// conversions maps each previous version to the conversion to the version
+// that follows it. Conversions are chained until the declaration is of
+// CurrentAPIVersion.
+var conversions = map[string]conversion{
+	// Declarations of v1 have the same shape as those written before
+	// declarations were versioned.
+	APIVersionV1Alpha1: {to: APIVersionV1, convert: func(*yamlv3.Node) (bool, error) { return false, nil }},
+}
+
+// migrateDocument converts the YAML document root to CurrentAPIVersion in
+// place, returning the version it was declared as, and whether any conversion
+// changed more than its apiVersion.
+func migrateDocument(root *yamlv3.Node) (string, bool, error) {
+	if root.Kind != yamlv3.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yamlv3.MappingNode {
+		return CurrentAPIVersion, false, nil
+	}
+
+	declaration := root.Content[0]
+	from := APIVersionV1Alpha1
+	if value := mappingValue(declaration, "apiVersion"); value != nil {
+		from = value.Value
+	}
+
+	version := from
+	changed := false
+	for version != CurrentAPIVersion {
+		c, ok := conversions[version]
+		if !ok {
+			return from, changed, fmt.Errorf("%w %q, expected %q", ErrUnknownAPIVersion, version, CurrentAPIVersion)
+		}
+
+		converted, err := c.convert(declaration)
+		if err != nil {
+			return from, changed, fmt.Errorf("converting from %s to %s: %w", version, c.to, err)
+		}
+		changed = changed || converted
+
+		version = c.to
+		setAPIVersion(declaration, version)
+	}
+
+	return from, changed, nil
+}
+
+// MigrationResult is the outcome of MigrateDeclarations.
+type MigrationResult struct {
+	// Content is the upgraded stream.
+	Content []byte
+	// Migrated is the number of declarations that were upgraded.
+	Migrated int
+	// Current is the number of declarations that were already of
+	// CurrentAPIVersion.
+	Current int
+}
+
+// MigrateDeclarations upgrades each declaration read from in, of any kind,
+// from a previous apiVersion to CurrentAPIVersion, retaining comments.
+// Declarations of CurrentAPIVersion, and documents containing no data, retain
+// their original content. Declarations whose conversion only changes their
+// apiVersion retain their original content apart from that field, so that
+// their values are never re-encoded.
+func MigrateDeclarations(in io.Reader) (*MigrationResult, error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MigrationResult{}
+	var out bytes.Buffer
+	for i, doc := range splitDocuments[struct{}](b) {
+		out.Write(doc.separator)
+
+		empty, err := isEmptyDocument(doc.raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		if empty {
+			out.Write(doc.raw)
+			continue
+		}
+
+		var root yamlv3.Node
+		if err := yamlv3.Unmarshal(doc.raw, &root); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		from, changed, err := migrateDocument(&root)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+
+		if from == CurrentAPIVersion {
+			out.Write(doc.raw)
+			result.Current++
+			continue
+		}
+
+		if !changed {
+			if content, ok := withAPIVersion(doc.raw, CurrentAPIVersion); ok {
+				out.Write(content)
+				result.Migrated++
+				continue
+			}
+		}
+
+		encoder := yamlv3.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&root); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		result.Migrated++
+	}
+
+	result.Content = out.Bytes()
+	return result, nil
+}
+
+// withAPIVersion returns the YAML document raw with the apiVersion of its
+// declaration set to version, by editing the line holding it, or by adding it
+// before the first field. It returns false if the field cannot be edited
+// without re-encoding the document, such as in flow style declarations.
+func withAPIVersion(raw []byte, version string) ([]byte, bool) {
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(raw, &root); err != nil || len(root.Content) == 0 {
+		return nil, false
+	}
+
+	declaration := root.Content[0]
+	if declaration.Kind != yamlv3.MappingNode || declaration.Style&yamlv3.FlowStyle != 0 || len(declaration.Content) == 0 {
+		return nil, false
+	}
+
+	lines := bytes.SplitAfter(raw, []byte("\n"))
+	if value := mappingValue(declaration, "apiVersion"); value != nil {
+		if value.Line < 1 || value.Line > len(lines) || value.Style&^(yamlv3.SingleQuotedStyle|yamlv3.DoubleQuotedStyle) != 0 {
+			return nil, false
+		}
+
+		token := value.Value
+		if value.Style != 0 {
+			quote := "'"
+			if value.Style == yamlv3.DoubleQuotedStyle {
+				quote = `"`
+			}
+			token = quote + token + quote
+		}
+
+		line := lines[value.Line-1]
+		start := value.Column - 1
+		if start < 0 || start > len(line) || !bytes.HasPrefix(line[start:], []byte(token)) {
+			return nil, false
+		}
+
+		edited := slices.Concat(line[:start], []byte(version), line[start+len(token):])
+		lines[value.Line-1] = edited
+		return bytes.Join(lines, nil), true
+	}
+
+	first := declaration.Content[0]
+	if first.Line < 1 || first.Line > len(lines) {
+		return nil, false
+	}
+
+	field := fmt.Sprintf("%sapiVersion: %s\n", strings.Repeat(" ", first.Column-1), version)
+	lines = slices.Insert(lines, first.Line-1, []byte(field))
+	return bytes.Join(lines, nil), true
+}
+
+// deprecationWarning describes a declaration read as version from, which has
+// since been converted to CurrentAPIVersion.
+func deprecationWarning(from string) string {
+	return fmt.Sprintf("apiVersion %s is deprecated and was converted to %s. Run \"productctl product migrate\" to upgrade the declaration", from, CurrentAPIVersion)
+}
+
+func mappingValue(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// setAPIVersion sets the apiVersion of the declaration mapping, adding it as
+// the first field if it is not already present.
+func setAPIVersion(mapping *yamlv3.Node, version string) {
+	if value := mappingValue(mapping, "apiVersion"); value != nil {
+		value.SetString(version)
+		return
+	}
+
+	key := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "apiVersion"}
+	value := &yamlv3.Node{}
+	value.SetString(version)
+
+	// Comments heading the declaration, such as a schema modeline, remain at
+	// its head.
+	if len(mapping.Content) > 0 {
+		key.HeadComment = mapping.Content[0].HeadComment
+		mapping.Content[0].HeadComment = ""
+	}
+
+	mapping.Content = append([]*yamlv3.Node{key, value}, mapping.Content...)
+}
@@ -0,0 +1,98 @@
+package resource_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/opdev/productctl/internal/resource"
+)
+
+var _ = Describe("Version", func() {
+	const unversioned = `# yaml-language-server: $schema=https://example.com/schema.json
+kind: ProductListing # the kind
+spec:
+  name: first
+`
+
+	It("should convert declarations without an apiVersion, with a warning", func() {
+		warnings := []string{}
+		listing, err := resource.ReadProductListing(
+			bytes.NewBufferString(unversioned),
+			resource.WithWarningHandler(func(w string) { warnings = append(warnings, w) }),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing.APIVersion).To(Equal(resource.CurrentAPIVersion))
+		Expect(listing.Spec.Name).To(Equal("first"))
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0]).To(ContainSubstring("apiVersion " + resource.APIVersionV1Alpha1 + " is deprecated"))
+	})
+
+	It("should not warn about declarations of the current apiVersion", func() {
+		warnings := []string{}
+		listing, err := resource.ReadProductListing(
+			bytes.NewBufferString("apiVersion: "+resource.CurrentAPIVersion+"\nkind: ProductListing\nspec:\n  name: first\n"),
+			resource.WithWarningHandler(func(w string) { warnings = append(warnings, w) }),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(listing.APIVersion).To(Equal(resource.CurrentAPIVersion))
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should reject unknown apiVersions", func() {
+		_, err := resource.ReadProductListing(bytes.NewBufferString("apiVersion: productctl.opdev.io/v99\nkind: ProductListing\n"))
+		Expect(err).To(MatchError(resource.ErrUnknownAPIVersion))
+	})
+
+	It("should write the current apiVersion in new declarations", func() {
+		Expect(resource.NewProductListing().APIVersion).To(Equal(resource.CurrentAPIVersion))
+	})
+
+	When("migrating a stream", func() {
+		It("should upgrade previous versions of any kind in place, retaining comments", func() {
+			current := "apiVersion: " + resource.CurrentAPIVersion + "\nkind: ProductListing\nspec:\n  name:    second\n"
+			component := "kind: Component\nspec:\n  name: third\n"
+
+			result, err := resource.MigrateDeclarations(bytes.NewBufferString(unversioned + "---\n" + current + "---\n# empty\n---\n" + component))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Migrated).To(Equal(2))
+			Expect(result.Current).To(Equal(1))
+			Expect(string(result.Content)).To(Equal(`# yaml-language-server: $schema=https://example.com/schema.json
+apiVersion: ` + resource.CurrentAPIVersion + `
+kind: ProductListing # the kind
+spec:
+  name: first
+---
+` + current + `---
+# empty
+---
+apiVersion: ` + resource.CurrentAPIVersion + `
+kind: Component
+spec:
+  name: third
+`))
+		})
+
+		It("should retain the text of values when only the apiVersion changes", func() {
+			values := "kind: Component\nspec:\n  name: \"010\"\n  helm_chart:\n    ocp_versions: [4.10, '4.9']\n    chart_name:   yes\n"
+			explicit := "apiVersion: '" + resource.APIVersionV1Alpha1 + "' # old\nkind: Component\nspec:\n  name: 4.10\n"
+
+			result, err := resource.MigrateDeclarations(bytes.NewBufferString(values + "---\n" + explicit))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Migrated).To(Equal(2))
+			Expect(string(result.Content)).To(Equal("apiVersion: " + resource.CurrentAPIVersion + "\n" + values +
+				"---\napiVersion: " + resource.CurrentAPIVersion + " # old\nkind: Component\nspec:\n  name: 4.10\n"))
+		})
+
+		It("should prefix warnings with the document they concern", func() {
+			warnings := []string{}
+			_, err := resource.ReadDeclarationStream(
+				bytes.NewBufferString("apiVersion: "+resource.CurrentAPIVersion+"\nkind: ProductListing\n---\n"+unversioned),
+				resource.WithWarningHandler(func(w string) { warnings = append(warnings, w) }),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveExactElements(HavePrefix("document 2: ")))
+		})
+	})
+})